@@ -33,6 +33,7 @@ type AbsorptionSignal struct {
   	- maxAbsRet: 收益率绝对值不应太大（太大更像趋势突破而不是吸筹/派发）
 	- scoreStrong/scoreWeak: 分类阈值
 */
+//metric:name=absorption unit=score cost=O(nlogn) min_points=2
 func (w *SlidingWindow) AbsorptionDistribution(
 	minVF, maxAbsRet, scoreWeak, scoreStrong float64,
 ) (AbsorptionSignal, bool) {
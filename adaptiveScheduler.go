@@ -0,0 +1,138 @@
+package sliding_window
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// CadenceConfig 自适应快照频率的边界
+type CadenceConfig struct {
+	Base time.Duration // 活跃度为 1（与各自基准持平）时的快照间隔，默认 1s
+	Min  time.Duration // 最短间隔（行情剧烈时），默认 100ms
+	Max  time.Duration // 最长间隔（行情清淡时），默认 10s
+}
+
+func (c CadenceConfig) withDefaults() CadenceConfig {
+	if c.Base <= 0 {
+		c.Base = time.Second
+	}
+	if c.Min <= 0 {
+		c.Min = 100 * time.Millisecond
+	}
+	if c.Max <= 0 {
+		c.Max = 10 * time.Second
+	}
+	c.Min = min(c.Min, c.Base)
+	c.Max = max(c.Max, c.Base)
+	return c
+}
+
+// interval 由活跃度得到下一次快照的间隔：Base / activity，夹在 [Min, Max]
+func (c CadenceConfig) interval(activity float64) time.Duration {
+	if activity <= 0 || math.IsNaN(activity) {
+		return c.Max
+	}
+	d := time.Duration(float64(c.Base) / activity)
+	return min(max(d, c.Min), c.Max)
+}
+
+// AdaptiveScheduler 按行情活跃度调整频率的快照调度器：成交到达率或 realized vol
+// 相对各自基准放大时加快快照，清淡时放慢，间隔限制在 CadenceConfig 的上下界内。
+// 活跃度取全部窗口中的最大值，任何一个品种异动都会让整体加速；两次快照之间没有新数据的窗口活跃度为 0。
+// 输出与 AlignedScheduler 相同（Boundary 为实际触发时刻），可以直接交给 BatchPublisher.EmitTick
+type AdaptiveScheduler struct {
+	m   *Manager
+	cfg CadenceConfig
+	fn  func(AlignedTick)
+
+	interval atomic.Int64 // 当前间隔（纳秒）
+	activity atomic.Uint64
+	seen     map[string]uint64 // 上一轮各窗口的版本号，只在调度协程中访问
+	run      runner
+}
+
+// NewAdaptiveScheduler fn 在调度协程中同步调用
+func NewAdaptiveScheduler(m *Manager, cfg CadenceConfig, fn func(AlignedTick)) *AdaptiveScheduler {
+	s := &AdaptiveScheduler{m: m, cfg: cfg.withDefaults(), fn: fn, seen: make(map[string]uint64)}
+	s.interval.Store(int64(s.cfg.Base))
+	return s
+}
+
+// Interval 当前快照间隔
+func (s *AdaptiveScheduler) Interval() time.Duration { return time.Duration(s.interval.Load()) }
+
+// Activity 最近一次快照时的活跃度（全部窗口的最大值）
+func (s *AdaptiveScheduler) Activity() float64 { return math.Float64frombits(s.activity.Load()) }
+
+// Start 在后台运行 Run(ctx)
+func (s *AdaptiveScheduler) Start(ctx context.Context) error { return s.run.start(ctx, s.Run) }
+
+// Close 停止调度并等待当前回调返回
+func (s *AdaptiveScheduler) Close() error { return s.run.close() }
+
+// Run 阻塞运行直到 ctx 结束
+func (s *AdaptiveScheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(s.Interval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		timer.Reset(s.tick(s.m.now()))
+	}
+}
+
+// tick 做一轮快照并返回下一次的间隔
+func (s *AdaptiveScheduler) tick(now time.Time) time.Duration {
+	targets := s.m.resolve(nil)
+	snaps := make(map[string]*Snapshot, len(targets))
+	ms := now.UnixMilli()
+
+	var activity float64
+	seen := make(map[string]uint64, len(targets))
+	for _, t := range targets {
+		v := t.w.Version()
+		snap := t.w.Snapshot()
+		if snap == nil {
+			continue
+		}
+		snap.BatchTs = ms
+		snaps[t.symbol] = snap
+
+		if last, ok := s.seen[t.symbol]; !ok || last != v {
+			activity = max(activity, t.w.activity(snap, now))
+		}
+		seen[t.symbol] = v
+	}
+	s.seen = seen // 已移除的 symbol 随之丢弃
+
+	d := s.cfg.interval(activity)
+	s.interval.Store(int64(d))
+	s.activity.Store(math.Float64bits(activity))
+
+	s.fn(AlignedTick{Boundary: now, Snaps: snaps})
+	return d
+}
+
+// activity 窗口活跃度：最近一个完整秒的成交笔数 / 到达率 EMA，与快照 realized vol / 基准，取较大者。
+// 超过 2 秒没有成交时到达率按 0 计
+func (w *SlidingWindow) activity(snap *Snapshot, now time.Time) float64 {
+	var rate float64
+	w.mu.RLock()
+	a := &w.arrival
+	if a.ema.Initialized && a.ema.Value > 0 && now.Sub(time.Unix(a.sec, 0)) <= 2*time.Second {
+		rate = float64(a.lastCount) / a.ema.Value
+	}
+	w.mu.RUnlock()
+
+	var vol float64
+	if base, ok := w.VolRegimeBaseline(); ok && base > 0 {
+		vol = snap.Volatility / base
+	}
+	return max(rate, vol)
+}
@@ -0,0 +1,59 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCadenceInterval(t *testing.T) {
+	c := CadenceConfig{Base: time.Second, Min: 100 * time.Millisecond, Max: 5 * time.Second}.withDefaults()
+	cases := []struct {
+		activity float64
+		want     time.Duration
+	}{
+		{0, 5 * time.Second},
+		{0.5, 2 * time.Second},
+		{1, time.Second},
+		{4, 250 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tc := range cases {
+		if got := c.interval(tc.activity); got != tc.want {
+			t.Fatalf("activity %v: interval %v, want %v", tc.activity, got, tc.want)
+		}
+	}
+}
+
+func TestAdaptiveScheduler_SpeedsUpOnBurst(t *testing.T) {
+	m := NewManager(func(string) *SlidingWindow { return NewSlidingWindow(time.Minute, 4096, 0.1) })
+	w := m.GetOrCreate("BTC")
+
+	var got []AlignedTick
+	s := NewAdaptiveScheduler(m, CadenceConfig{}, func(tk AlignedTick) { got = append(got, tk) })
+
+	// 之前每秒 2 笔，最近一秒 20 笔
+	now := time.Now().Truncate(time.Second)
+	start := now.Add(-30 * time.Second)
+	for sec := 0; sec < 30; sec++ {
+		n := 2
+		if sec == 28 {
+			n = 20
+		}
+		for k := 0; k < n; k++ {
+			w.AddWindowPoint(SideBuy, 100+float64(k%2), 1, start.Add(time.Duration(sec)*time.Second+time.Duration(k)*time.Millisecond))
+		}
+	}
+
+	d := s.tick(now)
+	if s.Activity() < 3 || d >= time.Second || d < 100*time.Millisecond {
+		t.Fatalf("activity %v interval %v", s.Activity(), d)
+	}
+	if len(got) != 1 || got[0].Snaps["BTC"] == nil || got[0].Snaps["BTC"].BatchTs != now.UnixMilli() {
+		t.Fatalf("ticks: %+v", got)
+	}
+
+	// 长时间无成交：放慢到上限
+	if d := s.tick(now.Add(time.Minute)); d != 10*time.Second {
+		t.Fatalf("quiet interval %v", d)
+	}
+}
@@ -5,75 +5,165 @@ import "time"
 // add 无锁批量添加
 // add 无锁批量添加（假设外层已经 w.mu.Lock 住）
 func (w *SlidingWindow) add(pts ...WindowPoint) {
+	if w.negative.policy != NegativeVolumeClamp {
+		pts = w.negativeVolumeUnlocked(pts)
+	}
+	w.addPoints(pts, nil)
+}
+
+// addPoints 把已过滤的点交给乱序缓冲或直接写入（要求持有写锁）；
+// seqs 为 nil 或与 pts 一一对应，是 DuplicateSeq 策略下的成交序号
+func (w *SlidingWindow) addPoints(pts []WindowPoint, seqs []uint64) {
+	if w.reorder.lateness > 0 {
+		pts, seqs = w.reorderUnlocked(pts, seqs)
+	}
+	w.addOrdered(pts, seqs)
+}
+
+// addOrdered 跳过乱序缓冲直接写入一批点（要求持有写锁），seqs 同 addPoints
+func (w *SlidingWindow) addOrdered(pts []WindowPoint, seqs []uint64) {
 	if len(pts) == 0 {
 		return
 	}
 
-	lastTs := pts[len(pts)-1].Ts
-	threshold := lastTs.Add(-w.duration)
+	probe := w.beginSlowProbeUnlocked()
+	defer w.endSlowProbeUnlocked(probe, len(pts))
+
+	lastTs := w.evictionNowUnlocked(pts[len(pts)-1])
+	threshold := w.thresholdUnlocked(lastTs)
 
 	for i := range pts {
-		pt := pts[i]
-		if !pt.Ts.After(threshold) {
+		if !w.admitUnlocked(pts[i], threshold) {
 			continue
 		}
+		var seq uint64
+		if seqs != nil {
+			seq = seqs[i]
+		}
+		w.insertUnlocked(pts[i], seq, threshold)
+	}
 
-		if w.size == 0 {
-			w.buf[0] = pt
-			w.start = 0
-			w.size = 1
-
-			// 新增统计
-			w.applyAddPointUnlocked(pt)
-
-		} else if w.size < len(w.buf) {
-			idx := (w.start + w.size) % len(w.buf)
-			w.buf[idx] = pt
-			w.size++
-
-			w.applyAddPointUnlocked(pt)
+	w.finishAddUnlocked(threshold)
+}
 
-		} else {
-			// 覆盖头部
-			idx := w.start
-			old := w.buf[idx]
+// insertUnlocked 写入一个已通过准入的点：过期拒收、环满覆盖、追加并做时序统计（要求持有写锁）。
+// seq 为该点的成交序号，没有时为 0
+func (w *SlidingWindow) insertUnlocked(pt WindowPoint, seq uint64, threshold time.Time) {
+	if !pt.Ts.After(threshold) {
+		w.counters.rejects.Add(1)
+		return
+	}
 
-			// 先减旧点统计
-			w.applyRemovePointUnlocked(old)
+	w.observeSizeUnlocked(pt)
 
-			// 覆盖
-			w.buf[idx] = pt
-			w.start = (w.start + 1) % len(w.buf)
+	if w.mergeDuplicateUnlocked(pt) || w.mergeRunUnlocked(pt) {
+		w.observeTradeUnlocked(pt)
+		return
+	}
 
-			// 再加新点统计
-			w.applyAddPointUnlocked(pt)
-		}
+	if w.countFullUnlocked() {
+		// 按点数出窗：保留最近 maxPoints 个点
+		w.evictHeadUnlocked()
+	} else if w.fullUnlocked() {
+		// 环满：覆盖头部（先减旧点统计）
+		w.counters.overflows.Add(1)
+		w.evictHeadUnlocked()
 	}
+	w.appendUnlocked(pt)
+	w.orderBySeqUnlocked(seq)
+	w.observeTradeUnlocked(pt)
+}
 
+// finishAddUnlocked 一批写入后的收尾：过期清理、high/low 补算、缓存刷新、发布（要求持有写锁）
+func (w *SlidingWindow) finishAddUnlocked(threshold time.Time) {
 	// trim：把“窗口内残留过期点”清掉（你原本就有）
 	w.trimExpiredUnlocked(threshold) // ⚠️ 这里也要同步做 applyRemove（见下）
+	w.trimCountedUnlocked()
+	w.compactUnlocked()
+	w.forgetSkewUnlocked()
 
 	// high/low 若 dirty，补一次
 	w.recomputeHighLowIfDirtyUnlocked()
 
 	// 你原本的缓存刷新
 	w.refreshVolumeCachesUnlocked()
+	w.updateImbalanceEMAsUnlocked()
+
+	w.bumpVersionUnlocked()
+}
+
+// observeTradeUnlocked 只在成交首次进入窗口时执行的时序统计（EMA、到达率等），
+// 与 applyAddPointUnlocked 的可加减统计分开，重建统计时不会重复计入（要求持有写锁）
+func (w *SlidingWindow) observeTradeUnlocked(pt WindowPoint) {
+	w.observeLifetimeUnlocked(pt)
+	w.observeAnchoredUnlocked(pt)
+	if w.isQuoteUnlocked(pt) {
+		// 报价更新只影响价格类统计
+		if w.tracked.Has(TrackBlend) {
+			w.observeBlendUnlocked(pt)
+		}
+		w.observeOpeningRangeUnlocked(pt)
+		return
+	}
+	if w.tracked.Has(TrackVolumeEMA) {
+		w.updateVolumeEMAUnlocked(pt)
+	}
+	if w.tracked.Has(TrackArrival) {
+		w.arrival.observe(pt.Ts)
+	}
+	if w.tracked.Has(TrackBlend) {
+		w.observeBlendUnlocked(pt)
+	}
+	w.observeOpeningRangeUnlocked(pt)
+}
+
+// appendUnlocked 在尾部追加一个点并累加统计（要求持有写锁，且环未满）
+func (w *SlidingWindow) appendUnlocked(pt WindowPoint) {
+	if w.tracked.Has(TrackTWAP) {
+		w.twapAppendUnlocked(pt)
+	}
+	w.pushUnlocked(pt)
+	w.runTailTs = time.Time{}
+
+	w.applyAddPointUnlocked(pt)
+}
+
+// evictHeadUnlocked 移除最旧的点并扣减统计，出窗点交给归档与出窗回调（要求持有写锁，且 size > 0）
+func (w *SlidingWindow) evictHeadUnlocked() WindowPoint {
+	old := w.atUnlocked(0)
+	w.counters.evictions.Add(1)
+	w.archiveUnlocked(old)
+	w.observeEvictUnlocked(old)
+
+	w.dropHeadUnlocked()
+	return old
+}
+
+// dropHeadUnlocked 移除最旧的点并扣减统计，不归档、不触发出窗回调；
+// 用于 Backfill 重建窗口等并非真实出窗的场景（要求持有写锁，且 size > 0）
+func (w *SlidingWindow) dropHeadUnlocked() {
+	if w.tracked.Has(TrackTWAP) {
+		w.twapEvictUnlocked()
+	}
+	w.applyRemovePointUnlocked(w.atUnlocked(0))
+	w.popHeadUnlocked()
 }
 
 // trimExpiredUnlocked：移除所有 Ts <= threshold 的点（保持窗口为 (threshold, +inf]）
 func (w *SlidingWindow) trimExpiredUnlocked(threshold time.Time) {
 	for w.size > 0 {
-		head := w.buf[w.start]
+		head := w.atUnlocked(0)
 		if head.Ts.After(threshold) {
 			break
 		}
 		// 移除 head
-		w.applyRemovePointUnlocked(head)
-
-		w.start = (w.start + 1) % len(w.buf)
-		w.size--
+		w.evictHeadUnlocked()
 	}
 
+	w.trimMarkersUnlocked(threshold)
+	w.trimBlendUnlocked(threshold)
+	w.trimOwnFillsUnlocked(threshold)
+
 	if w.size == 0 {
 		// 清空 latest/high/low 的合理处理（可选）
 		w.LatestPrice.Store(0)
@@ -82,8 +172,7 @@ func (w *SlidingWindow) trimExpiredUnlocked(threshold time.Time) {
 		w.LowestPrice.Store(0)
 	} else {
 		// latest 也可在 trim 后重新设（可选）
-		lastIdx := (w.start + w.size - 1) % len(w.buf)
-		w.LatestPrice.Store(w.buf[lastIdx].Price.Int64())
+		w.LatestPrice.Store(w.lastUnlocked().Price.Int64())
 	}
 }
 
@@ -95,8 +184,15 @@ func (w *SlidingWindow) refreshVolumeCachesUnlocked() {
 	}
 
 	// === 平均每点成交量（ticks） ===
-	// sumVolume 是 QtyLoz（内部是 ticks）
-	avgTicks := int64(w.sumVolume) / int64(w.size)
+	// sumVolume 是 QtyLoz（内部是 ticks）；分母按成交笔数而不是点数：游程、同时间戳合并、
+	// 压缩后的点包含多笔成交，与按笔更新的成交量 EMA 口径一致；零量报价不计入
+	n := w.nTrades.Load()
+	if n <= 0 {
+		w.avgVolPerPoint.Store(0)
+		w.volPerSecond.Store(0)
+		return
+	}
+	avgTicks := int64(w.sumVolume) / n
 	w.avgVolPerPoint.Store(avgTicks)
 
 	// === 每秒成交量（ticks / second） ===
@@ -108,7 +204,8 @@ func (w *SlidingWindow) refreshVolumeCachesUnlocked() {
 	oldest := w.atUnlocked(0)
 	newest := w.lastUnlocked()
 
-	sec := newest.Ts.Sub(oldest.Ts).Seconds()
+	// 按日历剔除休市/维护时段
+	sec := w.openDurationUnlocked(oldest.Ts, newest.Ts).Seconds()
 	if sec <= 0 {
 		w.volPerSecond.Store(0)
 		return
@@ -121,8 +218,8 @@ func (w *SlidingWindow) refreshVolumeCachesUnlocked() {
 
 // Add 添加一个点并自动清理超出时间窗口的旧点（写锁）
 func (w *SlidingWindow) Add(p ...WindowPoint) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
+	w.lockWrite()
+	defer w.unlockWrite()
 
 	w.add(p...)
 	return
@@ -130,9 +227,8 @@ func (w *SlidingWindow) Add(p ...WindowPoint) {
 
 // AddWindowPoint 添加一个点并自动清理超出时间窗口的旧点（写锁）
 func (w *SlidingWindow) AddWindowPoint(side Side, price, size float64, ts time.Time) {
-
-	w.mu.Lock()
-	defer w.mu.Unlock()
+	w.lockWrite()
+	defer w.unlockWrite()
 
 	w.add(WindowPoint{
 		Ts:     ts,
@@ -143,10 +239,26 @@ func (w *SlidingWindow) AddWindowPoint(side Side, price, size float64, ts time.T
 	return
 }
 
+// AddValues 热路径版本：直接传原始数值（ts 为毫秒时间戳），调用方无需构造 WindowPoint，
+// 点在栈上组装后交给 add，不产生堆分配（写锁）
+func (w *SlidingWindow) AddValues(ts int64, price, vol float64, side Side) {
+	pt := WindowPoint{
+		Ts:     time.UnixMilli(ts),
+		Price:  NewQtyLoz(price, w.priceScale),
+		Volume: NewQtyLoz(vol, w.volumeScale),
+		Side:   side,
+	}
+
+	w.lockWrite()
+	w.add(pt)
+	w.unlockWrite()
+}
+
 func (w *SlidingWindow) recomputeHighLowIfDirtyUnlocked() {
 	if !w.hiLoDirty {
 		return
 	}
+	w.counters.recomputes.Add(1)
 	if w.size == 0 {
 		w.HighestPrice.Store(0)
 		w.LowestPrice.Store(0)
@@ -154,13 +266,26 @@ func (w *SlidingWindow) recomputeHighLowIfDirtyUnlocked() {
 		return
 	}
 
-	first := w.buf[w.start]
-	hi := first.Price.Int64()
-	lo := hi
+	var hi, lo int64
+	if w.seg != nil {
+		hi, lo = w.seg.priceRange(w.size)
+	} else {
+		// 环形数组直接遍历两段连续切片，热路径上不经过 atUnlocked 的分支与取模
+		head, tail := w.ring.spans(w.size)
+		hi, lo = head[0].Price.Int64(), head[0].Price.Int64()
+		hi, lo = priceRangeOf(head, hi, lo)
+		hi, lo = priceRangeOf(tail, hi, lo)
+	}
+
+	w.HighestPrice.Store(hi)
+	w.LowestPrice.Store(lo)
+	w.hiLoDirty = false
+}
 
-	for i := 1; i < w.size; i++ {
-		idx := (w.start + i) % len(w.buf)
-		px := w.buf[idx].Price.Int64()
+// priceRangeOf 用 pts 的价格扩展 [lo, hi]（ticks）
+func priceRangeOf(pts []WindowPoint, hi, lo int64) (int64, int64) {
+	for i := range pts {
+		px := pts[i].Price.Int64()
 		if px > hi {
 			hi = px
 		}
@@ -168,18 +293,12 @@ func (w *SlidingWindow) recomputeHighLowIfDirtyUnlocked() {
 			lo = px
 		}
 	}
-
-	w.HighestPrice.Store(hi)
-	w.LowestPrice.Store(lo)
-	w.hiLoDirty = false
+	return hi, lo
 }
 
 func (w *SlidingWindow) applyAddPointUnlocked(pt WindowPoint) {
 	// === 原有 sumVolume / EMA ===
 	w.sumVolume += pt.Volume
-	if int64(pt.Volume) > 0 {
-		w.ema.Update(float64(pt.Volume) / float64(w.volumeScale))
-	}
 
 	// === 新增：ticks 统计 ===
 	px := pt.Price.Int64()
@@ -189,20 +308,38 @@ func (w *SlidingWindow) applyAddPointUnlocked(pt WindowPoint) {
 	} // 防御
 
 	// trades 计数（你如果想 Unknown side 也算一次 trade，就放这里）
-	w.nTrades.Add(1)
+	quote := w.isQuoteUnlocked(pt)
+	if !quote {
+		w.nTrades.Add(pt.Trades())
+	}
+	if w.tracked.Has(TrackSizeSketch) && !quote {
+		w.sizes.apply(pt.Volume.Int64(), 1)
+	}
+	w.applySizeBucketUnlocked(pt, 1)
+	w.applyPriceHistUnlocked(pt, 1)
 
 	// SumV / SumPV（注意：px*v 可能溢出，见后面说明）
 	w.SumV.Add(v)
 	w.SumPV.Add(px * v)
 
+	var notional float64
+	if w.tracked.Has(TrackNotional) {
+		notional = pt.Price.Float(w.priceScale) * float64(v) / float64(w.volumeScale)
+	}
+	w.sumNotional.add(notional)
+
 	// buy/sell vol
 	switch pt.Side {
 	case SideBuy:
 		w.buyVol.Add(v)
+		w.buyNotional.add(notional)
 	case SideSell:
 		w.sellVol.Add(v)
+		w.sellNotional.add(notional)
 	default:
-		return
+		if !quote {
+			return
+		}
 	}
 
 	// latest
@@ -241,17 +378,35 @@ func (w *SlidingWindow) applyRemovePointUnlocked(pt WindowPoint) {
 		v = 0
 	}
 
-	w.nTrades.Add(-1)
+	quote := w.isQuoteUnlocked(pt)
+	if !quote {
+		w.nTrades.Add(-pt.Trades())
+	}
+	if w.tracked.Has(TrackSizeSketch) && !quote {
+		w.sizes.apply(pt.Volume.Int64(), -1)
+	}
+	w.applySizeBucketUnlocked(pt, -1)
+	w.applyPriceHistUnlocked(pt, -1)
 	w.SumV.Add(-v)
 	w.SumPV.Add(-(px * v))
 
+	var notional float64
+	if w.tracked.Has(TrackNotional) {
+		notional = pt.Price.Float(w.priceScale) * float64(v) / float64(w.volumeScale)
+	}
+	w.sumNotional.add(-notional)
+
 	switch pt.Side {
 	case SideBuy:
 		w.buyVol.Add(-v)
+		w.buyNotional.add(-notional)
 	case SideSell:
 		w.sellVol.Add(-v)
+		w.sellNotional.add(-notional)
 	default:
-		return
+		if !quote {
+			return
+		}
 	}
 
 	// 如果删掉的点“可能是最高/最低”，标记 dirty，稍后必要时重算
@@ -0,0 +1,110 @@
+package sliding_window
+
+import "math"
+
+// aggressionRun 方向持续性统计的最近成交笔数
+const aggressionRun = 20
+
+// AggressionInputs 主动性评分的输入，均在窗口的同一个读锁视图内取得
+type AggressionInputs struct {
+	SizePercentile float64 // 最近一笔成交量在窗口分布中的百分位 [0,1]
+	Burst          float64 // 最近一个完整秒的笔数 / 到达率 EMA，1 为常态
+	Persistence    float64 // 最近 20 笔有方向的成交中与最新一笔同向的比例 [0,1]
+	Side           Side    // 最新一笔有方向成交的方向
+}
+
+// AggressionModel 把输入合成为 [0,1] 的主动性评分，可由使用方替换
+type AggressionModel interface {
+	Score(in AggressionInputs) float64
+}
+
+// AggressionFunc 函数形式的 AggressionModel
+type AggressionFunc func(in AggressionInputs) float64
+
+func (f AggressionFunc) Score(in AggressionInputs) float64 { return f(in) }
+
+// WeightedAggression 默认模型：三项各自映射到 [0,1] 后加权平均。
+// 到达率按 1 - exp(-(Burst-1)) 映射，常态及以下为 0；持续性按 (p-0.5)*2 映射，买卖交替为 0
+type WeightedAggression struct {
+	Size        float64
+	Burst       float64
+	Persistence float64
+}
+
+// DefaultAggressionModel 成交量、速度、方向持续性等权
+var DefaultAggressionModel = WeightedAggression{Size: 1, Burst: 1, Persistence: 1}
+
+func (m WeightedAggression) Score(in AggressionInputs) float64 {
+	total := m.Size + m.Burst + m.Persistence
+	if total <= 0 {
+		return 0
+	}
+	burst := 1 - math.Exp(-max(in.Burst-1, 0))
+	persist := max(in.Persistence-0.5, 0) * 2
+	return (m.Size*in.SizePercentile + m.Burst*burst + m.Persistence*persist) / total
+}
+
+// SetAggressionModel 替换 AggressionScore 使用的模型（写锁），nil 恢复默认模型
+func (w *SlidingWindow) SetAggressionModel(m AggressionModel) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.aggression = m
+}
+
+// AggressionScore 主动性评分 [0,1]（读锁）：大单、成交加速、同向连续成交同时出现时接近 1。
+// 方向见 AggressionDetail
+//
+//metric:name=aggression_score unit=score cost=O(1) min_points=2
+func (w *SlidingWindow) AggressionScore() (float64, bool) {
+	s, _, ok := w.AggressionDetail()
+	return s, ok
+}
+
+// AggressionDetail 主动性评分及其输入（读锁）
+func (w *SlidingWindow) AggressionDetail() (float64, AggressionInputs, bool) {
+	var in AggressionInputs
+	if !w.IsReady() {
+		return 0, in, false
+	}
+
+	w.mu.RLock()
+	if w.size < 2 || !w.lastPctOK {
+		w.mu.RUnlock()
+		return 0, in, false
+	}
+	in.SizePercentile = w.lastPct
+	if a := &w.arrival; a.ema.Initialized && a.ema.Value > 0 {
+		in.Burst = float64(a.lastCount) / a.ema.Value
+	}
+
+	var n, same int
+	for i := w.size - 1; i >= 0 && n < aggressionRun; i-- {
+		pt := w.atUnlocked(i)
+		if pt.Side == SideUnknown || w.isQuoteUnlocked(pt) {
+			continue
+		}
+		if n == 0 {
+			in.Side = pt.Side
+		}
+		n++
+		if pt.Side == in.Side {
+			same++
+		}
+	}
+	if n > 0 {
+		in.Persistence = float64(same) / float64(n)
+	}
+
+	m := w.aggression
+	w.mu.RUnlock()
+
+	if m == nil {
+		m = DefaultAggressionModel
+	}
+	s := m.Score(in)
+	if math.IsNaN(s) {
+		return 0, in, false
+	}
+	return min(max(s, 0), 1), in, true
+}
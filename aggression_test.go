@@ -0,0 +1,40 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAggressionScore(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 40 秒买卖交替的小单，最后一秒连续 10 笔大额买单
+	for s := 0; s < 40; s++ {
+		side := SideBuy
+		if s%2 == 1 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100, 1, t0.Add(time.Duration(s)*time.Second))
+	}
+	calm, in, ok := w.AggressionDetail()
+	if !ok || in.Persistence != 0.5 || calm > 0.4 {
+		t.Fatalf("calm: %v %+v", calm, in)
+	}
+
+	for k := 0; k < 10; k++ {
+		w.AddWindowPoint(SideBuy, 100, 5, t0.Add(40*time.Second+time.Duration(k)*time.Millisecond))
+	}
+	w.AddWindowPoint(SideBuy, 100, 8, t0.Add(41*time.Second)) // 结算上一秒的到达计数
+	hot, in, ok := w.AggressionDetail()
+	if !ok || in.Side != SideBuy || in.SizePercentile < 0.9 || in.Burst < 3 || hot < 0.7 {
+		t.Fatalf("hot: %v %+v", hot, in)
+	}
+
+	// 自定义模型：只看方向持续性
+	w.SetAggressionModel(AggressionFunc(func(in AggressionInputs) float64 { return in.Persistence }))
+	if s, _ := w.AggressionScore(); math.Abs(s-in.Persistence) > 1e-12 {
+		t.Fatalf("custom model score = %v", s)
+	}
+}
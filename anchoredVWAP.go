@@ -0,0 +1,106 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// anchoredVWAP 从锚点起累计的 VWAP，独立于窗口长度（出窗不扣减）
+type anchoredVWAP struct {
+	active bool
+	anchor time.Time
+	sumPV  compensated // Σ price·volume（真实值）
+	sumV   compensated
+}
+
+// AnchorVWAP 把锚定 VWAP 的起点设为 ts（写锁），之后 Ts >= ts 的成交计入；
+// 窗口内已有的 Ts >= ts 的成交立即计入，已出窗的部分无法补回。MarkSessionOpen 会以开盘时间重新锚定
+func (w *SlidingWindow) AnchorVWAP(ts time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.anchorVWAPUnlocked(ts)
+}
+
+func (w *SlidingWindow) anchorVWAPUnlocked(ts time.Time) {
+	w.anchored = anchoredVWAP{active: true, anchor: ts}
+	for i := w.searchTsUnlocked(ts); i < w.size; i++ {
+		w.observeAnchoredUnlocked(w.atUnlocked(i))
+	}
+}
+
+// observeAnchoredUnlocked 成交进窗时累计锚定 VWAP（要求持有写锁）
+func (w *SlidingWindow) observeAnchoredUnlocked(pt WindowPoint) {
+	a := &w.anchored
+	if !a.active || pt.Ts.Before(a.anchor) || w.isQuoteUnlocked(pt) {
+		return
+	}
+	v := max(pt.Volume.Float(w.volumeScale), 0)
+	a.sumPV.add(pt.Price.Float(w.priceScale) * v)
+	a.sumV.add(v)
+}
+
+// AnchoredVWAP 自锚点（AnchorVWAP / MarkSessionOpen）以来的 VWAP（读锁），未设锚点或尚无成交时返回 false
+//
+//metric:name=anchored_vwap unit=price cost=O(1) min_points=1 requires=anchor
+func (w *SlidingWindow) AnchoredVWAP() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.anchoredVWAPUnlocked()
+}
+
+func (w *SlidingWindow) anchoredVWAPUnlocked() (float64, bool) {
+	a := &w.anchored
+	sumV := a.sumV.value()
+	if !a.active || sumV <= 0 {
+		return 0, false
+	}
+	return a.sumPV.value() / sumV, true
+}
+
+// VWAPDivergence (窗口 VWAP - 锚定 VWAP) / 窗口内成交量加权价格标准差（读锁）。
+// 正值表示近期成交重心高于时段均价，常用作日内均值回归的输入
+//
+//metric:name=vwap_divergence unit=sigma cost=O(n) min_points=2 requires=anchor
+func (w *SlidingWindow) VWAPDivergence() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	anchored, ok := w.anchoredVWAPUnlocked()
+	if !ok || w.size < 2 {
+		return 0, false
+	}
+
+	var sumPV, sumV float64
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		v := pt.Volume.Float(w.volumeScale)
+		sumPV += pt.Price.Float(w.priceScale) * v
+		sumV += v
+	}
+	if sumV <= 0 {
+		return 0, false
+	}
+	vwap := sumPV / sumV
+
+	var sumVar float64
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		d := pt.Price.Float(w.priceScale) - vwap
+		sumVar += d * d * pt.Volume.Float(w.volumeScale)
+	}
+	sigma := math.Sqrt(sumVar / sumV)
+	if sigma <= 1e-12 {
+		return 0, false
+	}
+	return (vwap - anchored) / sigma, true
+}
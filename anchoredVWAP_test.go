@@ -0,0 +1,41 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnchoredVWAPDivergence(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	if _, ok := w.AnchoredVWAP(); ok {
+		t.Fatal("anchored vwap without anchor")
+	}
+	w.MarkSessionOpen(t0, time.Minute)
+
+	// 前 30 秒在 100 附近成交，之后抬升到 110 附近；10 秒窗口只看到后半段
+	for i := 0; i < 60; i++ {
+		px := 100 + float64(i%3)
+		if i >= 30 {
+			px += 10
+		}
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	anchored, ok := w.AnchoredVWAP()
+	if !ok || math.Abs(anchored-106) > 1e-9 {
+		t.Fatalf("anchored vwap = %v, %v", anchored, ok)
+	}
+	div, ok := w.VWAPDivergence()
+	if !ok || div < 3 {
+		t.Fatalf("divergence = %v, %v", div, ok)
+	}
+
+	// 重新锚定到窗口内：只计入窗口里已有的成交
+	w.AnchorVWAP(t0.Add(57 * time.Second))
+	if a, _ := w.AnchoredVWAP(); math.Abs(a-111) > 1e-9 {
+		t.Fatalf("re-anchored vwap = %v", a)
+	}
+}
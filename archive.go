@@ -0,0 +1,267 @@
+package sliding_window
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Candle 一根已收盘的 OHLC K 线（真实值）
+type Candle struct {
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Open       float64           `json:"open"`
+	High       float64           `json:"high"`
+	Low        float64           `json:"low"`
+	Close      float64           `json:"close"`
+	Volume     float64           `json:"volume"`
+	BuyVolume  float64           `json:"buy_volume"`
+	SellVolume float64           `json:"sell_volume"`
+	Trades     int               `json:"trades"`
+	Tags       map[string]string `json:"tags,omitempty"` // 窗口标签（只读，见 WithTags）
+}
+
+// Archiver 接收被淘汰数据聚合成的 K 线，用于落地到长期存储（SQLite、ClickHouse 等）。
+// OnBarClose 在写锁释放后由写入方协程按收盘顺序调用，不阻塞其他读写，但会拖慢该次写入的返回；
+// 慢存储请用 BatchArchiver 包装。
+type Archiver interface {
+	OnBarClose(Candle)
+}
+
+// candleBuilder 按淘汰顺序（即时间顺序）把点聚合为 K 线
+type candleBuilder struct {
+	interval time.Duration
+	open     bool
+	cur      Candle
+}
+
+// push 加入一个点；若点落在新的周期，先返回已收盘的旧 K 线
+func (b *candleBuilder) push(pt WindowPoint, priceScale, volumeScale QtyScale) (Candle, bool) {
+	start := pt.Ts.Truncate(b.interval)
+	px := pt.Price.Float(priceScale)
+	v := pt.Volume.Float(volumeScale)
+
+	var closed Candle
+	done := false
+	if b.open && !start.Equal(b.cur.Start) {
+		closed, done = b.cur, true
+		b.open = false
+	}
+	if !b.open {
+		b.cur = Candle{Start: start, End: start.Add(b.interval), Open: px, High: px, Low: px}
+		b.open = true
+	}
+
+	c := &b.cur
+	if px > c.High {
+		c.High = px
+	}
+	if px < c.Low {
+		c.Low = px
+	}
+	c.Close = px
+	c.Volume += v
+	switch pt.Side {
+	case SideBuy:
+		c.BuyVolume += v
+	case SideSell:
+		c.SellVolume += v
+	}
+	c.Trades += int(pt.Trades())
+	return closed, done
+}
+
+// SetArchiver 设置淘汰数据的归档目标（写锁）：出窗的点按 interval 聚合成 K 线，
+// 周期内最后一个点出窗后的下一次淘汰时交给 a.OnBarClose。a 为 nil 关闭归档。
+func (w *SlidingWindow) SetArchiver(a Archiver, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.archiver = a
+	w.candles = candleBuilder{interval: interval}
+	w.pendingCandles = nil
+}
+
+// FlushArchive 立即收盘当前未完成的 K 线并交给 Archiver（写锁），用于停机前落盘
+func (w *SlidingWindow) FlushArchive() {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	if w.archiver == nil || !w.candles.open {
+		return
+	}
+	w.candles.open = false
+	cur := w.candles.cur
+	cur.Tags = w.tags
+	w.pendingCandles = append(w.pendingCandles, cur)
+}
+
+// archiveUnlocked 出窗点进入 K 线聚合（要求持有写锁）
+func (w *SlidingWindow) archiveUnlocked(pt WindowPoint) {
+	if w.archiver == nil {
+		return
+	}
+	if c, ok := w.candles.push(pt, w.priceScale, w.volumeScale); ok {
+		c.Tags = w.tags
+		w.pendingCandles = append(w.pendingCandles, c)
+	}
+}
+
+// takeCandlesUnlocked 取走待派发的已收盘 K 线（要求持有写锁）
+func (w *SlidingWindow) takeCandlesUnlocked() ([]Candle, Archiver) {
+	if len(w.pendingCandles) == 0 {
+		return nil, nil
+	}
+	cs := w.pendingCandles
+	w.pendingCandles = nil
+	return cs, w.archiver
+}
+
+// ErrArchiverNotStarted BatchArchiver 从未 Start 就被 Close：队列中的 K 线已同步写入，
+// 但在此之前队列满的部分已被丢弃
+var ErrArchiverNotStarted = errors.New("sliding_window: batch archiver closed without Start")
+
+// CandleStore 长期存储的批量写入接口
+type CandleStore interface {
+	WriteCandles([]Candle) error
+}
+
+// BatchArchiver 异步批量归档：OnBarClose 只入队不阻塞，后台协程按条数或时间间隔批量写入。
+// 需要 Start 后才开始写入（或挂载到窗口上随窗口 Start）；从未 Start 时 Close 会同步写完队列，
+// 并返回 ErrArchiverNotStarted 提示漏了 Start（期间队列满丢弃的 K 线见 Dropped）。
+// 队列满时丢弃并计数，写入失败调用 OnError（可选），Close 返回最后一次写入错误。
+type BatchArchiver struct {
+	store     CandleStore
+	batchSize int
+	every     time.Duration
+	queue     chan Candle
+	closed    atomic.Bool
+	dropped   atomic.Int64
+	run       runner
+
+	errMu   sync.Mutex
+	lastErr error
+
+	OnError func(err error, batch []Candle)
+}
+
+// NewBatchArchiver batchSize 条或 every 时间到即写一批，queueSize 为入队缓冲
+func NewBatchArchiver(store CandleStore, batchSize int, every time.Duration, queueSize int) *BatchArchiver {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if every <= 0 {
+		every = time.Second
+	}
+	if queueSize < batchSize {
+		queueSize = batchSize
+	}
+	return &BatchArchiver{
+		store:     store,
+		batchSize: batchSize,
+		every:     every,
+		queue:     make(chan Candle, queueSize),
+	}
+}
+
+func (a *BatchArchiver) OnBarClose(c Candle) {
+	if a.closed.Load() {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.queue <- c:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped 因队列满或已关闭而丢弃的 K 线数
+func (a *BatchArchiver) Dropped() int64 { return a.dropped.Load() }
+
+// Start 启动后台写入协程，ctx 结束时写完剩余 K 线后退出
+func (a *BatchArchiver) Start(ctx context.Context) error { return a.run.start(ctx, a.loop) }
+
+// Close 停止接收，把队列中剩余 K 线写完后返回最后一次写入错误；
+// 从未 Start 时在当前协程写完队列，并返回 ErrArchiverNotStarted
+func (a *BatchArchiver) Close() error {
+	a.closed.Store(true)
+	if !a.run.started() {
+		a.drain(make([]Candle, 0, a.batchSize))
+		return errors.Join(ErrArchiverNotStarted, a.err())
+	}
+	if err := a.run.close(); err != nil {
+		return err
+	}
+	return a.err()
+}
+
+func (a *BatchArchiver) err() error {
+	a.errMu.Lock()
+	defer a.errMu.Unlock()
+	return a.lastErr
+}
+
+// write 写入一批 K 线，失败时记录错误并调用 OnError
+func (a *BatchArchiver) write(batch []Candle) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := a.store.WriteCandles(batch); err != nil {
+		a.errMu.Lock()
+		a.lastErr = err
+		a.errMu.Unlock()
+		if a.OnError != nil {
+			a.OnError(err, append([]Candle(nil), batch...))
+		}
+	}
+}
+
+// drain 把队列中剩余的 K 线按批写完
+func (a *BatchArchiver) drain(batch []Candle) {
+	for {
+		select {
+		case c := <-a.queue:
+			batch = append(batch, c)
+			if len(batch) >= a.batchSize {
+				a.write(batch)
+				batch = batch[:0]
+			}
+		default:
+			a.write(batch)
+			return
+		}
+	}
+}
+
+func (a *BatchArchiver) loop(ctx context.Context) error {
+	ticker := time.NewTicker(a.every)
+	defer ticker.Stop()
+
+	batch := make([]Candle, 0, a.batchSize)
+	flush := func() {
+		a.write(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 排空队列后退出
+			a.drain(batch)
+			return nil
+		case c := <-a.queue:
+			batch = append(batch, c)
+			if len(batch) >= a.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+type candleSink struct{ got []Candle }
+
+func (s *candleSink) OnBarClose(c Candle) { s.got = append(s.got, c) }
+
+func TestArchiver_EvictedCandles(t *testing.T) {
+	w := NewSlidingWindow(time.Second, 1024, 0.1)
+	sink := &candleSink{}
+	w.SetArchiver(sink, time.Second)
+
+	base := time.Unix(1_700_000_000, 0)
+	// 5 秒数据，每秒 10 笔
+	for i := 0; i < 50; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i%10), 1, base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	if len(sink.got) != 3 {
+		t.Fatalf("got %d candles, want 3", len(sink.got))
+	}
+	c := sink.got[0]
+	if !c.Start.Equal(base) || c.Open != 100 || c.High != 109 || c.Low != 100 || c.Close != 109 || c.Trades != 10 || c.Volume != 10 {
+		t.Fatalf("unexpected candle %+v", c)
+	}
+
+	w.FlushArchive()
+	if len(sink.got) != 4 {
+		t.Fatalf("flush: got %d candles, want 4", len(sink.got))
+	}
+}
+
+// readingSink 在 OnBarClose 中回读窗口，写锁内派发会死锁
+type readingSink struct {
+	w   *SlidingWindow
+	got []float64
+}
+
+func (s *readingSink) OnBarClose(Candle) { s.got = append(s.got, s.w.SumVolume()) }
+
+func TestArchiver_DispatchedOutsideLock(t *testing.T) {
+	w := NewSlidingWindow(time.Second, 1024, 0.1)
+	sink := &readingSink{w: w}
+	w.SetArchiver(sink, time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		base := time.Unix(1_700_000_000, 0)
+		for i := 0; i < 50; i++ {
+			w.AddWindowPoint(SideBuy, 100, 1, base.Add(time.Duration(i)*100*time.Millisecond))
+		}
+		w.FlushArchive()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("archiver dispatched under the write lock")
+	}
+	if len(sink.got) != 4 {
+		t.Fatalf("got %d candles, want 4", len(sink.got))
+	}
+}
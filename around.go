@@ -0,0 +1,141 @@
+package sliding_window
+
+import "time"
+
+// SliceStats 一个时间区间内的成交统计，OK=false 表示区间内没有点
+type SliceStats struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Points     int       `json:"points"`
+	Trades     int64     `json:"trades"`
+	Volume     float64   `json:"volume"`
+	BuyVolume  float64   `json:"buy_volume"`
+	SellVolume float64   `json:"sell_volume"`
+	Delta      float64   `json:"delta"`
+	Imbalance  float64   `json:"imbalance"`
+	VWAP       float64   `json:"vwap"`
+	High       float64   `json:"high"`
+	Low        float64   `json:"low"`
+	First      float64   `json:"first"`
+	Last       float64   `json:"last"`
+	Return     float64   `json:"return"` // Last / First - 1
+	OK         bool      `json:"ok"`
+}
+
+// WindowSliceStats 事件前后两段的对比
+type WindowSliceStats struct {
+	Ts         time.Time  `json:"ts"`
+	Before     SliceStats `json:"before"`      // [ts-before, ts)
+	After      SliceStats `json:"after"`       // [ts, ts+after]
+	EventPrice float64    `json:"event_price"` // 事件前最后一笔的价格（没有时取事件后第一笔）
+	Move       float64    `json:"move"`        // 事件后最后一笔相对 EventPrice 的收益
+	Markers    []Marker   `json:"markers"`     // 两段区间内的标注
+}
+
+// Around 以事件时间 ts 为界，统计之前 before 与之后 after 两段区间（读锁），
+// 便于在实盘里快速做事件研究（消息、资金费率、大单前后的量价变化）
+func (w *SlidingWindow) Around(ts time.Time, before, after time.Duration) WindowSliceStats {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.aroundUnlocked(ts, before, after)
+}
+
+// AroundMarker 对最近一个 label 标注调用 Around（读锁），找不到标注时返回 false
+func (w *SlidingWindow) AroundMarker(label string, before, after time.Duration) (WindowSliceStats, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for i := len(w.markers) - 1; i >= 0; i-- {
+		if w.markers[i].Label == label {
+			return w.aroundUnlocked(w.markers[i].Ts, before, after), true
+		}
+	}
+	return WindowSliceStats{}, false
+}
+
+func (w *SlidingWindow) aroundUnlocked(ts time.Time, before, after time.Duration) WindowSliceStats {
+	from, to := ts.Add(-before), ts.Add(after)
+	out := WindowSliceStats{Ts: ts}
+
+	lo := w.searchTsUnlocked(from)
+	mid := w.searchTsUnlocked(ts)
+	hi := w.searchTsUnlocked(to.Add(1)) // 包含 to
+
+	out.Before = w.sliceStatsUnlocked(lo, mid)
+	out.Before.From, out.Before.To = from, ts
+	out.After = w.sliceStatsUnlocked(mid, hi)
+	out.After.From, out.After.To = ts, to
+
+	switch {
+	case out.Before.OK:
+		out.EventPrice = out.Before.Last
+	case out.After.OK:
+		out.EventPrice = out.After.First
+	}
+	if out.After.OK && out.EventPrice != 0 {
+		out.Move = out.After.Last/out.EventPrice - 1
+	}
+
+	for _, m := range w.markers {
+		if m.Ts.Before(from) {
+			continue
+		}
+		if m.Ts.After(to) {
+			break
+		}
+		out.Markers = append(out.Markers, m)
+	}
+	return out
+}
+
+// sliceStatsUnlocked 统计窗口下标 [lo, hi) 的点（要求持有读锁）
+func (w *SlidingWindow) sliceStatsUnlocked(lo, hi int) SliceStats {
+	var s SliceStats
+	if hi <= lo {
+		return s
+	}
+	s.OK = true
+	s.Points = hi - lo
+
+	var pv, v, buyPV, buyV, sellPV, sellV compensated
+	for i := lo; i < hi; i++ {
+		pt := w.atUnlocked(i)
+		px := pt.Price.Float(w.priceScale)
+		if i == lo {
+			s.High, s.Low, s.First = px, px, px
+		}
+		s.High, s.Low, s.Last = max(s.High, px), min(s.Low, px), px
+
+		if w.isQuoteUnlocked(pt) {
+			continue
+		}
+		s.Trades += pt.Trades()
+		vol := max(pt.Volume.Float(w.volumeScale), 0)
+		pv.add(px * vol)
+		v.add(vol)
+		switch pt.Side {
+		case SideBuy:
+			buyPV.add(px * vol)
+			buyV.add(vol)
+		case SideSell:
+			sellPV.add(px * vol)
+			sellV.add(vol)
+		}
+	}
+
+	s.Volume = w.displayVolume(v.value(), pv.value())
+	s.BuyVolume = w.displayVolume(buyV.value(), buyPV.value())
+	s.SellVolume = w.displayVolume(sellV.value(), sellPV.value())
+	s.Delta = s.BuyVolume - s.SellVolume
+	if den := s.BuyVolume + s.SellVolume; den > 0 {
+		s.Imbalance = s.Delta / den
+	}
+	if sv := v.value(); sv > 0 {
+		s.VWAP = pv.value() / sv
+	}
+	if s.First != 0 {
+		s.Return = s.Last/s.First - 1
+	}
+	return s
+}
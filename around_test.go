@@ -0,0 +1,44 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAround(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 事件前 10 秒卖方主导、价格 100；事件后 10 秒买方主导、价格逐秒上涨
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideSell, 100, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	event := t0.Add(10 * time.Second)
+	w.Mark(event, "news")
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 101+float64(i), 2, event.Add(time.Duration(i)*time.Second))
+	}
+
+	a, ok := w.AroundMarker("news", 5*time.Second, 5*time.Second)
+	if !ok || !a.Ts.Equal(event) || len(a.Markers) != 1 {
+		t.Fatalf("around marker: %+v", a)
+	}
+	if a.Before.Points != 5 || a.Before.Imbalance != -1 || a.Before.VWAP != 100 {
+		t.Fatalf("before: %+v", a.Before)
+	}
+	// 包含 ts+5s 这一笔：101..106
+	if a.After.Points != 6 || a.After.Volume != 12 || a.After.Imbalance != 1 || a.After.High != 106 {
+		t.Fatalf("after: %+v", a.After)
+	}
+	if a.EventPrice != 100 || math.Abs(a.Move-0.06) > 1e-12 {
+		t.Fatalf("event price %v move %v", a.EventPrice, a.Move)
+	}
+
+	if _, ok := w.AroundMarker("missing", time.Second, time.Second); ok {
+		t.Fatal("missing marker should not be ok")
+	}
+	if e := w.Around(t0.Add(-time.Hour), time.Second, time.Second); e.Before.OK || e.After.OK {
+		t.Fatalf("empty slices: %+v", e)
+	}
+}
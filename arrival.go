@@ -0,0 +1,111 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// arrivalEMAAlpha 每秒成交笔数 EMA 的平滑系数
+const arrivalEMAAlpha = 0.1
+
+// arrivalTracker 按秒统计成交到达次数，维护每秒笔数的 EMA
+type arrivalTracker struct {
+	sec       int64 // 当前正在计数的秒
+	count     int64
+	lastCount int64 // 最近一个完整秒的笔数
+	ema       EMA
+}
+
+func (a *arrivalTracker) observe(ts time.Time) {
+	sec := ts.Unix()
+	if a.sec == 0 {
+		a.sec = sec
+		a.ema.Alpha = arrivalEMAAlpha
+	}
+
+	if sec > a.sec {
+		a.ema.Update(float64(a.count))
+		a.lastCount = a.count
+		// 中间空白的秒按 0 笔衰减：(1-alpha)^gap
+		if gap := sec - a.sec - 1; gap > 0 && a.ema.Initialized {
+			a.ema.Value *= math.Pow(1-a.ema.Alpha, float64(min(gap, 3600)))
+			a.lastCount = 0
+		}
+		a.sec = sec
+		a.count = 0
+	}
+	// 乱序点（sec < a.sec）计入当前秒，避免回退
+	a.count++
+}
+
+type ArrivalStats struct {
+	RatePerSec  float64 `json:"rate_per_sec"` // 窗口平均每秒笔数
+	RateEMA     float64 `json:"rate_ema"`     // 每秒笔数 EMA
+	LastSecond  int64   `json:"last_second"`  // 最近一个完整秒的笔数
+	BurstFactor float64 `json:"burst_factor"` // LastSecond / RateEMA
+	Fano        float64 `json:"fano"`         // 每秒笔数方差 / 均值，泊松到达约为 1，越大越成簇
+	Buckets     int     `json:"buckets"`
+}
+
+// ArrivalStats 成交到达率统计（读锁），用于发现喂价异常或刷单式爆发
+//
+//metric:name=arrival_stats unit=trades/s cost=O(n) min_points=2
+func (w *SlidingWindow) ArrivalStats() (ArrivalStats, bool) {
+	if !w.IsReady() {
+		return ArrivalStats{}, false
+	}
+
+	var st ArrivalStats
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 2 {
+		return st, false
+	}
+
+	first := w.atUnlocked(0).Ts.Unix()
+	last := w.lastUnlocked().Ts.Unix()
+	nb := int(last-first) + 1
+	if nb <= 0 {
+		return st, false
+	}
+
+	// Fano：按秒分桶（含空桶）
+	var sum, sumSq float64
+	cur, cnt := first, 0.0
+	flush := func(sec int64) {
+		for cur < sec {
+			sum += cnt
+			sumSq += cnt * cnt
+			cur++
+			cnt = 0
+		}
+	}
+	for i := 0; i < w.size; i++ {
+		s := w.atUnlocked(i).Ts.Unix()
+		if s > cur {
+			flush(s)
+		}
+		cnt++
+	}
+	flush(last + 1)
+
+	mean := sum / float64(nb)
+	if mean > 0 {
+		st.Fano = math.Max(0, sumSq/float64(nb)-mean*mean) / mean
+	}
+
+	if span := w.lastUnlocked().Ts.Sub(w.atUnlocked(0).Ts).Seconds(); span > 0 {
+		st.RatePerSec = float64(w.size) / span
+	}
+	st.Buckets = nb
+	st.LastSecond = w.arrival.lastCount
+	if v, ok := w.arrival.ema.Get(); ok {
+		st.RateEMA = v
+		if v > 0 {
+			st.BurstFactor = float64(st.LastSecond) / v
+		}
+	}
+	return st, true
+}
@@ -0,0 +1,56 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestArrivalStats_Burst(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 稳定的每秒 2 笔
+	for s := 0; s < 20; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second))
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second+500*time.Millisecond))
+	}
+	st, ok := w.ArrivalStats()
+	if !ok || st.Buckets != 20 || st.LastSecond != 2 || st.Fano != 0 {
+		t.Fatalf("steady: %+v", st)
+	}
+	if math.Abs(st.BurstFactor-1) > 1e-9 || math.Abs(st.RatePerSec-40/19.5) > 1e-9 {
+		t.Fatalf("steady rate: %+v", st)
+	}
+
+	// 一秒内爆发 20 笔，下一秒的成交把该秒结算为完整秒
+	burst := t0.Add(20 * time.Second)
+	for i := 0; i < 20; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, burst.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(21*time.Second))
+
+	st, ok = w.ArrivalStats()
+	if !ok || st.LastSecond != 20 || st.BurstFactor < 4 {
+		t.Fatalf("burst: %+v", st)
+	}
+	if st.Fano <= 1 {
+		t.Fatalf("clustered arrivals should have Fano > 1: %+v", st)
+	}
+}
+
+func TestArrivalStats_GapDecay(t *testing.T) {
+	w := NewSlidingWindow(time.Hour, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for s := 0; s < 10; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second))
+	}
+	before, _ := w.ArrivalStats()
+
+	// 空白 30 秒后再来一笔：EMA 按 0 笔衰减，最近完整秒记为 0 笔
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(40*time.Second))
+	st, ok := w.ArrivalStats()
+	if !ok || st.LastSecond != 0 || st.RateEMA >= before.RateEMA || st.BurstFactor != 0 {
+		t.Fatalf("gap: before=%+v after=%+v", before, st)
+	}
+}
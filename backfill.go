@@ -0,0 +1,137 @@
+package sliding_window
+
+import (
+	"slices"
+	"time"
+)
+
+// Backfill 把历史成交（例如 websocket 连上后通过 REST 拉取的最近成交）合并到已收到的实时点之下（写锁），
+// 返回实际写入的点数。规则：
+//   - 早于窗口（按最新一个点计算）的点丢弃；
+//   - 不早于最旧实时点的点视为与实时数据重叠，不写入；
+//   - 开启 SetTradeDedup 时同样按已见 ID 去重。
+//
+// 合并后按时间重建窗口：累加统计、high/low、TWAP 随之更新，成交量 EMA 与到达率按合并后的序列重放；
+// 出窗归档、出窗回调、游程合并等写入流程不作用于回填与重建。开启复制时之后的 DeltaSince 给出全量
+func (w *SlidingWindow) Backfill(pts []WindowPoint) int {
+	trades := make([]Trade, len(pts))
+	for i, pt := range pts {
+		trades[i].WindowPoint = pt
+	}
+	return w.BackfillTrades(trades)
+}
+
+// BackfillTrades 同 Backfill，但与实时数据重叠的成交带 TradeID、且不在窗口记住的 ID 中时也写入
+// （窗口只在开启 SetTradeDedup 或 NegativeVolumeCorrect 后记住写入成交的 ID）。
+// 时钟偏差与 DuplicateSeq 排序不使用回填成交的 RecvTs、Seq（写锁）
+func (w *SlidingWindow) BackfillTrades(trades []Trade) int {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	if len(trades) == 0 {
+		return 0
+	}
+
+	live := make([]WindowPoint, w.size)
+	for i := range live {
+		live[i] = w.atUnlocked(i)
+	}
+	ref := slices.MaxFunc(trades, func(a, b Trade) int { return a.Ts.Compare(b.Ts) }).Ts
+	if len(live) > 0 {
+		ref = live[len(live)-1].Ts
+	}
+	threshold := w.thresholdUnlocked(ref)
+
+	w.dedup.forget(threshold)
+	w.negative.trades.forget(threshold)
+	older := make([]WindowPoint, 0, len(trades))
+	for _, tr := range trades {
+		if !tr.Ts.After(threshold) {
+			w.counters.rejects.Add(1)
+			continue
+		}
+		if len(live) > 0 && !tr.Ts.Before(live[0].Ts) {
+			if tr.TradeID == "" {
+				continue
+			}
+			_, dup := w.dedup.seen[tr.TradeID]
+			_, known := w.negative.trades.seen[tr.TradeID]
+			if dup || known {
+				continue
+			}
+		}
+		if tr.TradeID != "" {
+			if w.dedup.max > 0 && w.dedupUnlocked(tr) {
+				continue
+			}
+			if w.negative.policy == NegativeVolumeCorrect {
+				w.negative.trades.remember(tr)
+			}
+		}
+		older = append(older, tr.WindowPoint)
+	}
+	if len(older) == 0 {
+		return 0
+	}
+	slices.SortStableFunc(older, func(a, b WindowPoint) int { return a.Ts.Compare(b.Ts) })
+
+	for w.size > 0 {
+		w.popTailUnlocked()
+	}
+	w.resetVolumeEMAUnlocked()
+
+	// 归并：同一时间回填点在前
+	i, j := 0, 0
+	for i < len(older) || j < len(live) {
+		var pt WindowPoint
+		backfilled := j == len(live) || (i < len(older) && !older[i].Ts.After(live[j].Ts))
+		if backfilled {
+			pt, i = older[i], i+1
+		} else {
+			pt, j = live[j], j+1
+		}
+
+		// 重建中挤出的点不是真实出窗：不归档、不触发出窗回调
+		if w.countFullUnlocked() {
+			w.dropHeadUnlocked()
+		} else if w.fullUnlocked() {
+			w.counters.overflows.Add(1)
+			w.dropHeadUnlocked()
+		}
+		w.appendUnlocked(pt)
+		w.replayVolumeEMAUnlocked(pt)
+		if backfilled {
+			w.observeLifetimeUnlocked(pt)
+			w.observeAnchoredUnlocked(pt)
+		}
+	}
+
+	w.hiLoDirty = true
+	w.seqTail = seqGroup{}
+	w.resetReplJournalUnlocked()
+	w.finishAddUnlocked(threshold)
+	return len(older)
+}
+
+// resetVolumeEMAUnlocked 清空成交量 EMA 与到达率，准备按窗口内序列重放（要求持有写锁）
+func (w *SlidingWindow) resetVolumeEMAUnlocked() {
+	w.ema = NewEMA(w.ema.Alpha)
+	if w.emaLong != nil {
+		w.emaLong = NewEMA(w.emaLong.Alpha)
+	}
+	w.lastTradeTs = time.Time{}
+	w.arrival = arrivalTracker{}
+}
+
+// replayVolumeEMAUnlocked 按时间顺序重放一个点对成交量 EMA 与到达率的贡献（要求持有写锁）
+func (w *SlidingWindow) replayVolumeEMAUnlocked(pt WindowPoint) {
+	if w.isQuoteUnlocked(pt) {
+		return
+	}
+	if w.tracked.Has(TrackVolumeEMA) {
+		w.updateVolumeEMAUnlocked(pt)
+	}
+	if w.tracked.Has(TrackArrival) {
+		w.arrival.observe(pt.Ts)
+	}
+}
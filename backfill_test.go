@@ -0,0 +1,90 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	w.EnableReplication(0)
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+
+	// websocket 连上后先收到 100..109 的实时成交
+	for s := 100; s < 110; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, at(s))
+	}
+	v0 := w.Version()
+
+	var hist []WindowPoint
+	for s := 109; s >= 30; s-- { // REST 按时间倒序返回，并与实时数据有重叠
+		hist = append(hist, WindowPoint{
+			Ts:     at(s),
+			Price:  NewQtyLoz(90+float64(s%5), w.priceScale),
+			Volume: NewQtyLoz(2, w.volumeScale),
+			Side:   SideSell,
+		})
+	}
+
+	// 50..99 写入；30..49 已在窗口之外；100..109 与实时数据重叠且没有 TradeID
+	if n := w.Backfill(hist); n != 50 {
+		t.Fatalf("backfilled %d, want 50", n)
+	}
+	if got := w.SumVolume(); got != 110 {
+		t.Fatalf("sum volume = %v, want 110", got)
+	}
+
+	w.mu.RLock()
+	size := w.size
+	ordered := true
+	for i := 1; i < w.size; i++ {
+		ordered = ordered && !w.atUnlocked(i).Ts.Before(w.atUnlocked(i-1).Ts)
+	}
+	lo := QtyLoz(w.LowestPrice.Load()).Float(w.priceScale)
+	latest := QtyLoz(w.LatestPrice.Load()).Float(w.priceScale)
+	w.mu.RUnlock()
+
+	if size != 60 || !ordered || lo != 90 || latest != 100 {
+		t.Fatalf("size=%d ordered=%v low=%v latest=%v", size, ordered, lo, latest)
+	}
+	if imb := w.Imbalance(); imb >= 0 {
+		t.Fatalf("imbalance = %v, want sell-dominated", imb)
+	}
+	if d, ok := w.DeltaSince(v0); !ok || !d.Full {
+		t.Fatalf("delta after backfill should be full: %+v", d)
+	}
+}
+
+func TestBackfill_NoEvictSideEffects(t *testing.T) {
+	var evicted []WindowPoint
+	w := NewSlidingWindowCount(3, 0.1)
+	w.SetEvictHook(func(pt WindowPoint) { evicted = append(evicted, pt) })
+	sink := &candleSink{}
+	w.SetArchiver(sink, time.Second)
+
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	for s := 10; s < 13; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, at(s))
+	}
+
+	// 回填点排在实时点之前，重建时被容量挤出，不应归档或触发出窗回调
+	hist := []WindowPoint{
+		{Ts: at(1), Price: NewQtyLoz(90, w.priceScale), Volume: NewQtyLoz(5, w.volumeScale), Side: SideSell},
+		{Ts: at(2), Price: NewQtyLoz(91, w.priceScale), Volume: NewQtyLoz(5, w.volumeScale), Side: SideSell},
+	}
+	if n := w.Backfill(hist); n != 2 {
+		t.Fatalf("backfilled %d, want 2", n)
+	}
+	if got := w.SumVolume(); got != 3 {
+		t.Fatalf("sum volume = %v, want 3", got)
+	}
+	w.FlushArchive()
+	if len(evicted) != 0 || len(sink.got) != 0 {
+		t.Fatalf("rebuild leaked side effects: evicted %v, candles %v", evicted, sink.got)
+	}
+	if c := w.Counters(); c.Evictions != 0 {
+		t.Fatalf("evictions = %d, want 0", c.Evictions)
+	}
+}
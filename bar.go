@@ -0,0 +1,77 @@
+package sliding_window
+
+import "time"
+
+// defaultBarInterval AddBar 默认的 K 线周期
+const defaultBarInterval = time.Second
+
+// SetBarInterval 设置 AddBar 输入 K 线的周期（默认 1s），决定展开点在时间上的分布
+func (w *SlidingWindow) SetBarInterval(d time.Duration) {
+	if d <= 0 {
+		d = defaultBarInterval
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.barInterval = d
+}
+
+// AddBar 添加一根预聚合 K 线（写锁），ts 为 K 线开始时间。
+//
+// K 线按 O→L→H→C（阳线）或 O→H→L→C（阴线）展开为 4 个代表点，均匀分布在周期内；
+// 成交量按 tick 平均分配（余数计入收盘点），方向取相邻两点的价格变化，
+// 持平时沿用上一腿方向。这样只有 K 线数据源时，基于逐笔的指标仍然可用。
+func (w *SlidingWindow) AddBar(open, high, low, close, vol float64, ts time.Time) {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	pts := w.expandBarUnlocked(open, high, low, close, vol, ts)
+	w.add(pts[:]...)
+}
+
+func (w *SlidingWindow) expandBarUnlocked(open, high, low, close, vol float64, ts time.Time) [4]WindowPoint {
+	interval := w.barInterval
+	if interval <= 0 {
+		interval = defaultBarInterval
+	}
+
+	prices := [4]float64{open, low, high, close}
+	if close < open {
+		prices = [4]float64{open, high, low, close}
+	}
+
+	total := NewQtyLoz(vol, w.volumeScale).Int64()
+	if total < 0 {
+		total = 0
+	}
+	each := total / 4
+
+	// 开盘点方向取整根 K 线方向
+	side := SideBuy
+	if close < open {
+		side = SideSell
+	}
+
+	var pts [4]WindowPoint
+	step := interval / 4
+	for i, px := range prices {
+		if i > 0 {
+			switch {
+			case px > prices[i-1]:
+				side = SideBuy
+			case px < prices[i-1]:
+				side = SideSell
+			}
+		}
+		v := each
+		if i == 3 {
+			v = total - 3*each
+		}
+		pts[i] = WindowPoint{
+			Ts:     ts.Add(time.Duration(i) * step),
+			Price:  NewQtyLoz(px, w.priceScale),
+			Volume: QtyLoz(v),
+			Side:   side,
+		}
+	}
+	return pts
+}
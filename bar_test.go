@@ -0,0 +1,26 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddBar_Expand(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	w.AddBar(100, 105, 98, 103, 10, base)
+
+	if hi, lo := w.HighestPrice.Load(), w.LowestPrice.Load(); hi != NewQtyLoz(105, w.priceScale).Int64() || lo != NewQtyLoz(98, w.priceScale).Int64() {
+		t.Fatalf("high/low = %d/%d", hi, lo)
+	}
+	if got := w.SumVolume(); got != 10 {
+		t.Fatalf("sum volume = %v, want 10", got)
+	}
+	if got := w.LatestPrice.Load(); got != NewQtyLoz(103, w.priceScale).Int64() {
+		t.Fatalf("latest = %d", got)
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+}
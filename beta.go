@@ -0,0 +1,58 @@
+package sliding_window
+
+import "time"
+
+// betaMinReturns BetaTo 至少需要的重采样收益个数
+const betaMinReturns = 10
+
+// covAccum Welford 增量协方差累加器，单次遍历且数值稳定
+type covAccum struct {
+	n     int
+	meanX float64
+	meanY float64
+	cxy   float64 // Σ (x-meanX)(y-meanY)
+	m2x   float64 // Σ (x-meanX)^2
+	m2y   float64
+}
+
+func (c *covAccum) add(x, y float64) {
+	c.n++
+	n := float64(c.n)
+	dx := x - c.meanX
+	c.meanX += dx / n
+	dy := y - c.meanY
+	c.meanY += dy / n
+	c.cxy += dx * (y - c.meanY)
+	c.m2x += dx * (x - c.meanX)
+	c.m2y += dy * (y - c.meanY)
+}
+
+// BetaTo 本窗口相对参考窗口（如 BTC）的滚动 beta：两者在重叠时段按 interval 重采样，
+// 对 log return 用 Welford 增量协方差计算 cov(self, ref) / var(ref)，可直接作为对冲比例
+// interval 过小、重采样网格超过点数上限（见 Resample）时返回 false
+func (w *SlidingWindow) BetaTo(ref *SlidingWindow, interval time.Duration) (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	if ref == nil || ref == w {
+		return 0, false
+	}
+	start, n, ok := commonGrid(w, ref, interval)
+	if !ok || n < betaMinReturns+1 {
+		return 0, false
+	}
+
+	self := logReturns(w.resampleOn(start, interval, n))
+	base := logReturns(ref.resampleOn(start, interval, n))
+
+	// x 为本窗口，y 为参考窗口
+	var c covAccum
+	for i := range self {
+		c.add(self[i], base[i])
+	}
+	if c.m2y <= 0 {
+		return 0, false
+	}
+	return c.cxy / c.m2y, true
+}
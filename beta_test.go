@@ -0,0 +1,34 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBetaTo(t *testing.T) {
+	ref := NewSlidingWindow(time.Minute, 4096, 0.1)
+	w := NewSlidingWindow(time.Minute, 4096, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	// 本窗口收益恒为参考窗口的 2 倍
+	for i := 0; i < 600; i++ {
+		r := 0.001 * math.Sin(float64(i)*0.7)
+		pRef := 100 * math.Exp(r*float64(i%7))
+		pSelf := 50 * math.Exp(2*r*float64(i%7))
+		ts := base.Add(time.Duration(i) * 100 * time.Millisecond)
+		ref.AddWindowPoint(SideBuy, pRef, 1, ts)
+		w.AddWindowPoint(SideBuy, pSelf, 1, ts)
+	}
+
+	beta, ok := w.BetaTo(ref, 100*time.Millisecond)
+	if !ok || math.Abs(beta-2) > 0.05 {
+		t.Fatalf("beta = %v (ok=%v), want ~2", beta, ok)
+	}
+	if _, ok := w.BetaTo(ref, time.Nanosecond); ok {
+		t.Fatal("1ns interval should exceed the grid limit")
+	}
+	if _, ok := w.BetaTo(w, time.Second); ok {
+		t.Fatal("beta to self should be rejected")
+	}
+}
@@ -0,0 +1,150 @@
+package sliding_window
+
+import (
+	"errors"
+	"time"
+)
+
+// BlendedPoint 混合价格序列上的一个点
+type BlendedPoint struct {
+	Ts    time.Time `json:"ts"`
+	Price float64   `json:"price"`
+}
+
+// priceBlend 成交价 / 标记价混合配置与状态
+type priceBlend struct {
+	enabled     bool
+	tradeWeight float64 // 已归一化
+	markWeight  float64
+	lastTrade   float64
+	lastMark    float64
+	series      []BlendedPoint // 按时间升序，随窗口一起过期
+}
+
+// SetPriceBlend 开启成交价与标记价的加权混合（写锁），权重按和归一化，
+// 例如 (0.7, 0.3)。任一来源更新时追加一个混合价格点；尚未收到标记价时混合价等于成交价。
+// 开启后均衡区（EquilibriumZone、Snapshot、Decision）的当前价改用最新混合价，
+// Snapshot.BlendedPrice 同时给出该值。两个权重都为 0 时关闭混合并清空序列。
+func (w *SlidingWindow) SetPriceBlend(tradeWeight, markWeight float64) error {
+	if tradeWeight < 0 || markWeight < 0 {
+		return errors.New("blend weights must be non-negative")
+	}
+	if !w.tracked.Has(TrackBlend) {
+		return errors.New("price blending is not tracked by this window")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sum := tradeWeight + markWeight
+	if sum == 0 {
+		w.blend = priceBlend{lastTrade: w.blend.lastTrade, lastMark: w.blend.lastMark}
+	} else {
+		w.blend.enabled = true
+		w.blend.tradeWeight = tradeWeight / sum
+		w.blend.markWeight = markWeight / sum
+	}
+	// 派生指标的当前价随之改变，已缓存的 Snapshot 失效
+	w.bumpVersionUnlocked()
+	return nil
+}
+
+// UpdateMark 推送标记价（写锁），开启混合时追加一个混合价格点并递增版本号
+func (w *SlidingWindow) UpdateMark(price float64, ts time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if price <= 0 {
+		return
+	}
+	w.blend.lastMark = price
+	if w.size > 0 && !ts.After(w.thresholdUnlocked(w.lastUnlocked().Ts)) {
+		return // 已在窗口之外
+	}
+	n := len(w.blend.series)
+	w.appendBlendUnlocked(ts)
+	if len(w.blend.series) != n {
+		w.bumpVersionUnlocked()
+	}
+}
+
+// observeBlendUnlocked 成交进窗时更新混合序列（要求持有写锁）
+func (w *SlidingWindow) observeBlendUnlocked(pt WindowPoint) {
+	w.blend.lastTrade = pt.Price.Float(w.priceScale)
+	w.appendBlendUnlocked(pt.Ts)
+}
+
+func (w *SlidingWindow) appendBlendUnlocked(ts time.Time) {
+	b := &w.blend
+	if !b.enabled || b.lastTrade <= 0 {
+		return
+	}
+
+	px := b.lastTrade
+	if b.lastMark > 0 {
+		px = b.tradeWeight*b.lastTrade + b.markWeight*b.lastMark
+	}
+
+	n := len(b.series)
+	if n > 0 && ts.Before(b.series[n-1].Ts) {
+		ts = b.series[n-1].Ts // 保持单调，乱序更新记在最新时刻
+	}
+	b.series = append(b.series, BlendedPoint{Ts: ts, Price: px})
+
+	// 上限为容量的两倍（成交与标记各占一份）
+	if limit := 2 * w.capacityUnlocked(); len(b.series) > limit {
+		b.series = append(b.series[:0], b.series[len(b.series)-limit:]...)
+	}
+}
+
+// blendedUnlocked 最新混合价格（要求持有锁），未开启混合或序列为空时返回 false
+func (w *SlidingWindow) blendedUnlocked() (float64, bool) {
+	n := len(w.blend.series)
+	if !w.blend.enabled || n == 0 {
+		return 0, false
+	}
+	return w.blend.series[n-1].Price, true
+}
+
+// zonePriceUnlocked 均衡区使用的当前价（要求持有锁，且 size > 0）：开启混合时取最新混合价，
+// 降低单笔异常成交对 Distance / NormDist 的影响，否则取最新成交价
+func (w *SlidingWindow) zonePriceUnlocked() float64 {
+	if px, ok := w.blendedUnlocked(); ok {
+		return px
+	}
+	return w.lastUnlocked().Price.Float(w.priceScale)
+}
+
+// trimBlendUnlocked 移除 Ts <= threshold 的混合点（要求持有写锁）
+func (w *SlidingWindow) trimBlendUnlocked(threshold time.Time) {
+	s := w.blend.series
+	i := 0
+	for i < len(s) && !s[i].Ts.After(threshold) {
+		i++
+	}
+	if i > 0 {
+		w.blend.series = append(s[:0], s[i:]...)
+	}
+}
+
+// BlendedPrice 最新混合价格（读锁），未开启混合时返回 false
+//
+//metric:name=blended_price unit=price cost=O(1) min_points=1
+func (w *SlidingWindow) BlendedPrice() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.blendedUnlocked()
+}
+
+// BlendedSeries 窗口内混合价格序列的副本（读锁）
+func (w *SlidingWindow) BlendedSeries() []BlendedPoint {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return append([]BlendedPoint(nil), w.blend.series...)
+}
@@ -0,0 +1,59 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceBlend(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if err := w.SetPriceBlend(0.7, 0.3); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Unix(1_700_000_000, 0)
+
+	w.AddWindowPoint(SideBuy, 100, 1, base)
+	if px, ok := w.BlendedPrice(); !ok || px != 100 {
+		t.Fatalf("before mark: %v %v", px, ok)
+	}
+
+	w.UpdateMark(110, base.Add(time.Second))
+	if px, _ := w.BlendedPrice(); math.Abs(px-103) > 1e-9 {
+		t.Fatalf("after mark: %v, want 103", px)
+	}
+
+	w.AddWindowPoint(SideSell, 90, 1, base.Add(2*time.Second))
+	if px, _ := w.BlendedPrice(); math.Abs(px-96) > 1e-9 {
+		t.Fatalf("after trade: %v, want 96", px)
+	}
+	if n := len(w.BlendedSeries()); n != 3 {
+		t.Fatalf("series len = %d, want 3", n)
+	}
+}
+
+func TestPriceBlend_Snapshot(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i, px := range []float64{100, 102, 101, 103} {
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*time.Second))
+	}
+	if s := w.Snapshot(); s == nil || s.BlendedPrice != 0 || s.Price != 103 {
+		t.Fatalf("without blend: %+v", s)
+	}
+
+	if err := w.SetPriceBlend(0.5, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	w.AddWindowPoint(SideBuy, 104, 1, base.Add(4*time.Second))
+	// 标记价更新没有新成交，缓存的 Snapshot 也必须失效
+	w.UpdateMark(100, base.Add(5*time.Second))
+
+	s := w.Snapshot()
+	if s == nil || math.Abs(s.BlendedPrice-102) > 1e-9 || s.Price != s.BlendedPrice {
+		t.Fatalf("with blend: %+v", s)
+	}
+	if ez, ok := w.EquilibriumZone(0.4, 0.5); !ok || ez.Price != s.BlendedPrice {
+		t.Fatalf("zone price = %+v, want blended", ez)
+	}
+}
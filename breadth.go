@@ -0,0 +1,86 @@
+package sliding_window
+
+import "time"
+
+// breadthLookback Breadth 统计涨跌的回看长度
+const breadthLookback = time.Minute
+
+// Breadth 跨 symbol 的市场宽度
+type Breadth struct {
+	Symbols       int     `json:"symbols"`        // 参与统计（已就绪）的窗口数
+	Advancing     int     `json:"advancing"`      // 1m 收益 > 0
+	Declining     int     `json:"declining"`      // 1m 收益 < 0
+	AdvancingPct  float64 `json:"advancing_pct"`  // Advancing / Symbols
+	AboveZone     int     `json:"above_zone"`     // 最新价高于均衡区上轨
+	BelowZone     int     `json:"below_zone"`     // 最新价低于均衡区下轨
+	AboveZonePct  float64 `json:"above_zone_pct"` // AboveZone / Symbols
+	DeltaVolume   float64 `json:"delta_volume"`   // Σ 主买量 − 主卖量（各自展示单位，仅同类品种可比）
+	DeltaNotional float64 `json:"delta_notional"` // Σ 主买额 − 主卖额（跨品种可比）
+	AvgReturn     float64 `json:"avg_return"`     // 1m 收益等权平均
+	Ts            int64   `json:"ts"`             // 统计时间（毫秒）
+}
+
+// Breadth 汇总全部窗口的市场宽度：1m 上涨占比、价格在均衡区之上的占比、总主动买卖差。
+// manager 锁只在解析窗口引用时持有；未就绪的窗口不计入。
+func (m *Manager) Breadth() Breadth {
+	b := Breadth{Ts: m.now().UnixMilli()}
+
+	var sumRet float64
+	for _, t := range m.resolve(nil) {
+		s := t.w.Snapshot()
+		if s == nil {
+			continue
+		}
+		b.Symbols++
+
+		if r, ok := t.w.returnOver(breadthLookback); ok {
+			sumRet += r
+			switch {
+			case r > 0:
+				b.Advancing++
+			case r < 0:
+				b.Declining++
+			}
+		}
+
+		switch {
+		case s.LatestPrice > s.UpperBand:
+			b.AboveZone++
+		case s.LatestPrice < s.LowerBand:
+			b.BelowZone++
+		}
+
+		b.DeltaVolume += s.DeltaVolume
+		t.w.mu.RLock()
+		b.DeltaNotional += t.w.buyNotional.value() - t.w.sellNotional.value()
+		t.w.mu.RUnlock()
+	}
+
+	if b.Symbols > 0 {
+		n := float64(b.Symbols)
+		b.AdvancingPct = float64(b.Advancing) / n
+		b.AboveZonePct = float64(b.AboveZone) / n
+		b.AvgReturn = sumRet / n
+	}
+	return b
+}
+
+// returnOver 最新价相对 lookback 之前（窗口内第一个不早于该时刻的点）的简单收益（读锁）
+func (w *SlidingWindow) returnOver(lookback time.Duration) (float64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 2 {
+		return 0, false
+	}
+	last := w.lastUnlocked()
+	i := w.searchTsUnlocked(last.Ts.Add(-lookback))
+	if i >= w.size-1 {
+		return 0, false
+	}
+	base := w.atUnlocked(i).Price.Float(w.priceScale)
+	if base <= 0 {
+		return 0, false
+	}
+	return last.Price.Float(w.priceScale)/base - 1, true
+}
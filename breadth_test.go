@@ -0,0 +1,26 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManager_Breadth(t *testing.T) {
+	m := NewManager(func(string) *SlidingWindow { return NewSlidingWindow(time.Minute, 1024, 0.1) })
+	base := time.Unix(1_700_000_000, 0)
+
+	up, down := m.GetOrCreate("UP"), m.GetOrCreate("DOWN")
+	for i := 0; i < 100; i++ {
+		ts := base.Add(time.Duration(i) * 100 * time.Millisecond)
+		up.AddWindowPoint(SideBuy, 100+float64(i)*0.1, 1, ts)
+		down.AddWindowPoint(SideSell, 100-float64(i)*0.1, 1, ts)
+	}
+
+	b := m.Breadth()
+	if b.Symbols != 2 || b.Advancing != 1 || b.Declining != 1 || b.AdvancingPct != 0.5 {
+		t.Fatalf("unexpected breadth %+v", b)
+	}
+	if b.DeltaVolume != 0 {
+		t.Fatalf("delta volume = %v, want 0", b.DeltaVolume)
+	}
+}
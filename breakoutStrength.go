@@ -1,5 +1,10 @@
 package sliding_window
 
+import "time"
+
+// breakoutTouchFrac 价格回到距离高/低点 Range 的这个比例以内算一次触及
+const breakoutTouchFrac = 0.05
+
 type BreakoutStrength struct {
 	High         float64
 	Low          float64
@@ -8,17 +13,107 @@ type BreakoutStrength struct {
 	Pos01        float64 // 通道内位置 [0,1]，超出范围也会被 clamp
 	Strength     float64 // 突破强度：上破为正，下破为负，未破为 0
 	StrengthNorm float64 // 标准化后的突破幅度（相对 Range）
+
+	// 以下只由 BreakoutStrength() 填充：被突破价位的成色
+	HighAge      time.Duration // 高点创出距最新一笔的时长
+	LowAge       time.Duration
+	HighTouches  int // 价格触及高点附近的次数（连续停留算一次，含创出那次）
+	LowTouches   int
+	HighQuality  float64 // 高点成色 [0,1]：越早创出、触及越多越高
+	LowQuality   float64
+	LevelQuality float64 // 被突破一侧的成色，未突破为 0
 }
 
+// BreakoutStrength 最新价相对窗口内（除最新点外）高低点的突破强度
+//
+//metric:name=breakout_strength unit=price cost=O(n) min_points=2
 func (w *SlidingWindow) BreakoutStrength() (BreakoutStrength, bool) {
+	if !w.IsReady() {
+		return BreakoutStrength{}, false
+	}
 
 	// collectStats：锁内把 prices[0:n] 填满（float 价格），并统计 sumPV/sumV 等
 	stats, ok := w.collectStats()
 	if !ok {
 		return BreakoutStrength{}, false
 	}
+	defer w.releaseStats(&stats)
+
+	bs, ok := w.breakoutStrength(stats)
+	if !ok {
+		return bs, false
+	}
 
-	return w.breakoutStrength(stats)
+	w.mu.RLock()
+	w.breakoutLevelsUnlocked(&bs)
+	w.mu.RUnlock()
+	return bs, true
+}
+
+// breakoutLevelsUnlocked 统计高低点的创出时间、触及次数并打分（要求持有读锁）。
+// 久经考验的价位（早就创出、被反复测试）被突破，比刚创出的价位被突破更有意义
+func (w *SlidingWindow) breakoutLevelsUnlocked(bs *BreakoutStrength) {
+	n := w.size - 1 // 与 breakoutStrength 一致，排除最新点
+	if n < 1 {
+		return
+	}
+	tol := breakoutTouchFrac * bs.Range
+	newest := w.lastUnlocked().Ts
+
+	var highTs, lowTs time.Time
+	var nearHigh, nearLow bool
+	for i := 0; i < n; i++ {
+		pt := w.atUnlocked(i)
+		px := pt.Price.Float(w.priceScale)
+
+		if px == bs.High && highTs.IsZero() {
+			highTs = pt.Ts
+		}
+		if px == bs.Low && lowTs.IsZero() {
+			lowTs = pt.Ts
+		}
+
+		near := px >= bs.High-tol
+		if near && !nearHigh {
+			bs.HighTouches++
+		}
+		nearHigh = near
+
+		near = px <= bs.Low+tol
+		if near && !nearLow {
+			bs.LowTouches++
+		}
+		nearLow = near
+	}
+
+	span := newest.Sub(w.atUnlocked(0).Ts)
+	if !highTs.IsZero() {
+		bs.HighAge = newest.Sub(highTs)
+		bs.HighQuality = levelQuality(bs.HighAge, span, bs.HighTouches)
+	}
+	if !lowTs.IsZero() {
+		bs.LowAge = newest.Sub(lowTs)
+		bs.LowQuality = levelQuality(bs.LowAge, span, bs.LowTouches)
+	}
+
+	switch {
+	case bs.Strength > 0:
+		bs.LevelQuality = bs.HighQuality
+	case bs.Strength < 0:
+		bs.LevelQuality = bs.LowQuality
+	}
+}
+
+// levelQuality 价位成色：年龄占窗口跨度的比例与触及次数（1 次为 0，2 次 0.5，3 次约 0.67…）各占一半
+func levelQuality(age, span time.Duration, touches int) float64 {
+	var ageScore, touchScore float64
+	if span > 0 {
+		ageScore = min(float64(age)/float64(span), 1)
+	}
+	if touches > 0 {
+		touchScore = 1 - 1/float64(touches)
+	}
+	return 0.5*ageScore + 0.5*touchScore
 }
 
 func (w *SlidingWindow) breakoutStrength(stats WindowStats) (BreakoutStrength, bool) {
@@ -0,0 +1,32 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBreakoutLevelQuality(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	// 高点 105 在第 1 秒创出，之后被测试两次，最后一笔上破
+	prices := []float64{100, 105, 101, 104.9, 100, 105, 102, 99, 106}
+	for i, px := range prices {
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	bs, ok := w.BreakoutStrength()
+	if !ok {
+		t.Fatal("not ok")
+	}
+	if bs.Strength != 1 || bs.HighAge != 7*time.Second || bs.HighTouches != 3 {
+		t.Fatalf("high level: %+v", bs)
+	}
+	if bs.LowAge != time.Second || bs.LowTouches != 1 || bs.LowQuality >= bs.HighQuality {
+		t.Fatalf("low level: %+v", bs)
+	}
+	want := 0.5*7.0/8.0 + 0.5*(1-1.0/3)
+	if math.Abs(bs.LevelQuality-want) > 1e-9 {
+		t.Fatalf("level quality = %v, want %v", bs.LevelQuality, want)
+	}
+}
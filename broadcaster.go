@@ -0,0 +1,325 @@
+package sliding_window
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// broadcastQueue 每个客户端的发送队列长度，慢客户端队列满时丢弃
+	broadcastQueue = 256
+	// broadcastWriteTimeout 单帧写超时
+	broadcastWriteTimeout = 5 * time.Second
+)
+
+// Broadcaster 把 manager 下各 symbol 的 Snapshot 以 JSON 推送给 WebSocket 订阅者。
+//
+// 客户端可在连接 URL 上带初始过滤条件：?symbols=BTC,ETH&throttle=500ms，
+// 也可随时发送指令：
+//
+//	{"op":"subscribe","symbols":["BTC"]}
+//	{"op":"unsubscribe","symbols":["BTC"]}
+//	{"op":"throttle","ms":500}
+//
+// 连接时未带 symbols 的客户端接收全部 symbol；一旦订阅过具体 symbol 就只接收订阅的部分，
+// 全部退订后不再收到推送。推送消息格式为
+// {"type":"snapshot","symbol":"BTC","data":{...Snapshot}}。
+//
+// 默认只接受不带 Origin 或 Origin 与 Host 一致的握手，跨站页面需通过 SetOriginCheck 放行。
+type Broadcaster struct {
+	m        *Manager
+	interval time.Duration
+
+	mu          sync.Mutex
+	clients     map[*wsClient]struct{}
+	checkOrigin func(r *http.Request) bool
+
+	dropped atomic.Int64
+	run     runner
+}
+
+// NewBroadcaster 每 interval 对全部 symbol 做一轮快照并推送
+func NewBroadcaster(m *Manager, interval time.Duration) *Broadcaster {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Broadcaster{
+		m:        m,
+		interval: interval,
+		clients:  make(map[*wsClient]struct{}),
+	}
+}
+
+// SetOriginCheck 设置握手时的 Origin 校验，返回 false 的请求以 403 拒绝；nil 恢复默认的同源校验
+func (b *Broadcaster) SetOriginCheck(fn func(r *http.Request) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkOrigin = fn
+}
+
+// Clients 当前连接数
+func (b *Broadcaster) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// Dropped 因客户端发送队列满而丢弃的消息数
+func (b *Broadcaster) Dropped() int64 { return b.dropped.Load() }
+
+// Run 按 interval 推送，直到 ctx 结束；返回前断开全部客户端
+func (b *Broadcaster) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	defer b.closeAll()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			b.broadcast(now)
+		}
+	}
+}
+
+// Start 在后台运行 Run(ctx)
+func (b *Broadcaster) Start(ctx context.Context) error { return b.run.start(ctx, b.Run) }
+
+// Close 停止推送并断开全部客户端
+func (b *Broadcaster) Close() error {
+	err := b.run.close()
+	b.closeAll()
+	return err
+}
+
+// ServeHTTP 升级为 WebSocket 并阻塞到连接断开
+func (b *Broadcaster) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	check := b.checkOrigin
+	b.mu.Unlock()
+	if check == nil {
+		check = wsSameOrigin
+	}
+	if !check(r) {
+		http.Error(rw, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	c := newWSClient(r)
+	conn, brw, err := wsUpgrade(rw, r)
+	if err != nil {
+		return
+	}
+	c.conn, c.rw = conn, brw
+
+	b.mu.Lock()
+	b.clients[c] = struct{}{}
+	b.mu.Unlock()
+
+	go c.writeLoop()
+	c.readLoop()
+
+	b.mu.Lock()
+	delete(b.clients, c)
+	b.mu.Unlock()
+	c.close()
+}
+
+func (b *Broadcaster) closeAll() {
+	b.mu.Lock()
+	clients := make([]*wsClient, 0, len(b.clients))
+	for c := range b.clients {
+		clients = append(clients, c)
+	}
+	b.mu.Unlock()
+
+	for _, c := range clients {
+		c.close()
+	}
+}
+
+type broadcastMsg struct {
+	Type   string    `json:"type"`
+	Symbol string    `json:"symbol"`
+	Data   *Snapshot `json:"data"`
+}
+
+func (b *Broadcaster) broadcast(now time.Time) {
+	b.mu.Lock()
+	clients := make([]*wsClient, 0, len(b.clients))
+	for c := range b.clients {
+		clients = append(clients, c)
+	}
+	b.mu.Unlock()
+	if len(clients) == 0 {
+		return
+	}
+
+	snaps := b.m.SnapshotMany(nil)
+	encoded := make(map[string][]byte, len(snaps))
+	for sym, s := range snaps {
+		msg, err := json.Marshal(broadcastMsg{Type: "snapshot", Symbol: sym, Data: s})
+		if err != nil {
+			continue
+		}
+		encoded[sym] = msg
+	}
+
+	for _, c := range clients {
+		for sym, msg := range encoded {
+			if !c.due(sym, now) {
+				continue
+			}
+			select {
+			case c.send <- msg:
+			default:
+				b.dropped.Add(1)
+			}
+		}
+	}
+}
+
+// wsClient 一个订阅连接
+type wsClient struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+
+	mu       sync.Mutex
+	all      bool // 连接时未指定 symbols：接收全部，订阅具体 symbol 后取消
+	symbols  map[string]bool
+	throttle time.Duration
+	lastSent map[string]time.Time
+	wmu      sync.Mutex // 保护 rw.Writer（写协程与 pong 共用）
+}
+
+func newWSClient(r *http.Request) *wsClient {
+	c := &wsClient{
+		send:     make(chan []byte, broadcastQueue),
+		done:     make(chan struct{}),
+		symbols:  make(map[string]bool),
+		lastSent: make(map[string]time.Time),
+	}
+	q := r.URL.Query()
+	for _, s := range strings.Split(q.Get("symbols"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.symbols[s] = true
+		}
+	}
+	c.all = len(c.symbols) == 0
+	if d, err := time.ParseDuration(q.Get("throttle")); err == nil && d > 0 {
+		c.throttle = d
+	}
+	return c
+}
+
+// due 判断 symbol 是否在过滤范围内且已过节流间隔，是则记录本次发送时间
+func (c *wsClient) due(sym string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.all && !c.symbols[sym] {
+		return false
+	}
+	if c.throttle > 0 {
+		if last, ok := c.lastSent[sym]; ok && now.Sub(last) < c.throttle {
+			return false
+		}
+	}
+	c.lastSent[sym] = now
+	return true
+}
+
+type wsCommand struct {
+	Op      string   `json:"op"`
+	Symbols []string `json:"symbols"`
+	Ms      int64    `json:"ms"`
+}
+
+func (c *wsClient) apply(cmd wsCommand) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch cmd.Op {
+	case "subscribe":
+		for _, s := range cmd.Symbols {
+			c.symbols[s] = true
+		}
+		if len(cmd.Symbols) > 0 {
+			c.all = false
+		}
+	case "unsubscribe":
+		for _, s := range cmd.Symbols {
+			delete(c.symbols, s)
+		}
+	case "throttle":
+		c.throttle = time.Duration(max(cmd.Ms, 0)) * time.Millisecond
+	}
+}
+
+func (c *wsClient) readLoop() {
+	for {
+		op, payload, err := wsReadFrame(c.rw.Reader)
+		if errors.Is(err, errWSProtocol) {
+			_ = c.writeFrame(wsOpClose, wsCloseCode(wsCloseProtocolError))
+			return
+		}
+		if err != nil {
+			return
+		}
+		switch op {
+		case wsOpText:
+			var cmd wsCommand
+			if json.Unmarshal(payload, &cmd) == nil {
+				c.apply(cmd)
+			}
+		case wsOpPing:
+			if c.writeFrame(wsOpPong, payload) != nil {
+				return
+			}
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, nil)
+			return
+		}
+	}
+}
+
+func (c *wsClient) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			if c.writeFrame(wsOpText, msg) != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(broadcastWriteTimeout))
+	return wsWriteFrame(c.rw.Writer, op, payload)
+}
+
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
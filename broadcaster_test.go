@@ -0,0 +1,145 @@
+package sliding_window
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster_FilteredPush(t *testing.T) {
+	m := NewManager(func(string) *SlidingWindow { return NewSlidingWindow(time.Minute, 64, 0.1) })
+	base := time.Unix(1_700_000_000, 0)
+	for _, sym := range []string{"BTC", "ETH"} {
+		w := m.GetOrCreate(sym)
+		for i := 0; i < 10; i++ {
+			w.AddWindowPoint(SideBuy, 100+float64(i), 1, base.Add(time.Duration(i)*time.Second))
+		}
+	}
+
+	b := NewBroadcaster(m, time.Hour)
+	srv := httptest.NewServer(b)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	req := "GET /?symbols=ETH HTTP/1.1\r\nHost: x\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake failed: %v %v", resp, err)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("accept = %q", got)
+	}
+
+	for deadline := time.Now().Add(time.Second); b.Clients() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("client not registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.broadcast(time.Now())
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var hdr [4]byte
+	if _, err := io.ReadFull(br, hdr[:2]); err != nil {
+		t.Fatal(err)
+	}
+	n := int(hdr[1] & 0x7F)
+	if n == 126 {
+		if _, err := io.ReadFull(br, hdr[2:4]); err != nil {
+			t.Fatal(err)
+		}
+		n = int(hdr[2])<<8 | int(hdr[3])
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg broadcastMsg
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Symbol != "ETH" || msg.Data == nil || msg.Data.LatestPrice != 109 {
+		t.Fatalf("unexpected message %s", payload)
+	}
+}
+
+func TestWSClient_Subscriptions(t *testing.T) {
+	now := time.Now()
+	c := newWSClient(httptest.NewRequest(http.MethodGet, "/", nil))
+	if !c.due("BTC", now) || !c.due("ETH", now) {
+		t.Fatal("client without symbols should receive everything")
+	}
+
+	c.apply(wsCommand{Op: "subscribe", Symbols: []string{"BTC"}})
+	if !c.due("BTC", now) || c.due("ETH", now) {
+		t.Fatal("subscribe should narrow to the listed symbols")
+	}
+	c.apply(wsCommand{Op: "unsubscribe", Symbols: []string{"BTC"}})
+	if c.due("BTC", now) || c.due("ETH", now) {
+		t.Fatal("unsubscribing from the last symbol should stop all pushes")
+	}
+}
+
+func TestBroadcaster_OriginCheck(t *testing.T) {
+	b := NewBroadcaster(NewManager(nil), time.Hour)
+	srv := httptest.NewServer(b)
+	defer srv.Close()
+
+	upgrade := func(origin string) int {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// 同源请求通过 Origin 校验，随后因缺少升级头被拒
+	if code := upgrade(srv.URL); code != http.StatusBadRequest {
+		t.Fatalf("same origin: status %d", code)
+	}
+	if code := upgrade("https://evil.example"); code != http.StatusForbidden {
+		t.Fatalf("cross origin: status %d", code)
+	}
+	b.SetOriginCheck(func(r *http.Request) bool { return r.Header.Get("Origin") == "https://evil.example" })
+	if code := upgrade("https://evil.example"); code != http.StatusBadRequest {
+		t.Fatalf("allow-listed origin: status %d", code)
+	}
+}
+
+func TestWSReadFrame_ProtocolErrors(t *testing.T) {
+	for name, b0 := range map[string]byte{
+		"rsv":          0x80 | 0x40 | wsOpText,
+		"fragment":     wsOpText, // FIN = 0
+		"continuation": 0x80 | wsOpCont,
+	} {
+		frame := []byte{b0, 0x80, 0, 0, 0, 0}
+		if _, _, err := wsReadFrame(bufio.NewReader(bytes.NewReader(frame))); !errors.Is(err, errWSProtocol) {
+			t.Fatalf("%s: err = %v", name, err)
+		}
+	}
+	ok := []byte{0x80 | wsOpText, 0x80 | 2, 0, 0, 0, 0, 'h', 'i'}
+	if op, p, err := wsReadFrame(bufio.NewReader(bytes.NewReader(ok))); err != nil || op != wsOpText || string(p) != "hi" {
+		t.Fatalf("valid frame: %v %q %v", op, p, err)
+	}
+}
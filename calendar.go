@@ -0,0 +1,169 @@
+package sliding_window
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionCalendar 交易时段日历：用于把休市/维护时段从按时间归一化的指标中剔除
+type SessionCalendar interface {
+	IsOpen(t time.Time) bool
+	// OpenDuration [from, to) 区间内的开市时长
+	OpenDuration(from, to time.Time) time.Duration
+}
+
+// CalendarIdentifier 可选接口：日历的稳定标识，写入配置指纹（见 WindowConfig）。
+// 未实现时按类型与字段值生成，字段里含指针的日历应当实现该接口
+type CalendarIdentifier interface {
+	CalendarID() string
+}
+
+// AlwaysOpen 7x24 连续交易（加密货币默认）
+type AlwaysOpen struct{}
+
+func (AlwaysOpen) CalendarID() string { return "always_open" }
+
+func (AlwaysOpen) IsOpen(time.Time) bool { return true }
+
+func (AlwaysOpen) OpenDuration(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// WeeklySpan 每周重复的休市区间；EndDay 早于 StartDay 表示跨周（例如周五收盘到周日开盘）
+type WeeklySpan struct {
+	StartDay time.Weekday
+	Start    time.Duration // 当天 00:00 起的偏移
+	EndDay   time.Weekday
+	End      time.Duration
+}
+
+// WeeklyCalendar 按周重复的休市日历（CME 风格：每日维护 + 周末休市）
+type WeeklyCalendar struct {
+	Location *time.Location
+	Closed   []WeeklySpan
+}
+
+// NewCMECalendar CME Globex 常规日历（芝加哥时间）：
+// 周一至周四 16:00-17:00 每日维护，周五 16:00 至周日 17:00 休市
+func NewCMECalendar() (*WeeklyCalendar, error) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &WeeklyCalendar{Location: loc}
+	for d := time.Monday; d <= time.Thursday; d++ {
+		cal.Closed = append(cal.Closed, WeeklySpan{StartDay: d, Start: 16 * time.Hour, EndDay: d, End: 17 * time.Hour})
+	}
+	cal.Closed = append(cal.Closed, WeeklySpan{StartDay: time.Friday, Start: 16 * time.Hour, EndDay: time.Sunday, End: 17 * time.Hour})
+	return cal, nil
+}
+
+// CalendarID 时区名加各休市区间，例如 "weekly:America/Chicago:1@16h0m0s-1@17h0m0s,..."
+func (c *WeeklyCalendar) CalendarID() string {
+	var b strings.Builder
+	b.WriteString("weekly:")
+	b.WriteString(c.loc().String())
+	for i, s := range c.Closed {
+		if i == 0 {
+			b.WriteByte(':')
+		} else {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%d@%v-%d@%v", s.StartDay, s.Start, s.EndDay, s.End)
+	}
+	return b.String()
+}
+
+func (c *WeeklyCalendar) loc() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// weekStart t 所在周的周日 00:00（日历时区）
+func (c *WeeklyCalendar) weekStart(t time.Time) time.Time {
+	t = t.In(c.loc())
+	y, m, d := t.Date()
+	return time.Date(y, m, d-int(t.Weekday()), 0, 0, 0, 0, c.loc())
+}
+
+// spanAt 以 ws 为周起点实例化一个休市区间
+func (c *WeeklyCalendar) spanAt(ws time.Time, s WeeklySpan) (time.Time, time.Time) {
+	y, m, d := ws.Date()
+	start := time.Date(y, m, d+int(s.StartDay), 0, 0, 0, 0, c.loc()).Add(s.Start)
+	endDay := int(s.EndDay)
+	if s.EndDay < s.StartDay || (s.EndDay == s.StartDay && s.End <= s.Start) {
+		endDay += 7
+	}
+	end := time.Date(y, m, d+endDay, 0, 0, 0, 0, c.loc()).Add(s.End)
+	return start, end
+}
+
+func (c *WeeklyCalendar) IsOpen(t time.Time) bool {
+	ws := c.weekStart(t)
+	// 上一周的跨周区间可能覆盖本周开头
+	for _, w := range []time.Time{ws.AddDate(0, 0, -7), ws} {
+		for _, s := range c.Closed {
+			start, end := c.spanAt(w, s)
+			if !t.Before(start) && t.Before(end) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *WeeklyCalendar) OpenDuration(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	total := to.Sub(from)
+	for ws := c.weekStart(from).AddDate(0, 0, -7); ws.Before(to); ws = ws.AddDate(0, 0, 7) {
+		for _, s := range c.Closed {
+			start, end := c.spanAt(ws, s)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				total -= end.Sub(start)
+			}
+		}
+	}
+	if total < 0 {
+		total = 0
+	}
+	return total
+}
+
+// SetCalendar 设置交易时段日历（写锁），nil 表示 7x24。
+// 影响 VolumePerSecond、TWAP 等按时间归一化的指标；递增版本号，Snapshot 缓存随之失效。
+func (w *SlidingWindow) SetCalendar(cal SessionCalendar) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.calendar = cal
+	w.rebuildTWAPUnlocked()
+	w.refreshVolumeCachesUnlocked()
+	w.bumpVersionUnlocked()
+}
+
+// openDurationUnlocked 按日历计算 [from, to) 的有效交易时长（要求持有读锁）
+func (w *SlidingWindow) openDurationUnlocked(from, to time.Time) time.Duration {
+	if w.calendar == nil {
+		if !to.After(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	return w.calendar.OpenDuration(from, to)
+}
@@ -0,0 +1,51 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklyCalendar_OpenDuration(t *testing.T) {
+	cal := &WeeklyCalendar{
+		Location: time.UTC,
+		Closed: []WeeklySpan{
+			{StartDay: time.Wednesday, Start: 16 * time.Hour, EndDay: time.Wednesday, End: 17 * time.Hour},
+			{StartDay: time.Friday, Start: 21 * time.Hour, EndDay: time.Sunday, End: 22 * time.Hour},
+		},
+	}
+
+	// 2024-01-03 是周三
+	from := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC)
+	if got := cal.OpenDuration(from, to); got != 2*time.Hour {
+		t.Fatalf("daily break: got %v want 2h", got)
+	}
+	if cal.IsOpen(time.Date(2024, 1, 3, 16, 30, 0, 0, time.UTC)) {
+		t.Fatal("16:30 should be inside the break")
+	}
+
+	// 跨周末：周五 20:00 到周一 00:00，休市 49h
+	from = time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC)
+	to = time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	if got := cal.OpenDuration(from, to); got != 3*time.Hour {
+		t.Fatalf("weekend: got %v want 3h", got)
+	}
+	if cal.IsOpen(time.Date(2024, 1, 7, 12, 0, 0, 0, time.UTC)) {
+		t.Fatal("sunday noon should be closed")
+	}
+}
+
+func TestSetCalendar_InvalidatesSnapshot(t *testing.T) {
+	w := NewSlidingWindow(time.Hour, 64, 0.1)
+	t0 := time.Date(2024, 1, 3, 15, 50, 0, 0, time.UTC) // 周三，16:00 起休市一小时
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*5*time.Minute))
+	}
+	s1 := w.Snapshot()
+
+	w.SetCalendar(&WeeklyCalendar{Closed: []WeeklySpan{{StartDay: time.Wednesday, Start: 16 * time.Hour, EndDay: time.Wednesday, End: 17 * time.Hour}}})
+	s2 := w.Snapshot()
+	if s1 == nil || s2 == nil || s2.Version == s1.Version || s2.Version != w.Version() {
+		t.Fatalf("snapshot should be recomputed after SetCalendar: %d -> %d (window %d)", s1.Version, s2.Version, w.Version())
+	}
+}
@@ -0,0 +1,128 @@
+package sliding_window
+
+import (
+	"fmt"
+	"math"
+)
+
+type IssueCode string
+
+const (
+	IssueSizeOverflow   IssueCode = "size_overflow"
+	IssueSumMismatch    IssueCode = "sum_mismatch"
+	IssueTradeCount     IssueCode = "trade_count"
+	IssueHighBelowLow   IssueCode = "high_below_low"
+	IssueNonMonotonicTs IssueCode = "non_monotonic_ts"
+	IssueStaleHighLow   IssueCode = "stale_high_low"
+)
+
+// Issue 自检发现的一条不变量问题
+type Issue struct {
+	Code    IssueCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+func (i Issue) String() string {
+	return string(i.Code) + ": " + i.Message
+}
+
+// checkSumTolerance sumVolume 与重算值之间允许的相对误差（整数累加理论上应完全一致）
+const checkSumTolerance = 1e-9
+
+// Check 校验窗口内部不变量（读锁），返回空切片表示健康。
+// 适合 readiness probe 或定时自检，开销为 O(n)。
+func (w *SlidingWindow) Check() []Issue {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var issues []Issue
+
+	// 1) size 不能超过容量
+	if w.size < 0 || w.size > w.capacityUnlocked() {
+		issues = append(issues, Issue{
+			Code:    IssueSizeOverflow,
+			Message: fmt.Sprintf("size=%d capacity=%d", w.size, w.capacityUnlocked()),
+		})
+		// 下面的遍历依赖 size 合法，直接返回
+		return issues
+	}
+
+	// 2) 重算成交量总和
+	var sum, sumClamped int64
+	backward := 0
+	trades := int64(0)
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		if !w.isQuoteUnlocked(pt) {
+			trades += pt.Trades()
+		}
+		v := pt.Volume.Int64()
+		sum += v
+		if v > 0 {
+			sumClamped += v
+		}
+		if i > 0 && pt.Ts.Before(w.atUnlocked(i-1).Ts) {
+			backward++
+		}
+	}
+
+	if !withinTolerance(float64(sum), float64(w.sumVolume)) {
+		issues = append(issues, Issue{
+			Code:    IssueSumMismatch,
+			Message: fmt.Sprintf("sumVolume=%d recomputed=%d", int64(w.sumVolume), sum),
+		})
+	}
+	if sv := w.SumV.Load(); !withinTolerance(float64(sumClamped), float64(sv)) {
+		issues = append(issues, Issue{
+			Code:    IssueSumMismatch,
+			Message: fmt.Sprintf("SumV=%d recomputed=%d", sv, sumClamped),
+		})
+	}
+
+	// 3) 成交笔数与有效点数一致（零量报价不计笔数）
+	if n := w.nTrades.Load(); n != trades {
+		issues = append(issues, Issue{
+			Code:    IssueTradeCount,
+			Message: fmt.Sprintf("nTrades=%d size=%d trades=%d", n, w.size, trades),
+		})
+	}
+
+	// 4) high >= low
+	hi, lo := w.HighestPrice.Load(), w.LowestPrice.Load()
+	if hi < lo {
+		issues = append(issues, Issue{
+			Code:    IssueHighBelowLow,
+			Message: fmt.Sprintf("high=%d low=%d", hi, lo),
+		})
+	}
+	if w.size == 0 && !w.hiLoDirty && (hi != 0 || lo != 0) {
+		issues = append(issues, Issue{
+			Code:    IssueStaleHighLow,
+			Message: fmt.Sprintf("empty window keeps high=%d low=%d", hi, lo),
+		})
+	}
+
+	// 5) 时间戳基本单调（乱序点会让 trim 的假设失效）
+	if backward > 0 {
+		issues = append(issues, Issue{
+			Code:    IssueNonMonotonicTs,
+			Message: fmt.Sprintf("%d points older than their predecessor", backward),
+		})
+	}
+
+	return issues
+}
+
+// Healthy Check 的便捷包装
+func (w *SlidingWindow) Healthy() bool {
+	return len(w.Check()) == 0
+}
+
+func withinTolerance(a, b float64) bool {
+	diff := math.Abs(a - b)
+	if diff == 0 {
+		return true
+	}
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	return diff <= scale*checkSumTolerance
+}
@@ -0,0 +1,35 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow_Check(t *testing.T) {
+	w := NewSlidingWindow(time.Second, 64, 0.1)
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("empty window should be healthy, got %v", issues)
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 200; i++ {
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i%7)*0.5, 1.25, base.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+
+	// 人为破坏 sumVolume
+	w.mu.Lock()
+	w.sumVolume += NewQtyLoz(0.5, w.volumeScale)
+	w.mu.Unlock()
+
+	issues := w.Check()
+	if len(issues) == 0 || issues[0].Code != IssueSumMismatch {
+		t.Fatalf("expected sum mismatch, got %v", issues)
+	}
+}
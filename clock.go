@@ -0,0 +1,86 @@
+package sliding_window
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源：Snapshot 的时间戳、Expirer 的过期时间等都从这里取，
+// 回测和单元测试可以换成模拟时间
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock 系统时钟（默认）
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// ManualClock 手动推进的时钟，并发安全
+type ManualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{t: t}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Set 把时钟设为 t
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// Advance 把时钟向前推进 d，返回推进后的时间
+func (c *ManualClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+	return c.t
+}
+
+// clockRef 让 Clock 接口值可以放进 atomic.Pointer
+type clockRef struct{ c Clock }
+
+// SetClock 设置窗口的时间来源（无锁），nil 恢复系统时钟
+func (w *SlidingWindow) SetClock(c Clock) {
+	if c == nil {
+		w.clock.Store(nil)
+		return
+	}
+	w.clock.Store(&clockRef{c: c})
+}
+
+// now 窗口时钟的当前时间
+func (w *SlidingWindow) now() time.Time {
+	if r := w.clock.Load(); r != nil {
+		return r.c.Now()
+	}
+	return time.Now()
+}
+
+// SetClock 设置 Manager 自身的时间来源（无锁）：SnapshotMany / Query 的 BatchTs、Breadth.Ts、
+// AdaptiveScheduler 的触发时刻都从这里取；nil 恢复系统时钟。各窗口的时钟仍由窗口的 SetClock 决定
+func (m *Manager) SetClock(c Clock) {
+	if c == nil {
+		m.clock.Store(nil)
+		return
+	}
+	m.clock.Store(&clockRef{c: c})
+}
+
+// now Manager 时钟的当前时间
+func (m *Manager) now() time.Time {
+	if r := m.clock.Load(); r != nil {
+		return r.c.Now()
+	}
+	return time.Now()
+}
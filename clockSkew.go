@@ -0,0 +1,150 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// skewStats 交易所时间戳与本地接收时间戳之差的增量统计（随窗口加减）
+// x = 交易所时间（相对 anchor 的秒数），y = offset 秒数 (RecvTs - Ts)。
+// 接收时间不存进环形数组，样本另存在 samples 里，头部点出窗时一并减去
+type skewStats struct {
+	anchor                time.Time
+	n                     int64
+	sx, sy, sxx, sxy, syy float64
+
+	samples  []skewSample // 窗口内的样本，按到达顺序，head 之前已移出
+	head     int
+	lastRecv time.Time // 当前批次中最晚的接收时间，供偏差修正推算交易所当前时间
+}
+
+type skewSample struct {
+	ts, recv time.Time
+}
+
+func (s *skewStats) apply(ts, recv time.Time, sign float64) {
+	if s.anchor.IsZero() {
+		s.anchor = ts
+	}
+	x := ts.Sub(s.anchor).Seconds()
+	y := recv.Sub(ts).Seconds()
+
+	s.n += int64(sign)
+	s.sx += sign * x
+	s.sy += sign * y
+	s.sxx += sign * x * x
+	s.sxy += sign * x * y
+	s.syy += sign * y * y
+
+	if s.n == 0 {
+		// 清空后重置 anchor，避免长期运行时 x 越来越大损失精度
+		s.anchor = time.Time{}
+		s.sx, s.sy, s.sxx, s.sxy, s.syy = 0, 0, 0, 0, 0
+	}
+}
+
+// observe 记入一个样本
+func (s *skewStats) observe(ts, recv time.Time) {
+	if recv.IsZero() || ts.IsZero() {
+		return
+	}
+	s.samples = append(s.samples, skewSample{ts: ts, recv: recv})
+	s.apply(ts, recv, 1)
+}
+
+// forget 减去成交时间早于 oldest 的样本；oldest 为零值时全部减去
+func (s *skewStats) forget(oldest time.Time) {
+	for s.head < len(s.samples) && (oldest.IsZero() || s.samples[s.head].ts.Before(oldest)) {
+		sm := s.samples[s.head]
+		s.apply(sm.ts, sm.recv, -1)
+		s.samples[s.head] = skewSample{}
+		s.head++
+	}
+	// 已移出部分过半时压缩，避免底层数组无限增长
+	if s.head > len(s.samples)/2 {
+		n := copy(s.samples, s.samples[s.head:])
+		s.samples = s.samples[:n]
+		s.head = 0
+	}
+}
+
+// forgetSkewUnlocked 随头部出窗减去样本（要求持有写锁）
+func (w *SlidingWindow) forgetSkewUnlocked() {
+	if w.skew.n == 0 {
+		return
+	}
+	var oldest time.Time
+	if w.size > 0 {
+		oldest = w.atUnlocked(0).Ts
+	}
+	w.skew.forget(oldest)
+}
+
+func (s *skewStats) meanOffset() (float64, bool) {
+	if s.n <= 0 {
+		return 0, false
+	}
+	return s.sy / float64(s.n), true
+}
+
+// ClockSkew 窗口内的时钟偏差估计
+type ClockSkew struct {
+	Offset   time.Duration `json:"offset"`    // 平均 RecvTs - Ts（含网络延迟）
+	StdDev   time.Duration `json:"std_dev"`   // offset 标准差（抖动）
+	DriftPPM float64       `json:"drift_ppm"` // offset 对交易所时间的回归斜率，百万分之一
+	Samples  int64         `json:"samples"`
+}
+
+// ClockSkew 估计交易所时钟与本地接收时钟的偏差和漂移（读锁），只统计经 AddTrades 写入、带 RecvTs 且仍在窗口内的成交
+//
+//metric:name=clock_skew unit=duration cost=O(1) min_points=2 requires=recv_ts
+func (w *SlidingWindow) ClockSkew() (ClockSkew, bool) {
+	if !w.IsReady() {
+		return ClockSkew{}, false
+	}
+
+	w.mu.RLock()
+	s := w.skew
+	w.mu.RUnlock()
+
+	var out ClockSkew
+	if s.n < 2 {
+		return out, false
+	}
+
+	n := float64(s.n)
+	mean := s.sy / n
+	variance := math.Max(0, s.syy/n-mean*mean)
+
+	out.Offset = time.Duration(mean * float64(time.Second))
+	out.StdDev = time.Duration(math.Sqrt(variance) * float64(time.Second))
+	out.Samples = s.n
+
+	if varX := s.sxx - s.sx*s.sx/n; varX > 0 {
+		out.DriftPPM = (s.sxy - s.sx*s.sy/n) / varX * 1e6
+	}
+	return out, true
+}
+
+// SetSkewCorrection 开启后，淘汰边界使用“最新接收时间 - 平均偏差”推算的交易所当前时间
+// （若它比批次内最新的交易所时间更晚），避免交易所时间戳滞后时旧点迟迟不过期（写锁）
+func (w *SlidingWindow) SetSkewCorrection(on bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.skewCorrect = on
+}
+
+// evictionNowUnlocked 计算本批次的淘汰参考时间（要求持有写锁）
+func (w *SlidingWindow) evictionNowUnlocked(last WindowPoint) time.Time {
+	now := last.Ts
+	recv := w.skew.lastRecv
+	if !w.skewCorrect || recv.IsZero() || w.skew.n < 2 {
+		return now
+	}
+	off, _ := w.skew.meanOffset()
+	est := recv.Add(-time.Duration(off * float64(time.Second)))
+	if est.After(now) {
+		return est
+	}
+	return now
+}
@@ -0,0 +1,75 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	clk := NewManualClock(t0)
+
+	w := NewSlidingWindow(10*time.Second, 64, 0.1)
+	w.SetClock(clk)
+	for s := 0; s < 5; s++ {
+		w.AddWindowPoint(SideBuy, 100+float64(s), 1, t0.Add(time.Duration(s)*time.Second))
+	}
+
+	if s := w.Snapshot(); s == nil || s.Ts != t0.UnixMilli() {
+		t.Fatalf("snapshot ts should come from the clock: %+v", s)
+	}
+
+	e := NewExpirer(time.Second, w)
+	e.SetClock(clk)
+	clk.Advance(12 * time.Second)
+	e.tick()
+	if got := w.SumVolume(); got != 2 {
+		t.Fatalf("sum volume = %v after expiry at simulated time, want 2", got)
+	}
+	if s := w.Snapshot(); s == nil || s.Ts != t0.Add(12*time.Second).UnixMilli() {
+		t.Fatalf("snapshot ts = %+v", s)
+	}
+}
+
+func TestExpirer_UsesWindowClock(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	clk := NewManualClock(t0)
+
+	w := NewSlidingWindow(10*time.Second, 64, 0.1)
+	w.SetClock(clk)
+	for s := 0; s < 5; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second))
+	}
+	m := NewManager(nil)
+	m.Set("A", w)
+
+	// 系统时钟远晚于成交时间，不按窗口时钟会清空窗口
+	NewExpirer(time.Second, m).tick()
+	if got := w.SumVolume(); got != 5 {
+		t.Fatalf("sum volume = %v, expirer should use the window clock", got)
+	}
+	clk.Advance(12 * time.Second)
+	NewExpirer(time.Second, w).tick()
+	if got := w.SumVolume(); got != 2 {
+		t.Fatalf("sum volume = %v after expiry at window time, want 2", got)
+	}
+}
+
+func TestManager_Clock(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	m := NewWindowManager(time.Minute, 64, 0.1)
+	m.SetClock(NewManualClock(t0))
+	for s := 0; s < 5; s++ {
+		m.GetOrCreate("A").AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second))
+	}
+
+	if s := m.SnapshotMany(nil)["A"]; s == nil || s.BatchTs != t0.UnixMilli() {
+		t.Fatalf("snapshot many batch ts should come from the manager clock: %+v", s)
+	}
+	if rows, _ := m.Query(Filter{}, SortSymbol, 0, 0); len(rows) != 1 || rows[0].Snapshot.BatchTs != t0.UnixMilli() {
+		t.Fatalf("query batch ts should come from the manager clock: %+v", rows)
+	}
+	if b := m.Breadth(); b.Ts != t0.UnixMilli() {
+		t.Fatalf("breadth ts = %d, want %d", b.Ts, t0.UnixMilli())
+	}
+}
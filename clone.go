@@ -0,0 +1,186 @@
+package sliding_window
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone 在读锁下深拷贝窗口：点、增量统计、EMA 及各项配置，得到与原窗口互不影响的独立副本，
+// 可以在副本上做假设性写入或耗时分析而不阻塞原窗口的写入。
+// 回调（大单、出窗、panic 处理）、归档目标、挂载的后台组件、复制日志、写入限速状态和诊断记录不复制；
+// 历史画像、日历、风险约束、聚合模型、时钟、标签等只读配置与原窗口共享
+func (w *SlidingWindow) Clone() *SlidingWindow {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	c := &SlidingWindow{
+		duration:     w.duration,
+		size:         w.size,
+		sumVolume:    w.sumVolume,
+		ema:          cloneEMA(w.ema),
+		emaLong:      cloneEMA(w.emaLong),
+		emaDecay:     w.emaDecay,
+		lastTradeTs:  w.lastTradeTs,
+		volumeScale:  w.volumeScale,
+		priceScale:   w.priceScale,
+		hiLoDirty:    w.hiLoDirty,
+		markers:      slices.Clone(w.markers),
+		profile:      w.profile,
+		sampling:     w.sampling,
+		skew:         w.skew.clone(),
+		skewCorrect:  w.skewCorrect,
+		burstGap:     w.burstGap,
+		calendar:     w.calendar,
+		sumNotional:  w.sumNotional,
+		buyNotional:  w.buyNotional,
+		sellNotional: w.sellNotional,
+		arrival:      w.arrival,
+		barInterval:  w.barInterval,
+		twap:         w.twap,
+		blend:        w.blend,
+		imbEMAs:      slices.Clone(w.imbEMAs),
+		ingest:       ingestLimit{n: w.ingest.n, interval: w.ingest.interval},
+		tracked:      w.tracked,
+		sizes:        w.sizes,
+		lastPct:      w.lastPct,
+		lastPctOK:    w.lastPctOK,
+		largePct:     w.largePct,
+		risk:         w.risk,
+		openRange:    w.openRange,
+		zeroVol:      w.zeroVol,
+		runSpan:      w.runSpan,
+		runTailTs:    w.runTailTs,
+		dupTs:        w.dupTs,
+		seqTail:      w.seqTail,
+		readyPolicy:  w.readyPolicy,
+		lifetime:     w.lifetime,
+		evict:        w.evict,
+		maxPoints:    w.maxPoints,
+		aggression:   w.aggression,
+		reorder:      w.reorder,
+		anchored:     w.anchored,
+		dedup:        w.dedup.clone(),
+		tags:         w.tags,
+		negative:     negativeVolume{policy: w.negative.policy, trades: w.negative.trades.clone()},
+		own:          ownFlow{fills: slices.Clone(w.own.fills)},
+	}
+	c.pricesPool.New = w.pricesPool.New
+	c.blend.series = slices.Clone(w.blend.series)
+	c.reorder.pending = slices.Clone(w.reorder.pending)
+	c.reorder.seqs = slices.Clone(w.reorder.seqs)
+	c.reorder.outSeqs = nil
+	c.seqTail.seqs = slices.Clone(w.seqTail.seqs)
+	c.reorder.out = nil
+	c.excursions = w.excursions.clone()
+
+	if w.seg != nil {
+		c.seg = newSegmentStore(w.seg.segSize)
+		for i := 0; i < w.size; i++ {
+			c.seg.push(w.atUnlocked(i), i)
+		}
+	} else {
+		c.ring = w.ring.clone()
+	}
+	if w.sizeBuckets != nil {
+		sb := *w.sizeBuckets
+		sb.breaks = slices.Clone(sb.breaks)
+		sb.acc = slices.Clone(sb.acc)
+		c.sizeBuckets = &sb
+	}
+	if w.compact != nil {
+		cp := *w.compact
+		c.compact = &cp
+	}
+	if w.priceHist != nil {
+		ph := *w.priceHist
+		ph.counts = maps.Clone(ph.counts)
+		c.priceHist = &ph
+	}
+
+	c.avgVolPerPoint.Store(w.avgVolPerPoint.Load())
+	c.volPerSecond.Store(w.volPerSecond.Load())
+	c.buyVol.Store(w.buyVol.Load())
+	c.sellVol.Store(w.sellVol.Load())
+	c.nTrades.Store(w.nTrades.Load())
+	c.HighestPrice.Store(w.HighestPrice.Load())
+	c.LowestPrice.Store(w.LowestPrice.Load())
+	c.LatestPrice.Store(w.LatestPrice.Load())
+	c.SumV.Store(w.SumV.Load())
+	c.SumPV.Store(w.SumPV.Load())
+	c.version.Store(w.version.Load())
+	c.unready.Store(w.unready.Load())
+	c.volConv.Store(w.volConv.Load())
+	c.clock.Store(w.clock.Load())
+	w.counters.copyTo(&c.counters)
+
+	w.reversionGate.copyTo(&c.reversionGate)
+	w.history.copyTo(&c.history)
+	w.volRegime.mu.Lock()
+	c.volRegime.ema = w.volRegime.ema
+	w.volRegime.mu.Unlock()
+	w.ttlCache.copyTo(&c.ttlCache)
+
+	c.publishUnlocked()
+	return c
+}
+
+func cloneEMA(e *EMA) *EMA {
+	if e == nil {
+		return nil
+	}
+	cp := *e
+	return &cp
+}
+
+func (c *windowCounters) copyTo(dst *windowCounters) {
+	dst.overflows.Store(c.overflows.Load())
+	dst.rejects.Store(c.rejects.Load())
+	dst.recomputes.Store(c.recomputes.Load())
+	dst.evictions.Store(c.evictions.Load())
+	dst.slowAdds.Store(c.slowAdds.Load())
+	dst.merged.Store(c.merged.Load())
+	dst.hookPanics.Store(c.hookPanics.Load())
+	dst.lateDrops.Store(c.lateDrops.Load())
+	dst.duplicates.Store(c.duplicates.Load())
+	dst.negativeRejects.Store(c.negativeRejects.Load())
+	dst.corrections.Store(c.corrections.Load())
+}
+
+func (g *SignalGate) copyTo(dst *SignalGate) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	dst.state, dst.dir, dst.armedAt, dst.firedAt = g.state, g.dir, g.armedAt, g.firedAt
+}
+
+func (h *metricHistory) copyTo(dst *metricHistory) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	dst.n, dst.next, dst.count = h.n, h.next, h.count
+	for i := range h.vals {
+		dst.vals[i] = slices.Clone(h.vals[i])
+	}
+}
+
+// copyTo 只复制 TTL 设置，副本的缓存结果从空开始
+func (m *metricTTLCache) copyTo(dst *metricTTLCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl != nil {
+		dst.ttl = maps.Clone(m.ttl)
+		dst.entries = make(map[string]*ttlEntry)
+	}
+	dst.on.Store(m.on.Load())
+}
+
+func (d tradeIDSet) clone() tradeIDSet {
+	d.seen = maps.Clone(d.seen)
+	d.order = slices.Clone(d.order[d.head:])
+	d.head = 0
+	return d
+}
+
+func (s skewStats) clone() skewStats {
+	s.samples = slices.Clone(s.samples[s.head:])
+	s.head = 0
+	return s
+}
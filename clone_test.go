@@ -0,0 +1,37 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	for name, w := range map[string]*SlidingWindow{
+		"ring":      NewSlidingWindow(time.Minute, 16, 0.1),
+		"segmented": New(time.Minute, 0, WithSegments(4)),
+	} {
+		for i := 0; i < 10; i++ {
+			w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Second))
+		}
+		c := w.Clone()
+		if c.SumVolume() != w.SumVolume() || c.ema.Value != w.ema.Value || c.version.Load() != w.version.Load() {
+			t.Fatalf("%s: clone differs from original", name)
+		}
+
+		// 副本上的写入不影响原窗口
+		c.AddWindowPoint(SideSell, 50, 5, t0.Add(20*time.Second))
+		if w.SumVolume() != 10 || w.size != 10 {
+			t.Fatalf("%s: original changed, sum=%v size=%d", name, w.SumVolume(), w.size)
+		}
+		if c.SumVolume() != 15 || QtyLoz(c.LowestPrice.Load()).Float(c.priceScale) != 50 {
+			t.Fatalf("%s: clone sum=%v", name, c.SumVolume())
+		}
+		if c.ema.Value == w.ema.Value {
+			t.Fatalf("%s: EMA should be independent", name)
+		}
+		if issues := c.Check(); len(issues) != 0 {
+			t.Fatalf("%s: check: %v", name, issues)
+		}
+	}
+}
@@ -4,7 +4,7 @@ import "math"
 
 func (w *SlidingWindow) structuralReturn() (float64, bool) {
 
-	if w.size <= 0 || len(w.buf) <= 0 {
+	if w.size <= 0 || w.capacityUnlocked() <= 0 {
 		return 0, false
 	}
 
@@ -20,13 +20,25 @@ func (w *SlidingWindow) structuralReturn() (float64, bool) {
 }
 
 // DeltaVolume: buy - sell （单位：成交量，已经除以 volumeScale 后的真实值）
+//
+//metric:name=delta_volume unit=volume cost=O(1) min_points=0
 func (w *SlidingWindow) DeltaVolume() float64 {
+	if w.volConv.Load() != nil {
+		// 设置了合约乘数：按展示单位换算（需要读锁拿名义金额累加器）
+		w.mu.RLock()
+		_, bv, sv := w.volumesUnlocked()
+		w.mu.RUnlock()
+		return bv - sv
+	}
+
 	bv := float64(w.buyVol.Load()) / float64(w.volumeScale)
 	sv := float64(w.sellVol.Load()) / float64(w.volumeScale)
 	return bv - sv
 }
 
 // Imbalance: (buy - sell) / (buy + sell)，范围 [-1, 1]
+//
+//metric:name=imbalance unit=ratio cost=O(1) min_points=0
 func (w *SlidingWindow) Imbalance() float64 {
 	bv := float64(w.buyVol.Load()) / float64(w.volumeScale)
 	sv := float64(w.sellVol.Load()) / float64(w.volumeScale)
@@ -38,10 +50,27 @@ func (w *SlidingWindow) Imbalance() float64 {
 }
 
 // RealizedVol: sqrt(sum(log(p_i/p_{i-1})^2))，窗口内 realized vol（不年化）
+//
+//metric:name=realized_vol unit=return cost=O(n) min_points=2
 func (w *SlidingWindow) RealizedVol() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+	if w.metricTTLOn() {
+		return cachedMetric(w, "realized_vol", "realized_vol", w.computeRealizedVol)
+	}
+	return w.computeRealizedVol()
+}
+
+func (w *SlidingWindow) computeRealizedVol() (float64, bool) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	return w.realizedVolUnlocked()
+}
+
+// realizedVolUnlocked 无锁版（要求调用方已持有 RLock 或 Lock）
+func (w *SlidingWindow) realizedVolUnlocked() (float64, bool) {
 	if w.size < 2 {
 		return 0, false
 	}
@@ -66,3 +95,7 @@ func (w *SlidingWindow) RealizedVol() (float64, bool) {
 
 	return math.Sqrt(sumsq), true
 }
+
+func logRatio(cur, prev float64) float64 {
+	return math.Log(cur / prev)
+}
@@ -0,0 +1,141 @@
+package sliding_window
+
+import (
+	"errors"
+	"time"
+)
+
+// compaction 长窗口省内存模式：最近 recent 内逐笔保存，更早的成交按 bucket 聚合
+type compaction struct {
+	recent time.Duration
+	bucket time.Duration
+	until  time.Time // Ts 早于它的点都已是聚合点
+}
+
+// SetCompaction 开启长窗口省内存模式（写锁）：最近 recent 内保留逐笔成交，更早的部分
+// （仍在窗口内）按 bucket（<= 0 时取 1 秒）把同方向成交合并为一个点：价格取该桶 VWAP，
+// 成交量、成交额、笔数、买卖量保持精确。30–60 分钟的窗口可以大幅减少占用，短周期指标不受影响。
+//
+// 聚合点以桶起点为时间戳，因此窗口左边界的精度为 bucket；高低点、中位数等价格统计在聚合区间
+// 内按桶 VWAP 计算。recent <= 0 关闭（已聚合的点保持不变）
+func (w *SlidingWindow) SetCompaction(recent, bucket time.Duration) error {
+	if bucket <= 0 {
+		bucket = time.Second
+	}
+	if recent > 0 && recent < bucket {
+		return errors.New("compaction: recent must be at least one bucket")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if recent <= 0 {
+		w.compact = nil
+		return nil
+	}
+	w.compact = &compaction{recent: recent, bucket: bucket}
+	w.compactUnlocked()
+	w.bumpVersionUnlocked()
+	return nil
+}
+
+// compactUnlocked 把越过 recent 边界的完整桶聚合（要求持有写锁）
+func (w *SlidingWindow) compactUnlocked() {
+	c := w.compact
+	if c == nil || w.size == 0 {
+		return
+	}
+	boundary := w.lastUnlocked().Ts.Add(-c.recent).Truncate(c.bucket)
+	if !boundary.After(c.until) {
+		return
+	}
+	from := w.searchTsUnlocked(c.until)
+	to := w.searchTsUnlocked(boundary)
+	c.until = boundary
+	if to-from < 2 {
+		return
+	}
+
+	// 统计增量替换：先扣除原始点，再计入聚合点，其余统计不受影响
+	raw := make([]WindowPoint, to-from)
+	for i := range raw {
+		raw[i] = w.atUnlocked(from + i)
+		w.applyRemovePointUnlocked(raw[i])
+	}
+	agg := w.aggregateUnlocked(raw, c.bucket)
+	for _, pt := range agg {
+		w.applyAddPointUnlocked(pt)
+	}
+
+	// 原地改写：聚合点写回 [from, from+len(agg))，其后的点整体左移
+	for i, pt := range agg {
+		w.setUnlocked(from+i, pt)
+	}
+	shift := len(raw) - len(agg)
+	for i := to; i < w.size; i++ {
+		w.setUnlocked(i-shift, w.atUnlocked(i))
+	}
+	w.size -= shift
+	if w.seg != nil {
+		w.seg.shrink(w.size)
+	}
+
+	w.rebuildTWAPUnlocked()
+	w.excursions.invalidate()
+	w.hiLoDirty = true
+}
+
+// aggregateUnlocked 按 (桶, 方向) 聚合一段按时间排序的点
+func (w *SlidingWindow) aggregateUnlocked(pts []WindowPoint, bucket time.Duration) []WindowPoint {
+	type acc struct {
+		vol, trades int64
+		pv          float64
+		last        QtyLoz
+		used        bool
+	}
+	out := make([]WindowPoint, 0, 8)
+	var cur time.Time
+	var sides [3]acc // 按 Side 取值下标
+
+	flush := func() {
+		for s := range sides {
+			a := &sides[s]
+			if !a.used {
+				continue
+			}
+			px := a.last
+			if a.vol > 0 {
+				px = QtyLoz(int64(a.pv/float64(a.vol) + 0.5))
+			}
+			pt := WindowPoint{Ts: cur, Price: px, Volume: QtyLoz(a.vol), Side: Side(s)}
+			if a.trades > 1 {
+				pt.Count = uint32(a.trades)
+			}
+			out = append(out, pt)
+			*a = acc{}
+		}
+	}
+
+	for i, pt := range pts {
+		b := pt.Ts.Truncate(bucket)
+		if i == 0 || !b.Equal(cur) {
+			flush()
+			cur = b
+		}
+		s := int(pt.Side)
+		if s >= len(sides) {
+			s = int(SideUnknown)
+		}
+		a := &sides[s]
+		a.used = true
+		v := max(pt.Volume.Int64(), 0)
+		a.vol += v
+		a.pv += float64(pt.Price.Int64()) * float64(v)
+		a.last = pt.Price
+		if !w.isQuoteUnlocked(pt) {
+			a.trades += pt.Trades()
+		}
+	}
+	flush()
+	return out
+}
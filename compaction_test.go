@@ -0,0 +1,50 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCompaction(t *testing.T) {
+	plain := NewSlidingWindow(time.Minute, 4096, 0.1)
+	w := NewSlidingWindowSegmented(time.Minute, 64, 0.1)
+	if err := w.SetCompaction(10*time.Second, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 1200; i++ { // 每秒 20 笔，共 60 秒
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		ts := t0.Add(time.Duration(i) * 50 * time.Millisecond)
+		px := 100 + float64(i%7)*0.1
+		plain.AddWindowPoint(side, px, 1+float64(i%4), ts)
+		w.AddWindowPoint(side, px, 1+float64(i%4), ts)
+	}
+
+	// 约 10 秒逐笔（含未满的边界桶）+ 50 秒 × 2 个方向的聚合点
+	if n := w.size; n > 220+2*51 || n >= plain.size {
+		t.Fatalf("size = %d (plain %d)", n, plain.size)
+	}
+	if issues := w.Check(); len(issues) > 0 {
+		t.Fatalf("check: %v", issues)
+	}
+
+	// 量、笔数、买卖量精确保留；VWAP 只有聚合价取整带来的误差
+	if w.SumVolume() != plain.SumVolume() || w.nTrades.Load() != plain.nTrades.Load() ||
+		w.buyVol.Load() != plain.buyVol.Load() || w.sellVol.Load() != plain.sellVol.Load() {
+		t.Fatalf("volumes differ: %v/%v trades %d/%d", w.SumVolume(), plain.SumVolume(), w.nTrades.Load(), plain.nTrades.Load())
+	}
+	a, _ := w.VolumeWeightedAveragePrice()
+	b, _ := plain.VolumeWeightedAveragePrice()
+	if math.Abs(a-b) > 1e-3 {
+		t.Fatalf("vwap %v vs %v", a, b)
+	}
+	// 最近 10 秒仍是逐笔
+	if w.lastUnlocked() != plain.lastUnlocked() {
+		t.Fatal("recent points should stay raw")
+	}
+}
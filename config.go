@@ -0,0 +1,108 @@
+package sliding_window
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// WindowConfig 窗口配置指纹：记录在快照里，让日志中的快照自描述、可跨部署比较
+type WindowConfig struct {
+	DurationMs         int64   `json:"duration_ms"`
+	Capacity           int     `json:"capacity"`               // 环形数组容量，分段存储时为 0
+	SegmentSize        int     `json:"segment_size,omitempty"` // 分段存储的每段点数
+	PriceScale         int64   `json:"price_scale"`
+	VolumeScale        int64   `json:"volume_scale"`
+	EMAAlpha           float64 `json:"ema_alpha"`
+	EMADecayHalfLifeMs int64   `json:"ema_decay_half_life_ms,omitempty"`
+	EvictionPolicy     string  `json:"eviction_policy"`
+	MaxPoints          int     `json:"max_points,omitempty"` // 按点数出窗的上限
+	ZeroVolume         string  `json:"zero_volume"`
+	NegativeVolume     string  `json:"negative_volume"`
+	DuplicateTs        string  `json:"duplicate_ts"`
+	RunLengthMs        int64   `json:"run_length_ms,omitempty"`
+	ReorderLatenessMs  int64   `json:"reorder_lateness_ms,omitempty"` // 乱序缓冲的最大容忍时长
+	TradeDedup         int     `json:"trade_dedup,omitempty"`         // 按 TradeID 去重记住的 ID 上限
+	CompactRecentMs    int64   `json:"compact_recent_ms,omitempty"`   // 长窗口省内存模式：逐笔保留的时长
+	CompactBucketMs    int64   `json:"compact_bucket_ms,omitempty"`
+	ContractMultiplier float64 `json:"contract_multiplier"`
+	VolumeUnit         uint8   `json:"volume_unit"`
+	Calendar           string  `json:"calendar"`
+	Tracked            string  `json:"tracked"`
+	Fingerprint        string  `json:"fingerprint"` // 以上字段的 FNV-64a 摘要
+}
+
+// Config 当前配置及其指纹（读锁）
+func (w *SlidingWindow) Config() WindowConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.configUnlocked()
+}
+
+func (w *SlidingWindow) configUnlocked() WindowConfig {
+	c := WindowConfig{
+		DurationMs:         w.duration.Milliseconds(),
+		PriceScale:         int64(w.priceScale),
+		VolumeScale:        int64(w.volumeScale),
+		EMAAlpha:           w.ema.Alpha,
+		EMADecayHalfLifeMs: w.emaDecay.HalfLife.Milliseconds(),
+		EvictionPolicy:     w.evict.String(),
+		MaxPoints:          w.maxPoints,
+		ZeroVolume:         w.zeroVol.String(),
+		NegativeVolume:     w.negative.policy.String(),
+		DuplicateTs:        w.dupTs.String(),
+		RunLengthMs:        w.runSpan.Milliseconds(),
+		ReorderLatenessMs:  w.reorder.lateness.Milliseconds(),
+		TradeDedup:         w.dedup.max,
+		ContractMultiplier: 1,
+		Calendar:           "always_open",
+		Tracked:            w.tracked.String(),
+	}
+	if vc := w.volConv.Load(); vc != nil {
+		c.ContractMultiplier = vc.mult
+		c.VolumeUnit = uint8(vc.unit)
+	}
+	// 分段存储已分配的槽位随负载变化，指纹只记录配置的段大小
+	if w.seg != nil {
+		c.SegmentSize = w.seg.segSize
+	} else {
+		c.Capacity = w.ring.capacity()
+	}
+	if w.compact != nil {
+		c.CompactRecentMs = w.compact.recent.Milliseconds()
+		c.CompactBucketMs = w.compact.bucket.Milliseconds()
+	}
+	if w.calendar != nil {
+		c.Calendar = calendarID(w.calendar)
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%d|%d|%d|%d|%g|%d|%s|%d|%s|%s|%s|%d|%d|%d|%d|%d|%g|%d|%s|%s",
+		c.DurationMs, c.Capacity, c.SegmentSize, c.PriceScale, c.VolumeScale, c.EMAAlpha, c.EMADecayHalfLifeMs,
+		c.EvictionPolicy, c.MaxPoints, c.ZeroVolume, c.NegativeVolume, c.DuplicateTs, c.RunLengthMs, c.ReorderLatenessMs, c.TradeDedup, c.CompactRecentMs, c.CompactBucketMs, c.ContractMultiplier, c.VolumeUnit, c.Calendar, c.Tracked)
+	c.Fingerprint = fmt.Sprintf("%016x", h.Sum64())
+	return c
+}
+
+// calendarID 日历的指纹标识：优先用 CalendarIdentifier，否则按类型与字段值
+func calendarID(cal SessionCalendar) string {
+	if id, ok := cal.(CalendarIdentifier); ok {
+		return id.CalendarID()
+	}
+	return fmt.Sprintf("%T%+v", cal, cal)
+}
+
+// ExtendedSnapshot 带配置指纹的快照，JSON 时 Snapshot 字段平铺
+type ExtendedSnapshot struct {
+	*Snapshot
+	Config WindowConfig `json:"config"`
+}
+
+// SnapshotExtended 快照 + 配置指纹；窗口未就绪时返回 nil
+func (w *SlidingWindow) SnapshotExtended() *ExtendedSnapshot {
+	s := w.Snapshot()
+	if s == nil {
+		return nil
+	}
+	return &ExtendedSnapshot{Snapshot: s, Config: w.Config()}
+}
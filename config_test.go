@@ -0,0 +1,88 @@
+package sliding_window
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestConfigFingerprint(t *testing.T) {
+	a := NewSlidingWindow(time.Minute, 64, 0.1)
+	b := NewSlidingWindow(time.Minute, 64, 0.1)
+	ca := a.Config()
+	if ca.Fingerprint == "" || ca.Fingerprint != b.Config().Fingerprint {
+		t.Fatalf("identical windows should share a fingerprint: %s vs %s", ca.Fingerprint, b.Config().Fingerprint)
+	}
+	if ca.DurationMs != 60000 || ca.EMAAlpha != 0.1 || ca.ContractMultiplier != 1 || ca.Calendar != "always_open" {
+		t.Fatalf("config: %+v", ca)
+	}
+
+	if err := b.SetContractMultiplier(10, VolumeInBase); err != nil {
+		t.Fatal(err)
+	}
+	cb := b.Config()
+	if cb.ContractMultiplier != 10 || cb.VolumeUnit != uint8(VolumeInBase) || cb.Fingerprint == ca.Fingerprint {
+		t.Fatalf("multiplier should change the fingerprint: %+v", cb)
+	}
+	if d := NewSlidingWindow(2*time.Minute, 64, 0.1).Config(); d.Fingerprint == ca.Fingerprint {
+		t.Fatal("duration should change the fingerprint")
+	}
+}
+
+func TestSnapshotExtended(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if w.SnapshotExtended() != nil {
+		t.Fatal("empty window should not produce a snapshot")
+	}
+	t0 := time.Unix(1_700_000_000, 0)
+	w.AddWindowPoint(SideBuy, 100, 1, t0)
+	w.AddWindowPoint(SideSell, 101, 1, t0.Add(time.Second))
+
+	ext := w.SnapshotExtended()
+	if ext == nil || ext.Config != w.Config() {
+		t.Fatalf("extended: %+v", ext)
+	}
+
+	// Snapshot 字段平铺在顶层，配置在 "config" 下
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	cfg, ok := m["config"].(map[string]any)
+	if m["latest_price"] != 101.0 || !ok || cfg["fingerprint"] != ext.Config.Fingerprint {
+		t.Fatalf("json: %s", raw)
+	}
+}
+
+func TestConfigFingerprint_SegmentedStable(t *testing.T) {
+	w := NewSlidingWindowSegmented(time.Minute, 8, 0.1)
+	before := w.Config()
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 100; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(i)*time.Millisecond))
+	}
+	after := w.Config()
+	if after.Fingerprint != before.Fingerprint || after.SegmentSize != 8 || after.Capacity != 0 {
+		t.Fatalf("fingerprint drifted with load: %+v -> %+v", before, after)
+	}
+}
+
+func TestConfigFingerprint_Calendar(t *testing.T) {
+	a := NewSlidingWindow(time.Minute, 64, 0.1)
+	b := NewSlidingWindow(time.Minute, 64, 0.1)
+	a.SetCalendar(&WeeklyCalendar{Closed: []WeeklySpan{{StartDay: time.Saturday, EndDay: time.Monday}}})
+	b.SetCalendar(&WeeklyCalendar{Closed: []WeeklySpan{{StartDay: time.Friday, Start: 16 * time.Hour, EndDay: time.Sunday, End: 17 * time.Hour}}})
+	if ca, cb := a.Config(), b.Config(); ca.Calendar == cb.Calendar || ca.Fingerprint == cb.Fingerprint {
+		t.Fatalf("different calendars of the same type should differ: %q / %q", ca.Calendar, cb.Calendar)
+	}
+
+	c := NewSlidingWindow(time.Minute, 64, 0.1)
+	c.SetCalendar(AlwaysOpen{})
+	if c.Config() != NewSlidingWindow(time.Minute, 64, 0.1).Config() {
+		t.Fatal("AlwaysOpen should fingerprint like the default calendar")
+	}
+}
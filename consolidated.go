@@ -0,0 +1,183 @@
+package sliding_window
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// flowTotals 窗口成交的 O(1) 汇总（真实值，数量按合约数）
+type flowTotals struct {
+	notional float64 // Σ price·volume
+	volume   float64 // Σ volume（负量按 0 计）
+	buy      float64
+	sell     float64
+}
+
+func (w *SlidingWindow) flowTotals() flowTotals {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	scale := float64(w.volumeScale)
+	return flowTotals{
+		notional: w.sumNotional.value(),
+		volume:   float64(w.SumV.Load()) / scale,
+		buy:      float64(w.buyVol.Load()) / scale,
+		sell:     float64(w.sellVol.Load()) / scale,
+	}
+}
+
+func (f flowTotals) vwap() (float64, bool) {
+	if f.volume <= 0 {
+		return 0, false
+	}
+	return f.notional / f.volume, true
+}
+
+type venueEntry struct {
+	name   string
+	w      *SlidingWindow
+	weight float64
+}
+
+// ConsolidatedWindow 多交易所合并视图：按权重（如交易所可信度）合成 VWAP / 不平衡，
+// 并在单个交易所 VWAP 偏离合成值时给出告警
+type ConsolidatedWindow struct {
+	mu     sync.RWMutex
+	venues []venueEntry
+}
+
+func NewConsolidatedWindow() *ConsolidatedWindow {
+	return &ConsolidatedWindow{}
+}
+
+// AddVenue 加入（或替换）一个交易所窗口，weight 必须为正
+func (c *ConsolidatedWindow) AddVenue(name string, w *SlidingWindow, weight float64) error {
+	if w == nil {
+		return fmt.Errorf("venue %q: nil window", name)
+	}
+	if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("venue %q: weight must be positive, got %v", name, weight)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.venues {
+		if c.venues[i].name == name {
+			c.venues[i] = venueEntry{name: name, w: w, weight: weight}
+			return nil
+		}
+	}
+	c.venues = append(c.venues, venueEntry{name: name, w: w, weight: weight})
+	return nil
+}
+
+func (c *ConsolidatedWindow) RemoveVenue(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.venues {
+		if c.venues[i].name == name {
+			c.venues = append(c.venues[:i], c.venues[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *ConsolidatedWindow) entries() []venueEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]venueEntry, len(c.venues))
+	copy(out, c.venues)
+	return out
+}
+
+type ConsolidatedStats struct {
+	VWAP      float64 `json:"vwap"`
+	Imbalance float64 `json:"imbalance"`
+	Volume    float64 `json:"volume"` // 加权成交量
+	Venues    int     `json:"venues"` // 有成交的交易所数
+}
+
+type VenueDivergence struct {
+	Venue     string  `json:"venue"`
+	VWAP      float64 `json:"vwap"`
+	DiffBps   float64 `json:"diff_bps"` // (venue - consolidated) / consolidated，单位 bp
+	Imbalance float64 `json:"imbalance"`
+	Alert     bool    `json:"alert"`
+}
+
+// Stats 合成 VWAP = Σ(weight·notional) / Σ(weight·volume)，不平衡同理按权重合成
+func (c *ConsolidatedWindow) Stats() (ConsolidatedStats, bool) {
+	st, _, ok := c.collect()
+	return st, ok
+}
+
+// Divergences 每个交易所 VWAP 相对合成 VWAP 的偏离，超过 thresholdBps 标记 Alert
+func (c *ConsolidatedWindow) Divergences(thresholdBps float64) ([]VenueDivergence, bool) {
+	st, per, ok := c.collect()
+	if !ok || st.VWAP <= 0 {
+		return nil, false
+	}
+
+	out := make([]VenueDivergence, 0, len(per))
+	for _, v := range per {
+		vwap, okV := v.totals.vwap()
+		if !okV {
+			continue
+		}
+		diff := (vwap - st.VWAP) / st.VWAP * 1e4
+		out = append(out, VenueDivergence{
+			Venue:     v.name,
+			VWAP:      vwap,
+			DiffBps:   diff,
+			Imbalance: imbalanceOfFloat(v.totals.buy, v.totals.sell),
+			Alert:     math.Abs(diff) > thresholdBps,
+		})
+	}
+	return out, true
+}
+
+type venueTotals struct {
+	name   string
+	totals flowTotals
+}
+
+func (c *ConsolidatedWindow) collect() (ConsolidatedStats, []venueTotals, bool) {
+	var st ConsolidatedStats
+
+	entries := c.entries()
+	per := make([]venueTotals, 0, len(entries))
+
+	var wNotional, wVolume, wBuy, wSell float64
+	for _, e := range entries {
+		f := e.w.flowTotals()
+		per = append(per, venueTotals{name: e.name, totals: f})
+		if f.volume <= 0 {
+			continue
+		}
+		st.Venues++
+		wNotional += e.weight * f.notional
+		wVolume += e.weight * f.volume
+		wBuy += e.weight * f.buy
+		wSell += e.weight * f.sell
+	}
+
+	if wVolume <= 0 {
+		return st, per, false
+	}
+	st.VWAP = wNotional / wVolume
+	st.Volume = wVolume
+	st.Imbalance = imbalanceOfFloat(wBuy, wSell)
+	return st, per, true
+}
+
+func imbalanceOfFloat(buy, sell float64) float64 {
+	den := buy + sell
+	if den <= 0 {
+		return 0
+	}
+	return (buy - sell) / den
+}
@@ -0,0 +1,66 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConsolidatedWindow(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	a := NewSlidingWindow(time.Minute, 64, 0.1)
+	b := NewSlidingWindow(time.Minute, 64, 0.1)
+	idle := NewSlidingWindow(time.Minute, 64, 0.1)
+	a.AddWindowPoint(SideBuy, 100, 1, t0)
+	b.AddWindowPoint(SideSell, 110, 1, t0)
+
+	c := NewConsolidatedWindow()
+	if _, ok := c.Stats(); ok {
+		t.Fatal("no venues should not report stats")
+	}
+	if err := c.AddVenue("x", nil, 1); err == nil {
+		t.Fatal("nil window should be rejected")
+	}
+	if err := c.AddVenue("x", a, math.NaN()); err == nil {
+		t.Fatal("NaN weight should be rejected")
+	}
+	for _, v := range []struct {
+		name   string
+		w      *SlidingWindow
+		weight float64
+	}{{"a", a, 1}, {"b", b, 3}, {"idle", idle, 1}} {
+		if err := c.AddVenue(v.name, v.w, v.weight); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// VWAP = (1·100 + 3·110) / (1 + 3)，不平衡 = (1 - 3) / 4；无成交的交易所不参与
+	near := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	st, ok := c.Stats()
+	if !ok || st.Venues != 2 || !near(st.VWAP, 107.5) || !near(st.Imbalance, -0.5) || !near(st.Volume, 4) {
+		t.Fatalf("stats: %+v", st)
+	}
+
+	divs, ok := c.Divergences(500)
+	if !ok || len(divs) != 2 {
+		t.Fatalf("divergences: %+v", divs)
+	}
+	if d := divs[0]; d.Venue != "a" || !d.Alert || !near(d.DiffBps, -7.5/107.5*1e4) || d.Imbalance != 1 {
+		t.Fatalf("venue a: %+v", d)
+	}
+	if d := divs[1]; d.Venue != "b" || d.Alert || !near(d.DiffBps, 2.5/107.5*1e4) {
+		t.Fatalf("venue b: %+v", d)
+	}
+
+	// 同名替换权重，移除后只剩一个有成交的交易所
+	if err := c.AddVenue("b", b, 1); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := c.Stats(); !near(st.VWAP, 105) {
+		t.Fatalf("replaced weight: %+v", st)
+	}
+	c.RemoveVenue("a")
+	if st, _ := c.Stats(); st.Venues != 1 || !near(st.VWAP, 110) {
+		t.Fatalf("after remove: %+v", st)
+	}
+}
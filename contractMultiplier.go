@@ -0,0 +1,99 @@
+package sliding_window
+
+import "fmt"
+
+type VolumeUnit uint8
+
+const (
+	VolumeInContracts VolumeUnit = iota // 原始数量（张 / 合约数），默认
+	VolumeInBase                        // 标的数量 = 合约数 × 乘数
+	VolumeInQuote                       // 计价货币名义金额 = 合约数 × 乘数 × 价格
+)
+
+// volumeConversion 成交量对外展示单位；nil 表示原始合约数
+type volumeConversion struct {
+	unit VolumeUnit
+	mult float64
+}
+
+// SetContractMultiplier 设置合约乘数和对外展示的成交量单位（写锁）。
+// 设置后 SumVolume / DeltaVolume / AvgVolumePerPoint / VolumePerSecond 以及 Snapshot 中的成交量
+// 统一按该单位输出，便于 manager 跨品种比较；Imbalance、VolumeFactor 等比值不受影响。
+func (w *SlidingWindow) SetContractMultiplier(mult float64, unit VolumeUnit) error {
+	if mult <= 0 {
+		return fmt.Errorf("contract multiplier must be positive, got %v", mult)
+	}
+	if unit > VolumeInQuote {
+		return fmt.Errorf("unknown volume unit %d", unit)
+	}
+	if unit == VolumeInQuote && !w.tracked.Has(TrackNotional) {
+		return fmt.Errorf("VolumeInQuote requires TrackNotional")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if unit == VolumeInContracts && mult == 1 {
+		w.volConv.Store(nil)
+		return nil
+	}
+	w.volConv.Store(&volumeConversion{unit: unit, mult: mult})
+	return nil
+}
+
+// VolumeUnit 当前对外展示的成交量单位
+func (w *SlidingWindow) VolumeUnit() VolumeUnit {
+	if c := w.volConv.Load(); c != nil {
+		return c.unit
+	}
+	return VolumeInContracts
+}
+
+// volumesUnlocked 按展示单位返回 total / buy / sell（要求持有读锁）
+func (w *SlidingWindow) volumesUnlocked() (total, buy, sell float64) {
+	c := w.volConv.Load()
+	if c != nil && c.unit == VolumeInQuote {
+		return w.sumNotional.value() * c.mult, w.buyNotional.value() * c.mult, w.sellNotional.value() * c.mult
+	}
+
+	m := 1.0
+	if c != nil {
+		m = c.mult
+	}
+	total = w.sumVolume.Float(w.volumeScale) * m
+	buy = float64(w.buyVol.Load()) / float64(w.volumeScale) * m
+	sell = float64(w.sellVol.Load()) / float64(w.volumeScale) * m
+	return
+}
+
+// displayVolume 把一段成交的合约数 v（真实值）与成交额 notional 换算到展示单位
+func (w *SlidingWindow) displayVolume(v, notional float64) float64 {
+	c := w.volConv.Load()
+	switch {
+	case c == nil:
+		return v
+	case c.unit == VolumeInQuote:
+		return notional * c.mult
+	default:
+		return v * c.mult
+	}
+}
+
+// volumeRateConvUnlocked 把以合约数计的速率（每点 / 每秒）换算到展示单位的系数（要求持有读锁）
+func (w *SlidingWindow) volumeRateConvUnlocked() float64 {
+	c := w.volConv.Load()
+	if c == nil {
+		return 1
+	}
+	switch c.unit {
+	case VolumeInQuote:
+		// Σ(p·v)/Σv 即成交额均价，速率 × 均价 = 名义金额速率
+		sv := float64(w.SumV.Load()) / float64(w.volumeScale)
+		if sv <= 0 {
+			return 0
+		}
+		return c.mult * w.sumNotional.value() / sv
+	default:
+		return c.mult
+	}
+}
@@ -0,0 +1,54 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestContractMultiplier(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	w.AddWindowPoint(SideBuy, 100, 2, t0)
+	w.AddWindowPoint(SideSell, 200, 1, t0.Add(time.Second))
+
+	if err := w.SetContractMultiplier(0, VolumeInBase); err == nil {
+		t.Fatal("non-positive multiplier should be rejected")
+	}
+	if err := w.SetContractMultiplier(10, VolumeInQuote+1); err == nil {
+		t.Fatal("unknown unit should be rejected")
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	imb := w.Imbalance()
+
+	// 标的数量：合约数 × 乘数，比值类指标不变
+	if err := w.SetContractMultiplier(10, VolumeInBase); err != nil {
+		t.Fatal(err)
+	}
+	if w.VolumeUnit() != VolumeInBase || !near(w.SumVolume(), 30) || !near(w.DeltaVolume(), 10) {
+		t.Fatalf("base: unit=%v sum=%v delta=%v", w.VolumeUnit(), w.SumVolume(), w.DeltaVolume())
+	}
+	if !near(w.AvgVolumePerPoint(), 15) || w.Imbalance() != imb {
+		t.Fatalf("base: avg=%v imbalance=%v", w.AvgVolumePerPoint(), w.Imbalance())
+	}
+	if s := w.Snapshot(); !near(s.TotalVolume, 30) || !near(s.BuyVolume, 20) || !near(s.SellVolume, 10) {
+		t.Fatalf("snapshot: total=%v buy=%v sell=%v", s.TotalVolume, s.BuyVolume, s.SellVolume)
+	}
+
+	// 计价货币名义金额：买 100×2、卖 200×1，再乘以乘数
+	if err := w.SetContractMultiplier(10, VolumeInQuote); err != nil {
+		t.Fatal(err)
+	}
+	if !near(w.SumVolume(), 4000) || !near(w.DeltaVolume(), 0) {
+		t.Fatalf("quote: sum=%v delta=%v", w.SumVolume(), w.DeltaVolume())
+	}
+
+	// 乘数 1 + 合约数：恢复原始成交量
+	if err := w.SetContractMultiplier(1, VolumeInContracts); err != nil {
+		t.Fatal(err)
+	}
+	if w.VolumeUnit() != VolumeInContracts || !near(w.SumVolume(), 3) || !near(w.DeltaVolume(), 1) {
+		t.Fatalf("contracts: unit=%v sum=%v delta=%v", w.VolumeUnit(), w.SumVolume(), w.DeltaVolume())
+	}
+}
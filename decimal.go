@@ -0,0 +1,129 @@
+package sliding_window
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scaleDecimals QtyScale 对应的小数位数；scale 必须是 10 的幂
+func scaleDecimals(scale QtyScale) (int, bool) {
+	if scale <= 0 {
+		return 0, false
+	}
+	n := 0
+	for s := int64(scale); s > 1; s /= 10 {
+		if s%10 != 0 {
+			return 0, false
+		}
+		n++
+	}
+	return n, true
+}
+
+// ParseQtyLoz 把十进制字符串（如交易所下发的 "0.06743"、"-12.5"、"1e-5"）精确解析为 ticks，
+// 全程不经过 float64。超出精度的位数按四舍五入（远离零）处理；
+// 指数绝对值超过尾数位数 + 19 时无论正负都超出 int64 ticks 的表示范围，返回错误。
+func ParseQtyLoz(s string, scale QtyScale) (QtyLoz, error) {
+	dec, ok := scaleDecimals(scale)
+	if !ok {
+		return 0, fmt.Errorf("scale %d is not a power of 10", scale)
+	}
+
+	str := strings.TrimSpace(s)
+	neg := false
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	exp := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid decimal %q", s)
+		}
+		exp = e
+		str = str[:i]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid decimal %q", s)
+	}
+	digits := intPart + fracPart
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+
+	// 先界定指数再做下标运算，避免极端指数在 int 运算中回绕
+	if limit := len(digits) + 19; exp > limit || exp < -limit {
+		return 0, fmt.Errorf("decimal %q exponent out of range", s)
+	}
+
+	// ticks = digits × 10^shift
+	shift := exp - len(fracPart) + dec
+	roundUp := false
+	if shift >= 0 {
+		if shift > 19 {
+			return 0, fmt.Errorf("decimal %q overflows int64 ticks", s)
+		}
+		digits += strings.Repeat("0", shift)
+	} else {
+		drop := -shift
+		if drop > len(digits) {
+			digits = ""
+		} else {
+			roundUp = digits[len(digits)-drop] >= '5'
+			digits = digits[:len(digits)-drop]
+		}
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	var n int64
+	if digits != "" {
+		v, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("decimal %q overflows int64 ticks", s)
+		}
+		n = v
+	}
+	if roundUp {
+		if n == 1<<63-1 {
+			return 0, fmt.Errorf("decimal %q overflows int64 ticks", s)
+		}
+		n++
+	}
+	if neg {
+		n = -n
+	}
+	return QtyLoz(n), nil
+}
+
+// errEmptyDecimal 价格或数量为空字符串
+var errEmptyDecimal = errors.New("empty decimal string")
+
+// AddDecimalString 直接用交易所下发的十进制字符串添加一个点（写锁），
+// 价格与数量精确解析为 ticks，避免 NewQtyLoz 的浮点舍入误差
+func (w *SlidingWindow) AddDecimalString(price, size string, side Side, ts time.Time) error {
+	if price == "" || size == "" {
+		return errEmptyDecimal
+	}
+	px, err := ParseQtyLoz(price, w.priceScale)
+	if err != nil {
+		return fmt.Errorf("price: %w", err)
+	}
+	vol, err := ParseQtyLoz(size, w.volumeScale)
+	if err != nil {
+		return fmt.Errorf("size: %w", err)
+	}
+
+	w.lockWrite()
+	defer w.unlockWrite()
+	w.add(WindowPoint{Ts: ts, Price: px, Volume: vol, Side: side})
+	return nil
+}
@@ -0,0 +1,48 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQtyLoz(t *testing.T) {
+	p4, p8 := NewQtyScaleFromDecimals(4), NewQtyScaleFromDecimals(8)
+	cases := []struct {
+		in    string
+		scale QtyScale
+		want  QtyLoz
+	}{
+		{"0.06743", p8, 6743000},
+		{"0.06743", NewQtyScaleFromDecimals(5), 6743},
+		{"123.4567", p4, 1234567},
+		{"-12.5", p4, -125000},
+		{"1e-5", p8, 1000},
+		{"0.00005", p4, 1}, // 四舍五入
+		{"0.000049", p4, 0},
+		{".5", p4, 5000},
+		{"7", p4, 70000},
+		{"1e-20", p4, 0}, // 仍在指数范围内，舍入为 0
+	}
+	for _, c := range cases {
+		got, err := ParseQtyLoz(c.in, c.scale)
+		if err != nil || got != c.want {
+			t.Errorf("ParseQtyLoz(%q) = %d, %v; want %d", c.in, got, err, c.want)
+		}
+	}
+	for _, bad := range []string{"", "abc", "1.2.3", "1e", "99999999999999999999",
+		"1e-9223372036854775807", "1.25e-9223372036854775807", "1e9223372036854775807", "1e-21", "1e99999999999999999999"} {
+		if _, err := ParseQtyLoz(bad, p8); err == nil {
+			t.Errorf("ParseQtyLoz(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAddDecimalString(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 16, 0.1)
+	if err := w.AddDecimalString("0.06743", "1.5", SideBuy, time.Unix(1_700_000_000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.LatestPrice.Load(); got != 674 {
+		t.Fatalf("latest ticks = %d, want 674", got)
+	}
+}
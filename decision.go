@@ -0,0 +1,53 @@
+package sliding_window
+
+import "time"
+
+// DecisionBundle 策略决策点常用的一组指标，全部取自同一个读锁视图（同一版本），
+// 避免分别调用各个 getter 时中间插入 Add 导致字段彼此不一致
+type DecisionBundle struct {
+	Version      uint64          `json:"version"`
+	Ready        bool            `json:"ready"` // 满足窗口级就绪条件（见 SetReadyPolicy）且至少 2 个点
+	Ts           time.Time       `json:"ts"`    // 最新一个点的时间
+	LatestPrice  float64         `json:"latest_price"`
+	VWAP         float64         `json:"vwap"`
+	Imbalance    float64         `json:"imbalance"`
+	Momentum     float64         `json:"momentum"`
+	MomentumOK   bool            `json:"momentum_ok"`
+	BandPosition float64         `json:"band_position"` // 相对均衡区的位置，即 EquilibriumZone.NormDist
+	Zone         EquilibriumZone `json:"zone"`
+	ZoneOK       bool            `json:"zone_ok"`
+	RealizedVol  float64         `json:"realized_vol"`
+	RealizedOK   bool            `json:"realized_ok"`
+}
+
+// Decision 一次读锁内取出决策常用指标（读锁），均衡区参数与 Snapshot 一致。
+// 窗口未就绪时仍填充已有字段，由调用方按 Ready 决定是否使用
+func (w *SlidingWindow) Decision() DecisionBundle {
+	var d DecisionBundle
+
+	w.mu.RLock()
+	d.Version = w.version.Load()
+	if w.size < 2 {
+		w.mu.RUnlock()
+		return d
+	}
+	d.Ready = !w.unready.Load()
+	d.Ts = w.lastUnlocked().Ts
+	d.LatestPrice = w.lastUnlocked().Price.Float(w.priceScale)
+	d.Imbalance = imbalanceOf(w.buyVol.Load(), w.sellVol.Load())
+	d.Momentum, d.MomentumOK = w.momentumUnlocked()
+	d.RealizedVol, d.RealizedOK = w.realizedVolUnlocked()
+	in, ok := w.gatherZoneUnlocked()
+	w.mu.RUnlock()
+
+	if !ok {
+		return d
+	}
+	if in.sumV > 0 {
+		d.VWAP = in.sumPV / in.sumV
+	}
+	// 排序放在锁外，价格缓冲是锁内拷贝出来的
+	d.Zone, d.ZoneOK = w.computeZone(in, snapshotZoneAlpha, snapshotZoneBeta)
+	d.BandPosition = d.Zone.NormDist
+	return d
+}
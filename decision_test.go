@@ -0,0 +1,36 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecision(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	if d := w.Decision(); d.Ready {
+		t.Fatalf("empty window ready: %+v", d)
+	}
+
+	for i := 0; i < 30; i++ {
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i%7), 1+float64(i%4), t0.Add(time.Duration(i)*time.Second))
+	}
+
+	d := w.Decision()
+	if !d.Ready || d.Version != w.Version() {
+		t.Fatalf("bundle: %+v", d)
+	}
+	vwap, _ := w.VolumeWeightedAveragePrice()
+	vol, _ := w.RealizedVol()
+	zone, _ := w.EquilibriumZone(snapshotZoneAlpha, snapshotZoneBeta)
+	if math.Abs(d.VWAP-vwap) > 1e-9 || d.RealizedVol != vol || d.Imbalance != w.Imbalance() ||
+		d.Zone != zone || d.BandPosition != zone.NormDist || d.LatestPrice != 101 {
+		t.Fatalf("bundle mismatch: %+v", d)
+	}
+}
@@ -0,0 +1,96 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// 窗口按时长均分为 deltaAccelBuckets 个桶，加速度取最近 deltaAccelRecent 个桶的斜率
+const (
+	deltaAccelBuckets = 12
+	deltaAccelRecent  = 4
+)
+
+// DeltaAccel 主动买卖差（CVD 的一阶导数）及其变化率
+type DeltaAccel struct {
+	Bucket    time.Duration `json:"bucket"`     // 桶长 = 窗口时长 / 12（计数窗口为数据跨度 / 12）
+	Rates     []float64     `json:"rates"`      // 各桶每秒主动买卖差，旧 → 新，最后一个桶以最新一笔结尾
+	Rate      float64       `json:"rate"`       // 最近一个桶的每秒主动买卖差
+	Accel     float64       `json:"accel"`      // 最近 4 个桶 Rate 的最小二乘斜率（每秒²）
+	AccelNorm float64       `json:"accel_norm"` // Accel × 桶长 / 各桶 |Rate| 均值，跨品种可比
+}
+
+// DeltaAcceleration 主动买卖差的加速度（读锁）：把 CVD 按桶求导得到每秒买卖差，再对最近几个桶求斜率。
+// 买方在加速进场时为正，往往领先于失衡本身的变化
+//
+//metric:name=delta_acceleration unit=volume/s2 cost=O(n) min_points=2
+func (w *SlidingWindow) DeltaAcceleration() (DeltaAccel, bool) {
+	if !w.IsReady() {
+		return DeltaAccel{}, false
+	}
+
+	var out DeltaAccel
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	bucket := w.windowDurationUnlocked() / deltaAccelBuckets
+	if w.size < 2 || bucket <= 0 {
+		return out, false
+	}
+	out.Bucket = bucket
+
+	var delta, notional [deltaAccelBuckets]float64
+	end := w.lastUnlocked().Ts
+	start := end.Add(-bucket * deltaAccelBuckets)
+	for i := w.searchTsUnlocked(start.Add(1)); i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		if pt.Volume <= 0 {
+			continue
+		}
+		// (start, end] 均分，右闭
+		k := int((pt.Ts.Sub(start) - 1) / bucket)
+		k = min(max(k, 0), deltaAccelBuckets-1)
+		v := pt.Volume.Float(w.volumeScale)
+		n := v * pt.Price.Float(w.priceScale)
+		switch pt.Side {
+		case SideBuy:
+			delta[k] += v
+			notional[k] += n
+		case SideSell:
+			delta[k] -= v
+			notional[k] -= n
+		}
+	}
+
+	sec := bucket.Seconds()
+	out.Rates = make([]float64, deltaAccelBuckets)
+	var sumAbs float64
+	for k := range out.Rates {
+		out.Rates[k] = w.displayVolume(delta[k], notional[k]) / sec
+		sumAbs += math.Abs(out.Rates[k])
+	}
+	out.Rate = out.Rates[deltaAccelBuckets-1]
+
+	// 最近几个桶对桶中心时间做最小二乘
+	recent := out.Rates[deltaAccelBuckets-deltaAccelRecent:]
+	var mx, my float64
+	for j, r := range recent {
+		mx += float64(j)
+		my += r
+	}
+	mx /= deltaAccelRecent
+	my /= deltaAccelRecent
+	var sxy, sxx float64
+	for j, r := range recent {
+		dx := float64(j) - mx
+		sxy += dx * (r - my)
+		sxx += dx * dx
+	}
+	out.Accel = sxy / sxx / sec
+
+	if mean := sumAbs / deltaAccelBuckets; mean > 0 {
+		out.AccelNorm = out.Accel * sec / mean
+	}
+	return out, true
+}
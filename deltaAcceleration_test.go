@@ -0,0 +1,37 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDeltaAcceleration(t *testing.T) {
+	w := NewSlidingWindow(12*time.Second, 1024, 0.1)
+	if _, ok := w.DeltaAcceleration(); ok {
+		t.Fatal("empty window should not be ok")
+	}
+
+	// 每秒一个桶：前 8 秒买卖平衡，之后每秒的净买量依次为 1、2、3、4
+	t0 := time.Unix(1_700_000_000, 0)
+	for s := 1; s <= 12; s++ {
+		ts := t0.Add(time.Duration(s) * time.Second)
+		net := 0.0
+		if s > 8 {
+			net = float64(s - 8)
+		}
+		w.AddWindowPoint(SideSell, 100, 1, ts.Add(-500*time.Millisecond))
+		w.AddWindowPoint(SideBuy, 100, 1+net, ts)
+	}
+
+	d, ok := w.DeltaAcceleration()
+	if !ok || d.Bucket != time.Second || len(d.Rates) != 12 {
+		t.Fatalf("got %+v", d)
+	}
+	if d.Rate != 4 || d.Rates[7] != 0 || d.Rates[8] != 1 {
+		t.Fatalf("rates: %v", d.Rates)
+	}
+	if math.Abs(d.Accel-1) > 1e-12 || math.Abs(d.AccelNorm-1/(10.0/12)) > 1e-12 {
+		t.Fatalf("accel %v norm %v", d.Accel, d.AccelNorm)
+	}
+}
@@ -0,0 +1,235 @@
+package sliding_window
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Excursion 价格路径上的一次最大回撤 / 最大上冲
+type Excursion struct {
+	Value     float64   `json:"value"` // 相对幅度，始终 >= 0
+	FromPrice float64   `json:"from_price"`
+	ToPrice   float64   `json:"to_price"`
+	FromTs    time.Time `json:"from_ts"`
+	ToTs      time.Time `json:"to_ts"`
+}
+
+// excPoint 参与回撤计算的价格点
+type excPoint struct {
+	px float64
+	ts int64 // UnixNano
+}
+
+// excLeg 一段回撤 / 上冲：from 到 to 的相对幅度
+type excLeg struct {
+	v        float64
+	from, to excPoint
+}
+
+func (l excLeg) excursion() Excursion {
+	return Excursion{
+		Value:     l.v,
+		FromPrice: l.from.px,
+		ToPrice:   l.to.px,
+		FromTs:    time.Unix(0, l.from.ts),
+		ToTs:      time.Unix(0, l.to.ts),
+	}
+}
+
+// excAgg 一段连续价格路径的摘要：最高点、最低点（同价取最早）及段内的最大回撤 / 上冲。
+// 前后相邻两段的摘要可以合并，且合并满足结合律
+type excAgg struct {
+	peak, trough excPoint
+	dd, ru       excLeg
+}
+
+// combineExc 合并前段 a 与后段 b：跨段的回撤为 a 的峰值到 b 的谷值，上冲为 a 的谷值到 b 的峰值
+func combineExc(a, b excAgg) excAgg {
+	out := a
+	if b.peak.px > out.peak.px {
+		out.peak = b.peak
+	}
+	if b.trough.px < out.trough.px {
+		out.trough = b.trough
+	}
+	if p := a.peak.px; p > 0 {
+		if d := (p - b.trough.px) / p; d > out.dd.v {
+			out.dd = excLeg{v: d, from: a.peak, to: b.trough}
+		}
+	}
+	if b.dd.v > out.dd.v {
+		out.dd = b.dd
+	}
+	if t := a.trough.px; t > 0 {
+		if u := (b.peak.px - t) / t; u > out.ru.v {
+			out.ru = excLeg{v: u, from: a.trough, to: b.peak}
+		}
+	}
+	if b.ru.v > out.ru.v {
+		out.ru = b.ru
+	}
+	return out
+}
+
+type excEntry struct {
+	pt  excPoint
+	agg excAgg // 该元素到所在栈底一段的合并摘要
+}
+
+// excursionQueue 双栈滑动窗口聚合：front 栈顶为最旧点，back 栈顶为最新点，
+// 进窗压入 back，出窗从 front 弹出，front 为空时把 back 整体倒入，均摊 O(1)。
+// 首次查询前不维护（零值 built 为 false），由读者在 mu 下按窗口全量建好后写者才开始增量维护，
+// 没人读回撤的窗口不承担 Add 开销；中间点被改写后同样退回未建状态，下次查询时重建
+type excursionQueue struct {
+	mu          sync.Mutex
+	front, back []excEntry
+	built       bool
+}
+
+func (q *excursionQueue) push(p excPoint) {
+	if !q.built {
+		return
+	}
+	agg := excAgg{peak: p, trough: p}
+	if n := len(q.back); n > 0 {
+		agg = combineExc(q.back[n-1].agg, agg)
+	}
+	q.back = append(q.back, excEntry{pt: p, agg: agg})
+}
+
+func (q *excursionQueue) popHead() {
+	if !q.built {
+		return
+	}
+	if len(q.front) == 0 {
+		for i := len(q.back) - 1; i >= 0; i-- {
+			p := q.back[i].pt
+			agg := excAgg{peak: p, trough: p}
+			if n := len(q.front); n > 0 {
+				agg = combineExc(agg, q.front[n-1].agg)
+			}
+			q.front = append(q.front, excEntry{pt: p, agg: agg})
+		}
+		q.back = q.back[:0]
+	}
+	if n := len(q.front); n > 0 {
+		q.front = q.front[:n-1]
+	}
+}
+
+// popTail 移除最新点；最新点已倒入 front 栈底时无法 O(1) 移除，改为退回未建状态
+func (q *excursionQueue) popTail() {
+	if !q.built {
+		return
+	}
+	if n := len(q.back); n > 0 {
+		q.back = q.back[:n-1]
+		return
+	}
+	if len(q.front) > 0 {
+		q.built = false
+	}
+}
+
+func (q *excursionQueue) invalidate() {
+	q.front, q.back, q.built = q.front[:0], q.back[:0], false
+}
+
+// result 当前窗口的整体摘要，窗口为空时返回 false
+func (q *excursionQueue) result() (excAgg, bool) {
+	nf, nb := len(q.front), len(q.back)
+	switch {
+	case nf > 0 && nb > 0:
+		return combineExc(q.front[nf-1].agg, q.back[nb-1].agg), true
+	case nf > 0:
+		return q.front[nf-1].agg, true
+	case nb > 0:
+		return q.back[nb-1].agg, true
+	}
+	return excAgg{}, false
+}
+
+func (q *excursionQueue) clone() excursionQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return excursionQueue{front: slices.Clone(q.front), back: slices.Clone(q.back), built: q.built}
+}
+
+func (w *SlidingWindow) excPointUnlocked(pt WindowPoint) excPoint {
+	return excPoint{px: pt.Price.Float(w.priceScale), ts: pt.Ts.UnixNano()}
+}
+
+// excursionPushUnlocked 尾部追加点后调用（要求持有写锁）
+func (w *SlidingWindow) excursionPushUnlocked(pt WindowPoint) {
+	if w.tracked.Has(TrackExcursion) {
+		w.excursions.push(w.excPointUnlocked(pt))
+	}
+}
+
+// excursionSetUnlocked 第 i 个点被改写前调用（要求持有写锁）：只改成交量时不受影响，
+// 改写最新点时在 back 栈上替换，其余情况退回未建状态
+func (w *SlidingWindow) excursionSetUnlocked(i int, pt WindowPoint) {
+	q := &w.excursions
+	if !w.tracked.Has(TrackExcursion) || !q.built {
+		return
+	}
+	old := w.atUnlocked(i)
+	if old.Price == pt.Price && old.Ts.Equal(pt.Ts) {
+		return
+	}
+	if n := len(q.back); i == w.size-1 && n > 0 {
+		q.back = q.back[:n-1]
+		q.push(w.excPointUnlocked(pt))
+		return
+	}
+	q.built = false
+}
+
+// MaxDrawdown 窗口价格路径上的最大回撤（峰值到其后谷值的最大跌幅）
+//
+//metric:name=max_drawdown unit=return cost=O(1) min_points=2
+func (w *SlidingWindow) MaxDrawdown() (Excursion, bool) {
+	if !w.IsReady() {
+		return Excursion{}, false
+	}
+
+	agg, ok := w.excursionAgg()
+	return agg.dd.excursion(), ok
+}
+
+// MaxRunUp 窗口价格路径上的最大上冲（谷值到其后峰值的最大涨幅）
+//
+//metric:name=max_run_up unit=return cost=O(1) min_points=2
+func (w *SlidingWindow) MaxRunUp() (Excursion, bool) {
+	if !w.IsReady() {
+		return Excursion{}, false
+	}
+
+	agg, ok := w.excursionAgg()
+	return agg.ru.excursion(), ok
+}
+
+// excursionAgg 读锁下取增量维护的摘要，未建时先按窗口全量建好；未维护 TrackExcursion 时返回 false
+func (w *SlidingWindow) excursionAgg() (excAgg, bool) {
+	if !w.tracked.Has(TrackExcursion) {
+		return excAgg{}, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.size < 2 {
+		return excAgg{}, false
+	}
+
+	q := &w.excursions
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.built {
+		q.front, q.back, q.built = q.front[:0], q.back[:0], true
+		for i := 0; i < w.size; i++ {
+			q.push(w.excPointUnlocked(w.atUnlocked(i)))
+		}
+	}
+	return q.result()
+}
@@ -0,0 +1,118 @@
+package sliding_window
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// bruteExcursions 逐点重扫求最大回撤 / 上冲，作为增量结果的对照
+func bruteExcursions(w *SlidingWindow) (dd, ru float64, n int) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	peak, trough := math.Inf(-1), math.Inf(1)
+	for i := 0; i < w.size; i++ {
+		px := w.atUnlocked(i).Price.Float(w.priceScale)
+		peak, trough = max(peak, px), min(trough, px)
+		dd = max(dd, (peak-px)/peak)
+		ru = max(ru, (px-trough)/trough)
+	}
+	return dd, ru, w.size
+}
+
+func TestMaxDrawdown_PeakEvicted(t *testing.T) {
+	w := NewSlidingWindowCount(4, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i, px := range []float64{120, 100, 110, 105} {
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*time.Second))
+	}
+
+	dd, ok := w.MaxDrawdown()
+	if !ok || dd.FromPrice != 120 || dd.ToPrice != 100 || math.Abs(dd.Value-20.0/120) > 1e-12 {
+		t.Fatalf("drawdown = %+v", dd)
+	}
+
+	// 峰值 120 出窗后，回撤变为 110 -> 105
+	w.AddWindowPoint(SideBuy, 108, 1, base.Add(4*time.Second))
+	dd, ok = w.MaxDrawdown()
+	if !ok || dd.FromPrice != 110 || dd.ToPrice != 105 || !dd.FromTs.Equal(base.Add(2*time.Second)) {
+		t.Fatalf("drawdown after peak eviction = %+v", dd)
+	}
+	ru, ok := w.MaxRunUp()
+	if !ok || ru.FromPrice != 100 || ru.ToPrice != 110 {
+		t.Fatalf("run-up = %+v", ru)
+	}
+}
+
+func TestMaxDrawdown_MatchesRescan(t *testing.T) {
+	w := NewSlidingWindowCount(50, 0.1)
+	w.SetDuplicateTsPolicy(DuplicateMerge)
+	r := rand.New(rand.NewPCG(1, 2))
+	base := time.Unix(1_700_000_000, 0)
+	px := 100.0
+	ts := base
+	for i := 0; i < 2000; i++ {
+		px = max(px+r.NormFloat64(), 1)
+		if r.IntN(4) > 0 { // 约 1/4 的点与上一笔同时间戳，触发尾部合并
+			ts = ts.Add(time.Second)
+		}
+		w.AddWindowPoint(SideBuy, px, 1+r.Float64(), ts)
+
+		dd, ok1 := w.MaxDrawdown()
+		ru, ok2 := w.MaxRunUp()
+		wantDD, wantRU, n := bruteExcursions(w)
+		if n < 2 {
+			continue
+		}
+		if !ok1 || !ok2 || math.Abs(dd.Value-wantDD) > 1e-9 || math.Abs(ru.Value-wantRU) > 1e-9 {
+			t.Fatalf("step %d: drawdown %v / run-up %v, want %v / %v", i, dd.Value, ru.Value, wantDD, wantRU)
+		}
+	}
+}
+
+func TestMaxDrawdown_Untracked(t *testing.T) {
+	w := NewSlidingWindowTracked(time.Minute, 64, 0.1, TrackAll&^TrackExcursion)
+	base := time.Unix(1_700_000_000, 0)
+	for i, px := range []float64{120, 100, 110} {
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*time.Second))
+	}
+	if _, ok := w.MaxDrawdown(); ok {
+		t.Fatal("drawdown should be unavailable without TrackExcursion")
+	}
+}
+
+func TestMaxDrawdown_BuiltOnFirstQuery(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	add := func(i int, px float64) {
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*time.Second))
+	}
+	for i, px := range []float64{120, 100, 110} {
+		add(i, px)
+	}
+
+	// 没人查询时写入不维护双栈
+	w.mu.RLock()
+	idle := w.excursions.built || len(w.excursions.back)+len(w.excursions.front) > 0
+	w.mu.RUnlock()
+	if idle {
+		t.Fatal("excursion stacks should not be maintained before the first query")
+	}
+
+	if dd, ok := w.MaxDrawdown(); !ok || math.Abs(dd.Value-20.0/120) > 1e-12 {
+		t.Fatalf("drawdown = %+v, %v", dd, ok)
+	}
+	// 查询之后转为增量维护
+	add(3, 90)
+	w.mu.RLock()
+	n := len(w.excursions.back) + len(w.excursions.front)
+	w.mu.RUnlock()
+	if n != 4 {
+		t.Fatalf("stack entries = %d", n)
+	}
+	if dd, _ := w.MaxDrawdown(); math.Abs(dd.Value-30.0/120) > 1e-12 {
+		t.Fatalf("drawdown after add = %+v", dd)
+	}
+}
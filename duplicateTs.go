@@ -0,0 +1,107 @@
+package sliding_window
+
+import "time"
+
+// DuplicateTsPolicy 同一时间戳多笔成交的处理方式
+type DuplicateTsPolicy uint8
+
+const (
+	// DuplicateKeepOrder 按到达顺序保留（默认，兼容旧行为）
+	DuplicateKeepOrder DuplicateTsPolicy = iota
+	// DuplicateMerge 与尾部同时间戳、同方向的成交合并为一个点：成交量累加，价格取 VWAP，Count 记笔数
+	DuplicateMerge
+	// DuplicateSeq 同时间戳的点按 TradeMeta.Seq 升序排列（Seq 为 0 的点保持到达顺序）
+	DuplicateSeq
+)
+
+func (p DuplicateTsPolicy) String() string {
+	switch p {
+	case DuplicateMerge:
+		return "merge"
+	case DuplicateSeq:
+		return "seq"
+	default:
+		return "keep_order"
+	}
+}
+
+// SetDuplicateTsPolicy 设置同一时间戳多笔成交的处理方式（写锁）。
+// 毫秒级行情里同一毫秒常有多笔成交，按到达顺序保留时最新价、structuralReturn 等
+// 依赖首尾顺序的指标带有随意性；只影响之后写入的点
+func (w *SlidingWindow) SetDuplicateTsPolicy(p DuplicateTsPolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.dupTs = p
+	w.seqTail = seqGroup{}
+}
+
+// mergeDuplicateUnlocked DuplicateMerge 策略下尝试把 pt 并入尾部同时间戳的点，成功返回 true（要求持有写锁）
+func (w *SlidingWindow) mergeDuplicateUnlocked(pt WindowPoint) bool {
+	if w.dupTs != DuplicateMerge || w.size == 0 {
+		return false
+	}
+	tail := w.lastUnlocked()
+	if !tail.Ts.Equal(pt.Ts) || tail.Side != pt.Side || pt.Volume < 0 ||
+		w.runTailTs.After(tail.Ts) || w.isQuoteUnlocked(pt) || w.isQuoteUnlocked(tail) {
+		return false
+	}
+
+	// 尾部点没有出向的 TWAP 点对，改价只需替换可加减统计
+	w.applyRemovePointUnlocked(tail)
+	merged := tail
+	merged.Volume = tail.Volume + pt.Volume
+	merged.Count = uint32(tail.Trades() + pt.Trades())
+	if v := merged.Volume.Int64(); v > 0 {
+		pv := float64(tail.Price.Int64())*float64(tail.Volume.Int64()) + float64(pt.Price.Int64())*float64(pt.Volume.Int64())
+		merged.Price = QtyLoz(int64(pv/float64(v) + 0.5))
+	} else {
+		merged.Price = pt.Price
+	}
+	w.setUnlocked(w.size-1, merged)
+	w.applyAddPointUnlocked(merged)
+	w.hiLoDirty = true
+	return true
+}
+
+// seqGroup DuplicateSeq 的旁路结构：环尾部时间戳为 ts 的连续各点的成交序号，与这些点一一对应。
+// 序号不存进环形数组，排序只需要尾部同时间戳的这一组
+type seqGroup struct {
+	ts   time.Time
+	seqs []uint64
+}
+
+// orderBySeqUnlocked DuplicateSeq 策略下把刚追加的尾部点（序号 seq）移到同时间戳点中按序号的位置（要求持有写锁）
+func (w *SlidingWindow) orderBySeqUnlocked(seq uint64) {
+	if w.dupTs != DuplicateSeq {
+		return
+	}
+	g := &w.seqTail
+	pt := w.lastUnlocked()
+	if !pt.Ts.Equal(g.ts) {
+		g.ts = pt.Ts
+		g.seqs = append(g.seqs[:0], seq)
+		return
+	}
+	// 头部出窗可能已移走组内最早的点
+	if n := w.size - 1; len(g.seqs) > n {
+		g.seqs = append(g.seqs[:0], g.seqs[len(g.seqs)-n:]...)
+	}
+	g.seqs = append(g.seqs, seq)
+	if seq == 0 {
+		return
+	}
+	i, k := w.size-1, len(g.seqs)-1
+	for k > 0 && g.seqs[k-1] != 0 && g.seqs[k-1] > seq {
+		// 同时间戳点之间的 TWAP 点对时长为 0，交换不影响累加器
+		w.setUnlocked(i, w.atUnlocked(i-1))
+		g.seqs[k] = g.seqs[k-1]
+		i, k = i-1, k-1
+	}
+	if k == len(g.seqs)-1 {
+		return
+	}
+	w.setUnlocked(i, pt)
+	g.seqs[k] = seq
+	w.LatestPrice.Store(w.lastUnlocked().Price.Int64())
+}
@@ -0,0 +1,60 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuplicateTsSeq(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetDuplicateTsPolicy(DuplicateSeq)
+	t0 := time.Unix(1_700_000_000, 0)
+	ts := t0.Add(time.Second)
+
+	trade := func(ts time.Time, seq uint64) Trade {
+		return Trade{
+			WindowPoint: WindowPoint{Ts: ts, Price: QtyLoz(1000000 + seq*100), Volume: 1e8, Side: SideBuy},
+			TradeMeta:   TradeMeta{Seq: seq},
+		}
+	}
+	w.AddTrades(trade(t0, 1))
+	// 同一毫秒的三笔乱序到达
+	for _, seq := range []uint64{4, 2, 3} {
+		w.AddTrades(trade(ts, seq))
+	}
+
+	for i, want := range []uint64{1, 2, 3, 4} {
+		if got := w.at(i).Price; got != QtyLoz(1000000+want*100) {
+			t.Fatalf("point %d price = %d, want seq %d", i, got, want)
+		}
+	}
+	if w.LatestPrice.Load() != 1000400 {
+		t.Fatalf("latest = %d", w.LatestPrice.Load())
+	}
+	if issues := w.Check(); len(issues) > 0 {
+		t.Fatalf("check: %v", issues)
+	}
+}
+
+func TestDuplicateTsMerge(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetDuplicateTsPolicy(DuplicateMerge)
+	ts := time.Unix(1_700_000_000, 0)
+
+	w.AddWindowPoint(SideBuy, 100, 1, ts)
+	w.AddWindowPoint(SideBuy, 103, 2, ts)
+	w.AddWindowPoint(SideSell, 99, 1, ts) // 方向不同，不合并
+
+	if w.size != 2 || w.nTrades.Load() != 3 {
+		t.Fatalf("size %d trades %d", w.size, w.nTrades.Load())
+	}
+	if got := w.at(0); got.PriceFloat(w.priceScale) != 102 || got.VolumeFloat(w.volumeScale) != 3 || got.Count != 2 {
+		t.Fatalf("merged point: %+v", got)
+	}
+	if hi, lo, _ := w.HighLow(); hi != 102 || lo != 99 {
+		t.Fatalf("high/low = %v/%v", hi, lo)
+	}
+	if issues := w.Check(); len(issues) > 0 {
+		t.Fatalf("check: %v", issues)
+	}
+}
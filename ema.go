@@ -1,5 +1,10 @@
 package sliding_window
 
+import (
+	"math"
+	"time"
+)
+
 type EMA struct {
 	Value       float64
 	Alpha       float64 // 0~1, 越大越偏向新数据
@@ -35,3 +40,13 @@ func (e *EMA) Get() (float64, bool) {
 	}
 	return e.Value, true
 }
+
+// DecayToward 按半衰期把当前值向 target 指数衰减 elapsed 时长，未初始化时不动
+func (e *EMA) DecayToward(target float64, elapsed, halfLife time.Duration) float64 {
+	if !e.Initialized || elapsed <= 0 || halfLife <= 0 {
+		return e.Value
+	}
+	k := math.Exp2(-float64(elapsed) / float64(halfLife))
+	e.Value = target + (e.Value-target)*k
+	return e.Value
+}
@@ -0,0 +1,80 @@
+package sliding_window
+
+import "time"
+
+type EMADecayTarget uint8
+
+const (
+	DecayToZero    EMADecayTarget = iota // 安静期向 0 衰减
+	DecayToLongRun                       // 安静期向长期均值衰减
+)
+
+// EMADecay 安静期成交量基准衰减配置
+type EMADecay struct {
+	HalfLife     time.Duration  // 半衰期，<=0 表示关闭
+	Idle         time.Duration  // 超过多久没有成交才开始衰减
+	Target       EMADecayTarget // 衰减目标
+	LongRunAlpha float64        // Target=DecayToLongRun 时，长期均值 EMA 的 alpha（建议远小于基准 alpha）
+}
+
+// SetEMADecay 配置成交量 EMA 的安静期衰减（写锁）。
+// 成交恢复时先按空闲时长衰减基准，再用新成交更新，避免 VolumeFactor 被忙时基准压低；
+// 读取基准时同样按窗口时钟（见 SetClock）算到当前时刻，空闲中的窗口不会报告未衰减的基准。
+// 重复设置相同的 LongRunAlpha（按 NewEMA 的取值范围比较）时保留长期均值的状态
+func (w *SlidingWindow) SetEMADecay(cfg EMADecay) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.emaDecay = cfg
+	if cfg.Target == DecayToLongRun {
+		if long := NewEMA(cfg.LongRunAlpha); w.emaLong == nil || w.emaLong.Alpha != long.Alpha {
+			w.emaLong = long
+		}
+	} else {
+		w.emaLong = nil
+	}
+}
+
+// updateVolumeEMAUnlocked 用一笔新成交更新成交量基准（要求持有写锁）
+func (w *SlidingWindow) updateVolumeEMAUnlocked(pt WindowPoint) {
+	if int64(pt.Volume) <= 0 {
+		return
+	}
+	v := float64(pt.Volume) / float64(w.volumeScale)
+
+	w.decayVolumeEMAUnlocked(w.ema, pt.Ts)
+
+	w.ema.Update(v)
+	if w.emaLong != nil {
+		w.emaLong.Update(v)
+	}
+	if pt.Ts.After(w.lastTradeTs) {
+		w.lastTradeTs = pt.Ts
+	}
+}
+
+// decayVolumeEMAUnlocked 按 now 距最后一笔成交的空闲时长把 e 向衰减目标衰减（要求持有锁）
+func (w *SlidingWindow) decayVolumeEMAUnlocked(e *EMA, now time.Time) {
+	d := w.emaDecay
+	if d.HalfLife <= 0 || w.lastTradeTs.IsZero() {
+		return
+	}
+	if idle := now.Sub(w.lastTradeTs) - d.Idle; idle > 0 {
+		target := 0.0
+		if w.emaLong != nil {
+			target, _ = w.emaLong.Get()
+		}
+		e.DecayToward(target, idle, d.HalfLife)
+	}
+}
+
+// volumeBaselineUnlocked 读取时刻的成交量基准（要求持有读锁）：配置了衰减时在副本上算到窗口时钟的当前时间，
+// 不修改 EMA 本身
+func (w *SlidingWindow) volumeBaselineUnlocked() (float64, bool) {
+	if w.emaDecay.HalfLife <= 0 {
+		return w.ema.Get()
+	}
+	e := *w.ema
+	w.decayVolumeEMAUnlocked(&e, w.now())
+	return e.Get()
+}
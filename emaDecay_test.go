@@ -0,0 +1,111 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func volumeBaseline(w *SlidingWindow) float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	v, _ := w.ema.Get()
+	return v
+}
+
+func TestEMADecay(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	feed := func(w *SlidingWindow) {
+		for s := 0; s < 5; s++ {
+			w.AddWindowPoint(SideBuy, 100, 10, t0.Add(time.Duration(s)*time.Second))
+		}
+		// 安静 64s 后恢复成交
+		w.AddWindowPoint(SideBuy, 100, 10, t0.Add(68*time.Second))
+	}
+
+	plain := NewSlidingWindow(10*time.Minute, 64, 0.5)
+	feed(plain)
+	if got := volumeBaseline(plain); got != 10 {
+		t.Fatalf("baseline without decay = %v, want 10", got)
+	}
+
+	// 空闲超过 Idle 的 54s 折合 5.4 个半衰期向 0 衰减，再以 alpha=0.5 计入新成交
+	w := NewSlidingWindow(10*time.Minute, 64, 0.5)
+	w.SetEMADecay(EMADecay{HalfLife: 10 * time.Second, Idle: 10 * time.Second})
+	feed(w)
+	want := 0.5*10 + 0.5*10*math.Exp2(-5.4)
+	if got := volumeBaseline(w); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("decayed baseline = %v, want %v", got, want)
+	}
+
+	// 间隔短于 Idle 时不衰减
+	w.AddWindowPoint(SideBuy, 100, 10, t0.Add(70*time.Second))
+	if got := volumeBaseline(w); math.Abs(got-(0.5*10+0.5*want)) > 1e-9 {
+		t.Fatalf("baseline within idle = %v", got)
+	}
+}
+
+func TestEMADecay_LongRun(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	w := NewSlidingWindow(10*time.Minute, 64, 0.5)
+	w.SetEMADecay(EMADecay{HalfLife: time.Second, Target: DecayToLongRun, LongRunAlpha: 0.01})
+
+	// 长期均值停留在 4 附近，忙时基准被抬到 100
+	w.AddWindowPoint(SideBuy, 100, 4, t0)
+	for s := 1; s <= 3; s++ {
+		w.AddWindowPoint(SideBuy, 100, 100, t0.Add(time.Duration(s)*time.Second))
+	}
+	w.mu.RLock()
+	long, _ := w.emaLong.Get()
+	w.mu.RUnlock()
+
+	// 安静一小时后基准几乎回到长期均值，而不是 0
+	w.AddWindowPoint(SideBuy, 100, 4, t0.Add(time.Hour))
+	if got := volumeBaseline(w); math.Abs(got-(0.5*4+0.5*long)) > 1e-6 {
+		t.Fatalf("baseline = %v, want about %v", got, 0.5*4+0.5*long)
+	}
+}
+
+func TestEMADecay_KeepsLongRunOnReconfigure(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	w := NewSlidingWindow(10*time.Minute, 64, 0.5)
+	cfg := EMADecay{HalfLife: time.Second, Target: DecayToLongRun} // LongRunAlpha 取默认值
+	w.SetEMADecay(cfg)
+	w.AddWindowPoint(SideBuy, 100, 4, t0)
+
+	w.mu.RLock()
+	long := w.emaLong
+	w.mu.RUnlock()
+	w.SetEMADecay(cfg)
+	w.mu.RLock()
+	kept := w.emaLong == long && long.Initialized
+	w.mu.RUnlock()
+	if !kept {
+		t.Fatal("reapplying the same config should keep the long-run EMA")
+	}
+}
+
+func TestEMADecay_AppliedOnRead(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	clk := NewManualClock(t0)
+	w := NewSlidingWindow(10*time.Minute, 64, 0.5)
+	w.SetClock(clk)
+	w.SetEMADecay(EMADecay{HalfLife: 10 * time.Second, Idle: 10 * time.Second})
+	for s := 0; s < 5; s++ {
+		w.AddWindowPoint(SideBuy, 100, 10, t0.Add(time.Duration(s)*time.Second))
+	}
+	clk.Set(t0.Add(4 * time.Second))
+	vf0, ok := w.VolumeFactor()
+	if !ok || math.Abs(vf0-1) > 1e-9 {
+		t.Fatalf("volume factor = %v, %v", vf0, ok)
+	}
+
+	// 没有新成交，空闲 10s + 一个半衰期后读取：基准减半，VolumeFactor 翻倍
+	clk.Advance(20 * time.Second)
+	if vf, _ := w.VolumeFactor(); math.Abs(vf-2) > 1e-9 {
+		t.Fatalf("volume factor while idle = %v, want 2", vf)
+	}
+	if got := volumeBaseline(w); got != 10 {
+		t.Fatalf("reading must not modify the EMA: %v", got)
+	}
+}
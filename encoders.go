@@ -0,0 +1,195 @@
+package sliding_window
+
+import (
+	"encoding/csv"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SeriesTags 写入时序库时附带的标签
+type SeriesTags struct {
+	Symbol string
+	Venue  string
+}
+
+// snapshotField Snapshot 的一个可导出数值字段（按 json 标签命名）
+type snapshotField struct {
+	name  string
+	index int
+	isInt bool
+}
+
+// snapshotFields Snapshot 的字段表，Ts 作为时间戳单独输出，不在其中
+var snapshotFields = sync.OnceValue(func() []snapshotField {
+	t := reflect.TypeOf(Snapshot{})
+	out := make([]snapshotField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || name == "ts" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Float64:
+			out = append(out, snapshotField{name: name, index: i})
+		case reflect.Int64:
+			out = append(out, snapshotField{name: name, index: i, isInt: true})
+		}
+	}
+	return out
+})
+
+// LineProtocolWriter 把快照写成 InfluxDB line protocol，每个快照一行：
+//
+//	measurement,symbol=BTCUSDT,venue=binance highest_price=101,...,n_trades=12i 1700000000000000000
+//
+// 时间戳为纳秒（由 Snapshot.Ts 毫秒换算）。非并发安全。
+type LineProtocolWriter struct {
+	w           io.Writer
+	measurement string
+	buf         []byte
+}
+
+// NewLineProtocolWriter measurement 为空时使用 "sliding_window"
+func NewLineProtocolWriter(w io.Writer, measurement string) *LineProtocolWriter {
+	if measurement == "" {
+		measurement = "sliding_window"
+	}
+	return &LineProtocolWriter{w: w, measurement: measurement}
+}
+
+// Write 写入一个快照；nil 快照忽略
+func (lp *LineProtocolWriter) Write(tags SeriesTags, s *Snapshot) error {
+	if s == nil {
+		return nil
+	}
+	b := lp.buf[:0]
+	b = appendLPEscaped(b, lp.measurement, ", ")
+	if tags.Symbol != "" {
+		b = append(b, ",symbol="...)
+		b = appendLPEscaped(b, tags.Symbol, ", =")
+	}
+	if tags.Venue != "" {
+		b = append(b, ",venue="...)
+		b = appendLPEscaped(b, tags.Venue, ", =")
+	}
+	// 窗口标签按 key 排序追加，symbol / venue 以 SeriesTags 为准
+	for _, k := range slices.Sorted(maps.Keys(s.Tags)) {
+		if (k == "symbol" && tags.Symbol != "") || (k == "venue" && tags.Venue != "") || s.Tags[k] == "" {
+			continue
+		}
+		b = append(b, ',')
+		b = appendLPEscaped(b, k, ", =")
+		b = append(b, '=')
+		b = appendLPEscaped(b, s.Tags[k], ", =")
+	}
+
+	v := reflect.ValueOf(s).Elem()
+	for i, f := range snapshotFields() {
+		if i == 0 {
+			b = append(b, ' ')
+		} else {
+			b = append(b, ',')
+		}
+		b = append(b, f.name...)
+		b = append(b, '=')
+		if f.isInt {
+			b = strconv.AppendInt(b, v.Field(f.index).Int(), 10)
+			b = append(b, 'i')
+		} else {
+			b = strconv.AppendFloat(b, v.Field(f.index).Float(), 'g', -1, 64)
+		}
+	}
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, s.Ts*1e6, 10)
+	b = append(b, '\n')
+
+	lp.buf = b
+	_, err := lp.w.Write(b)
+	return err
+}
+
+// WriteMany 按 symbol 写入 Manager.SnapshotMany 的结果，venue 对所有行相同
+func (lp *LineProtocolWriter) WriteMany(venue string, snaps map[string]*Snapshot) error {
+	for _, sym := range slices.Sorted(maps.Keys(snaps)) {
+		if err := lp.Write(SeriesTags{Symbol: sym, Venue: venue}, snaps[sym]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendLPEscaped 按 line protocol 规则对 special 中的字符加反斜杠
+func appendLPEscaped(b []byte, s, special string) []byte {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(special, s[i]) >= 0 {
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return b
+}
+
+// CSVWriter 把快照写成 CSV：首行表头 ts,symbol,venue,<快照字段...>。
+// 内部带缓冲，写完后调用 Flush。非并发安全。
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+	row    []string
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write 写入一个快照（首次写入前先写表头）；nil 快照忽略
+func (cw *CSVWriter) Write(tags SeriesTags, s *Snapshot) error {
+	if s == nil {
+		return nil
+	}
+	fields := snapshotFields()
+	if !cw.header {
+		head := make([]string, 0, len(fields)+3)
+		head = append(head, "ts", "symbol", "venue")
+		for _, f := range fields {
+			head = append(head, f.name)
+		}
+		if err := cw.w.Write(head); err != nil {
+			return err
+		}
+		cw.header = true
+	}
+
+	row := append(cw.row[:0], strconv.FormatInt(s.Ts, 10), tags.Symbol, tags.Venue)
+	v := reflect.ValueOf(s).Elem()
+	for _, f := range fields {
+		if f.isInt {
+			row = append(row, strconv.FormatInt(v.Field(f.index).Int(), 10))
+		} else {
+			row = append(row, strconv.FormatFloat(v.Field(f.index).Float(), 'g', -1, 64))
+		}
+	}
+	cw.row = row
+	return cw.w.Write(row)
+}
+
+// WriteMany 按 symbol 写入 Manager.SnapshotMany 的结果，venue 对所有行相同
+func (cw *CSVWriter) WriteMany(venue string, snaps map[string]*Snapshot) error {
+	for _, sym := range slices.Sorted(maps.Keys(snaps)) {
+		if err := cw.Write(SeriesTags{Symbol: sym, Venue: venue}, snaps[sym]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush 刷出缓冲并返回期间的写入错误
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,46 @@
+package sliding_window
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestLineProtocolWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lp := NewLineProtocolWriter(&buf, "")
+	s := &Snapshot{HighestPrice: 101.5, NTrades: 12, Ts: 1700000000000}
+	if err := lp.Write(SeriesTags{Symbol: "BTC USDT", Venue: "a,b"}, s); err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+	if !strings.HasPrefix(line, `sliding_window,symbol=BTC\ USDT,venue=a\,b highest_price=101.5,`) {
+		t.Fatalf("prefix: %q", line)
+	}
+	if !strings.Contains(line, ",n_trades=12i,") || !strings.HasSuffix(line, " 1700000000000000000\n") {
+		t.Fatalf("line: %q", line)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewCSVWriter(&buf)
+	err := cw.WriteMany("binance", map[string]*Snapshot{
+		"ETH": {LatestPrice: 2000, Ts: 2},
+		"BTC": {LatestPrice: 60000, Ts: 1},
+	})
+	if err != nil || cw.Flush() != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "ts" || rows[1][1] != "BTC" || rows[2][2] != "binance" {
+		t.Fatalf("rows: %v", rows)
+	}
+	if len(rows[0]) != len(snapshotFields())+3 {
+		t.Fatalf("header: %v", rows[0])
+	}
+}
@@ -0,0 +1,218 @@
+package sliding_window
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ===== Side =====
+
+var sideNames = map[Side]string{
+	SideUnknown: "unknown",
+	SideBuy:     "buy",
+	SideSell:    "sell",
+}
+
+func (s Side) String() string {
+	if n, ok := sideNames[s]; ok {
+		return n
+	}
+	return "Side(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ParseSide 解析 "buy"/"sell"/"unknown"（不区分大小写，兼容 "b"/"s"/"bid"/"ask" 与数字），空串返回错误
+func ParseSide(s string) (Side, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "buy", "b", "bid":
+		return SideBuy, nil
+	case "sell", "s", "ask":
+		return SideSell, nil
+	case "unknown":
+		return SideUnknown, nil
+	case "":
+		return SideUnknown, fmt.Errorf("empty side")
+	}
+	n, err := parseEnumInt(s, int(SideUnknown), int(SideSell))
+	if err != nil {
+		return SideUnknown, fmt.Errorf("invalid side %q", s)
+	}
+	return Side(n), nil
+}
+
+func (s Side) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+func (s *Side) UnmarshalText(b []byte) error {
+	v, err := ParseSide(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// UnmarshalJSON 兼容旧格式的数字编码
+func (s *Side) UnmarshalJSON(b []byte) error {
+	return s.UnmarshalText(unquoteJSON(b))
+}
+
+// ===== MomentumLevel =====
+
+var momentumNames = map[MomentumLevel]string{
+	MomentumStrongDown: "strong_down",
+	MomentumDown:       "down",
+	MomentumNeutral:    "neutral",
+	MomentumUp:         "up",
+	MomentumStrongUp:   "strong_up",
+}
+
+func (l MomentumLevel) String() string {
+	if n, ok := momentumNames[l]; ok {
+		return n
+	}
+	return "MomentumLevel(" + strconv.Itoa(int(l)) + ")"
+}
+
+func ParseMomentumLevel(s string) (MomentumLevel, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	for l, n := range momentumNames {
+		if n == key {
+			return l, nil
+		}
+	}
+	n, err := parseEnumInt(s, int(MomentumStrongDown), int(MomentumStrongUp))
+	if err != nil {
+		return MomentumNeutral, fmt.Errorf("invalid momentum level %q", s)
+	}
+	return MomentumLevel(n), nil
+}
+
+func (l MomentumLevel) MarshalText() ([]byte, error) { return []byte(l.String()), nil }
+
+func (l *MomentumLevel) UnmarshalText(b []byte) error {
+	v, err := ParseMomentumLevel(string(b))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+func (l *MomentumLevel) UnmarshalJSON(b []byte) error {
+	return l.UnmarshalText(unquoteJSON(b))
+}
+
+// ===== ADKind =====
+
+var adKindNames = map[ADKind]string{
+	ADNeutral:      "neutral",
+	ADAbsorption:   "absorption",
+	ADDistribution: "distribution",
+}
+
+func (k ADKind) String() string {
+	if n, ok := adKindNames[k]; ok {
+		return n
+	}
+	return "ADKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+func ParseADKind(s string) (ADKind, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	for k, n := range adKindNames {
+		if n == key {
+			return k, nil
+		}
+	}
+	n, err := parseEnumInt(s, int(ADNeutral), int(ADDistribution))
+	if err != nil {
+		return ADNeutral, fmt.Errorf("invalid AD kind %q", s)
+	}
+	return ADKind(n), nil
+}
+
+func (k ADKind) MarshalText() ([]byte, error) { return []byte(k.String()), nil }
+
+func (k *ADKind) UnmarshalText(b []byte) error {
+	v, err := ParseADKind(string(b))
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+func (k *ADKind) UnmarshalJSON(b []byte) error {
+	return k.UnmarshalText(unquoteJSON(b))
+}
+
+// ===== MarketStateKind =====
+
+var marketStateNames = map[MarketStateKind]string{
+	MarketIlliquid: "illiquid",
+	MarketRanging:  "ranging",
+	MarketTrending: "trending",
+	MarketVolatile: "volatile",
+}
+
+func (k MarketStateKind) String() string {
+	if n, ok := marketStateNames[k]; ok {
+		return n
+	}
+	return "MarketStateKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+func ParseMarketStateKind(s string) (MarketStateKind, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	for k, n := range marketStateNames {
+		if n == key {
+			return k, nil
+		}
+	}
+	n, err := parseEnumInt(s, int(MarketIlliquid), int(MarketVolatile))
+	if err != nil {
+		return MarketIlliquid, fmt.Errorf("invalid market state %q", s)
+	}
+	return MarketStateKind(n), nil
+}
+
+func (k MarketStateKind) MarshalText() ([]byte, error) { return []byte(k.String()), nil }
+
+func (k *MarketStateKind) UnmarshalText(b []byte) error {
+	v, err := ParseMarketStateKind(string(b))
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+func (k *MarketStateKind) UnmarshalJSON(b []byte) error {
+	return k.UnmarshalText(unquoteJSON(b))
+}
+
+// ===== helpers =====
+
+func parseEnumInt(s string, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%d out of range [%d,%d]", n, lo, hi)
+	}
+	return n, nil
+}
+
+// unquoteJSON 去掉 JSON 字符串的引号；数字原样返回
+func unquoteJSON(b []byte) []byte {
+	b = bytes.TrimSpace(b)
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		if s, err := strconv.Unquote(string(b)); err == nil {
+			return []byte(s)
+		}
+		return b[1 : len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,54 @@
+package sliding_window
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestEnums_RoundTrip(t *testing.T) {
+	for _, l := range []MomentumLevel{MomentumStrongDown, MomentumDown, MomentumNeutral, MomentumUp, MomentumStrongUp} {
+		got, err := ParseMomentumLevel(l.String())
+		if err != nil || got != l {
+			t.Fatalf("momentum %v: got %v err %v", l, got, err)
+		}
+	}
+	for _, k := range []ADKind{ADNeutral, ADAbsorption, ADDistribution} {
+		got, err := ParseADKind(k.String())
+		if err != nil || got != k {
+			t.Fatalf("ad kind %v: got %v err %v", k, got, err)
+		}
+	}
+
+	pt := WindowPoint{Ts: time.Unix(1_700_000_000, 0).UTC(), Side: SideSell}
+	b, err := json.Marshal(pt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tb, _ := json.Marshal(Trade{WindowPoint: pt}); strings.Contains(string(tb), "recv_ts") {
+		t.Fatalf("zero RecvTs should be omitted: %s", tb)
+	}
+	if n := unsafe.Sizeof(pt); unsafe.Sizeof(uintptr(0)) == 8 && n != 48 {
+		t.Fatalf("WindowPoint is %d bytes, update the size note on the type", n)
+	}
+	var back WindowPoint
+	if err := json.Unmarshal(b, &back); err != nil || back.Side != SideSell {
+		t.Fatalf("round trip %s: side=%v err=%v", b, back.Side, err)
+	}
+
+	// 兼容旧的数字编码
+	if err := json.Unmarshal([]byte(`{"side":1}`), &back); err != nil || back.Side != SideBuy {
+		t.Fatalf("numeric side: side=%v err=%v", back.Side, err)
+	}
+	if _, err := ParseSide("sideways"); err == nil {
+		t.Fatal("expected error for invalid side")
+	}
+	if _, err := ParseSide(" "); err == nil {
+		t.Fatal("expected error for empty side")
+	}
+	if s, err := ParseSide("unknown"); err != nil || s != SideUnknown {
+		t.Fatalf("unknown: side=%v err=%v", s, err)
+	}
+}
@@ -1,6 +1,7 @@
 package sliding_window
 
 import (
+	"fmt"
 	"math"
 	"sort"
 )
@@ -22,6 +23,18 @@ type WindowStats struct {
 
 	// 用于 median（也用 ticks，避免 float 排序误差）
 	Prices []float64
+
+	pb *pricesBuf // Prices 背后的池化 buffer，用完需 release
+}
+
+// releaseStats 把 Prices 归还到池中，之后不能再访问 stats.Prices
+func (w *SlidingWindow) releaseStats(stats *WindowStats) {
+	if stats.pb == nil {
+		return
+	}
+	w.putPricesBuf(stats.pb)
+	stats.pb = nil
+	stats.Prices = nil
 }
 
 func (w *SlidingWindow) collectStats() (WindowStats, bool) {
@@ -33,8 +46,9 @@ func (w *SlidingWindow) collectStats() (WindowStats, bool) {
 	if w.size < 2 {
 		return stats, false
 	}
+	// buffer 由调用方通过 releaseStats 归还：在这里 defer put 会让调用方拿到已经回池的切片
 	prices, p1 := w.getPricesBuf(w.size)
-	defer w.putPricesBuf(p1)
+	stats.pb = p1
 
 	n := w.size
 	stats.Prices = prices[:n] // ✅ 关键：把 stats.Prices 指向外部 buffer
@@ -42,6 +56,7 @@ func (w *SlidingWindow) collectStats() (WindowStats, bool) {
 	first := w.atUnlocked(0)
 	hi := first.Price.Float(w.priceScale)
 	lo := hi
+	var sumPV, sumV compensated
 
 	oldest := first
 	newest := w.lastUnlocked()
@@ -63,35 +78,73 @@ func (w *SlidingWindow) collectStats() (WindowStats, bool) {
 			lo = px
 		}
 
-		stats.SumPV += px * v
-		stats.SumV += v
+		sumPV.add(px * v)
+		sumV.add(v)
 	}
 
+	stats.SumPV = sumPV.value()
+	stats.SumV = sumV.value()
 	stats.HighTicks = hi
 	stats.LowTicks = lo
 	return stats, true
 }
 
+// EquilibriumZone VWAP 与中位数加权得到均衡价及其通道
+//
+//metric:name=equilibrium_zone unit=price cost=O(nlogn) min_points=2
 func (w *SlidingWindow) EquilibriumZone(alpha, beta float64) (EquilibriumZone, bool) {
-	var empty EquilibriumZone
+	if !w.IsReady() {
+		return EquilibriumZone{}, false
+	}
+	if w.metricTTLOn() {
+		key := fmt.Sprintf("equilibrium_zone|%g|%g", alpha, beta)
+		return cachedMetric(w, "equilibrium_zone", key, func() (EquilibriumZone, bool) {
+			return w.computeEquilibriumZone(alpha, beta)
+		})
+	}
+	return w.computeEquilibriumZone(alpha, beta)
+}
 
+func (w *SlidingWindow) computeEquilibriumZone(alpha, beta float64) (EquilibriumZone, bool) {
 	w.mu.RLock()
+	in, ok := w.gatherZoneUnlocked()
+	w.mu.RUnlock()
+	if !ok {
+		return EquilibriumZone{}, false
+	}
+
+	// 排序等计算放在锁外
+	return w.computeZone(in, alpha, beta)
+}
+
+// zoneInputs 计算均衡区所需的原始数据，prices 来自池，由 computeZone 归还
+type zoneInputs struct {
+	prices         []float64
+	pb             *pricesBuf
+	high, low      float64
+	oldest, newest float64
+	sumPV, sumV    float64
+}
+
+// gatherZoneUnlocked 一次遍历收集均衡区输入（要求持有读锁）
+func (w *SlidingWindow) gatherZoneUnlocked() (zoneInputs, bool) {
+	var in zoneInputs
 	if w.size < 2 {
-		w.mu.RUnlock()
-		return empty, false
+		return in, false
 	}
 
 	n := w.size
 	prices, pb := w.getPricesBuf(n)
+	in.prices, in.pb = prices, pb
 
 	first := w.atUnlocked(0)
-	high := first.Price.Float(w.priceScale)
-	low := high
+	in.high = first.Price.Float(w.priceScale)
+	in.low = in.high
 
-	oldest := first.Price.Float(w.priceScale)
-	newest := w.lastUnlocked().Price.Float(w.priceScale)
+	in.oldest = first.Price.Float(w.priceScale)
+	in.newest = w.zonePriceUnlocked()
 
-	var sumPV, sumV float64
+	var accPV, accV compensated
 
 	for i := 0; i < n; i++ {
 		pt := w.atUnlocked(i)
@@ -100,26 +153,33 @@ func (w *SlidingWindow) EquilibriumZone(alpha, beta float64) (EquilibriumZone, b
 
 		prices[i] = px
 
-		if px > high {
-			high = px
+		if px > in.high {
+			in.high = px
 		}
-		if px < low {
-			low = px
+		if px < in.low {
+			in.low = px
 		}
 
-		sumPV += px * v
-		sumV += v
+		accPV.add(px * v)
+		accV.add(v)
 	}
-	w.mu.RUnlock()
+	in.sumPV, in.sumV = accPV.value(), accV.value()
+	return in, true
+}
+
+// computeZone 由输入计算均衡区，并把价格缓冲归还到池（不需要持锁）
+func (w *SlidingWindow) computeZone(in zoneInputs, alpha, beta float64) (EquilibriumZone, bool) {
+	var empty EquilibriumZone
+	defer w.putPricesBuf(in.pb)
 
-	// ====== 从这里开始，所有 return 前都要 put ======
-	if sumV <= 0 {
-		w.putPricesBuf(pb)
+	if in.sumV <= 0 {
 		return empty, false
 	}
 
-	vwap := sumPV / sumV
+	vwap := in.sumPV / in.sumV
 
+	prices := in.prices
+	n := len(prices)
 	sort.Float64s(prices)
 
 	var median float64
@@ -129,15 +189,22 @@ func (w *SlidingWindow) EquilibriumZone(alpha, beta float64) (EquilibriumZone, b
 		median = (prices[n/2-1] + prices[n/2]) / 2
 	}
 
+	return zoneFrom(in, vwap, median, alpha, beta)
+}
+
+// zoneFrom 由 VWAP、中位数与高低点 / 首尾价计算均衡区
+func zoneFrom(in zoneInputs, vwap, median, alpha, beta float64) (EquilibriumZone, bool) {
+	var empty EquilibriumZone
+
 	equ := alpha*vwap + (1-alpha)*median
 
-	rng := high - low
-	if rng <= 0 || oldest == 0 {
-		w.putPricesBuf(pb)
+	rng := in.high - in.low
+	if rng <= 0 || in.oldest == 0 {
 		return empty, false
 	}
 
-	ret := (newest - oldest) / oldest
+	newest := in.newest
+	ret := (newest - in.oldest) / in.oldest
 	retScale := math.Abs(ret) * newest
 
 	bw := beta * rng
@@ -145,12 +212,11 @@ func (w *SlidingWindow) EquilibriumZone(alpha, beta float64) (EquilibriumZone, b
 		bw = retScale
 	}
 	if bw <= 1e-12 {
-		w.putPricesBuf(pb)
 		return empty, false
 	}
 
 	dist := newest - equ
-	zone := EquilibriumZone{
+	return EquilibriumZone{
 		EquPrice:  equ,
 		UpperBand: equ + bw,
 		LowerBand: equ - bw,
@@ -158,10 +224,7 @@ func (w *SlidingWindow) EquilibriumZone(alpha, beta float64) (EquilibriumZone, b
 		Price:     newest,
 		Distance:  dist,
 		NormDist:  dist / bw,
-	}
-
-	w.putPricesBuf(pb)
-	return zone, true
+	}, true
 }
 
 type EquilibriumZone struct {
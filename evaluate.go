@@ -0,0 +1,144 @@
+package sliding_window
+
+import "math"
+
+// MetricID Evaluate 支持的标量指标，取值与注册表中的指标名一致
+type MetricID string
+
+const (
+	MetricVWAP              MetricID = "vwap"
+	MetricMedianPrice       MetricID = "median_price"
+	MetricRealizedVol       MetricID = "realized_vol"
+	MetricMaxDrawdown       MetricID = "max_drawdown"
+	MetricMaxRunUp          MetricID = "max_run_up"
+	MetricTWAP              MetricID = "twap"
+	MetricExecutionPressure MetricID = "execution_pressure"
+	MetricTimeImbalance     MetricID = "time_imbalance"
+	MetricImbalance         MetricID = "imbalance"
+	MetricDeltaVolume       MetricID = "delta_volume"
+	MetricSumVolume         MetricID = "sum_volume"
+	MetricAvgVolumePerPoint MetricID = "avg_volume_per_point"
+	MetricVolumePerSecond   MetricID = "volume_per_second"
+	MetricVelocity          MetricID = "velocity"
+	MetricMomentum          MetricID = "momentum"
+)
+
+// Evaluate 在同一个读锁内批量计算指标：需要遍历窗口的指标（VWAP、中位数、realized vol、
+// 回撤/上冲）共用一次遍历，中位数的排序在锁外完成。结果与逐个调用对应方法一致；
+// 未知指标或数据不足（对应方法返回 false）的指标不出现在结果中
+func (w *SlidingWindow) Evaluate(metrics []MetricID) map[MetricID]float64 {
+	want := make(map[MetricID]bool, len(metrics))
+	for _, m := range metrics {
+		want[m] = true
+	}
+	out := make(map[MetricID]float64, len(metrics))
+	if !w.IsReady() {
+		return out
+	}
+	set := func(m MetricID, v float64, ok bool) {
+		if ok && want[m] {
+			out[m] = v
+		}
+	}
+
+	needPass := want[MetricVWAP] || want[MetricMedianPrice] || want[MetricRealizedVol] ||
+		want[MetricMaxDrawdown] || want[MetricMaxRunUp]
+
+	w.mu.RLock()
+
+	// --- O(1) 指标 ---
+	total, buy, sell := w.volumesUnlocked()
+	set(MetricSumVolume, total, true)
+	set(MetricDeltaVolume, buy-sell, true)
+	set(MetricImbalance, w.Imbalance(), true)
+	if want[MetricAvgVolumePerPoint] || want[MetricVolumePerSecond] {
+		conv := 1.0
+		if w.volConv.Load() != nil {
+			conv = w.volumeRateConvUnlocked()
+		}
+		set(MetricAvgVolumePerPoint, QtyLoz(w.avgVolPerPoint.Load()).Float(w.volumeScale)*conv, true)
+		set(MetricVolumePerSecond, QtyLoz(w.volPerSecond.Load()).Float(w.volumeScale)*conv, true)
+	}
+	if want[MetricTWAP] {
+		v, ok := w.twapUnlocked()
+		set(MetricTWAP, v, ok)
+	}
+	if want[MetricExecutionPressure] {
+		v, ok := w.executionPressureUnlocked()
+		set(MetricExecutionPressure, v, ok)
+	}
+	if want[MetricVelocity] {
+		v, ok := w.velocityUnlocked()
+		set(MetricVelocity, v, ok)
+	}
+	if want[MetricMomentum] {
+		v, ok := w.momentumUnlocked()
+		set(MetricMomentum, v, ok)
+	}
+	if want[MetricTimeImbalance] {
+		v, ok := w.timeImbalanceUnlocked()
+		set(MetricTimeImbalance, v, ok)
+	}
+
+	// --- 共用一次遍历的 O(n) 指标 ---
+	var (
+		prices []float64
+		pb     *pricesBuf
+	)
+	if needPass && w.size >= 2 {
+		if want[MetricMedianPrice] {
+			prices, pb = w.getPricesBuf(w.size)
+			prices = prices[:w.size]
+		}
+
+		var sumPV, sumV compensated
+		var sumsq, dd, ru float64
+		first := w.atUnlocked(0).Price.Float(w.priceScale)
+		prev, peak, trough := first, first, first
+		rvOK := first > 0
+
+		for i := 0; i < w.size; i++ {
+			pt := w.atUnlocked(i)
+			px := pt.Price.Float(w.priceScale)
+			if prices != nil {
+				prices[i] = px
+			}
+			sumPV.add(px * pt.Volume.Float(w.volumeScale))
+			sumV.add(pt.Volume.Float(w.volumeScale))
+
+			if i == 0 {
+				continue
+			}
+			// 与 realizedVolUnlocked 相同：非正价格跳过，但作为下一段的起点
+			if px > 0 {
+				r := math.Log(px / prev)
+				sumsq += r * r
+			}
+			prev = px
+
+			peak = max(peak, px)
+			trough = min(trough, px)
+			if peak > 0 {
+				dd = max(dd, (peak-px)/peak)
+			}
+			if trough > 0 {
+				ru = max(ru, (px-trough)/trough)
+			}
+		}
+
+		if sv := sumV.value(); sv > 0 {
+			set(MetricVWAP, sumPV.value()/sv, true)
+		}
+		set(MetricRealizedVol, math.Sqrt(sumsq), rvOK)
+		set(MetricMaxDrawdown, dd, true)
+		set(MetricMaxRunUp, ru, true)
+	}
+	w.mu.RUnlock()
+
+	if prices != nil {
+		med, _ := w.medianPrice(WindowStats{Prices: prices})
+		set(MetricMedianPrice, med, true)
+		w.putPricesBuf(pb)
+	}
+	return out
+}
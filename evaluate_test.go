@@ -0,0 +1,56 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateMatchesMethods(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 512, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 200; i++ {
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		px := 100 + float64(i%23)*0.05 - float64(i%7)*0.03
+		w.AddWindowPoint(side, px, 0.5+float64(i%5), t0.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	all := []MetricID{
+		MetricVWAP, MetricMedianPrice, MetricRealizedVol, MetricMaxDrawdown, MetricMaxRunUp,
+		MetricTWAP, MetricExecutionPressure, MetricTimeImbalance, MetricImbalance, MetricDeltaVolume,
+		MetricSumVolume, MetricAvgVolumePerPoint, MetricVolumePerSecond, MetricVelocity, MetricMomentum,
+	}
+	for _, id := range all {
+		if _, ok := LookupMetric(string(id)); !ok {
+			t.Fatalf("%s is not a registry metric", id)
+		}
+	}
+
+	got := w.Evaluate(all)
+	vwap, _ := w.VolumeWeightedAveragePrice()
+	med, _ := w.MedianPrice()
+	rv, _ := w.RealizedVol()
+	dd, _ := w.MaxDrawdown()
+	ru, _ := w.MaxRunUp()
+	twap, _ := w.TWAP()
+	vel, _ := w.Velocity()
+	mom, _ := w.Momentum()
+	want := map[MetricID]float64{
+		MetricVWAP: vwap, MetricMedianPrice: med, MetricRealizedVol: rv,
+		MetricMaxDrawdown: dd.Value, MetricMaxRunUp: ru.Value, MetricTWAP: twap,
+		MetricImbalance: w.Imbalance(), MetricDeltaVolume: w.DeltaVolume(), MetricSumVolume: w.SumVolume(),
+		MetricAvgVolumePerPoint: w.AvgVolumePerPoint(), MetricVolumePerSecond: w.VolumePerSecond(),
+		MetricVelocity: vel, MetricMomentum: mom,
+	}
+	for id, v := range want {
+		if g, ok := got[id]; !ok || g != v {
+			t.Errorf("%s = %v (ok=%v), want %v", id, g, ok, v)
+		}
+	}
+
+	if got := w.Evaluate([]MetricID{"nope", MetricImbalance}); len(got) != 1 {
+		t.Fatalf("unknown metric should be skipped: %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package sliding_window
+
+// SetEvictHook 设置出窗回调（写锁），fn 为 nil 时关闭。每个出窗的点（过期、环满覆盖、按点数出窗）
+// 都会回调一次，按出窗顺序在写锁释放后派发，回调内可以安全地读取窗口；用于把过期成交转发到
+// 更长周期的存储或降采样归档，而不必重新接入行情。回调 panic 按 HookEvict 隔离
+func (w *SlidingWindow) SetEvictHook(fn func(WindowPoint)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.evictHook = fn
+	if fn == nil {
+		w.pendingEvicted = nil
+	}
+}
+
+// WithEvictHook 构造时设置出窗回调，见 SetEvictHook
+func WithEvictHook(fn func(WindowPoint)) Option {
+	return func(o *windowOptions) { o.evictHook = fn }
+}
+
+// observeEvictUnlocked 登记一个出窗点，等待写锁释放后派发（要求持有写锁）
+func (w *SlidingWindow) observeEvictUnlocked(pt WindowPoint) {
+	if w.evictHook != nil {
+		w.pendingEvicted = append(w.pendingEvicted, pt)
+	}
+}
+
+// takeEvictedUnlocked 取走待派发的出窗点（要求持有写锁）
+func (w *SlidingWindow) takeEvictedUnlocked() ([]WindowPoint, func(WindowPoint)) {
+	if len(w.pendingEvicted) == 0 {
+		return nil, nil
+	}
+	pts := w.pendingEvicted
+	w.pendingEvicted = nil
+	return pts, w.evictHook
+}
@@ -0,0 +1,41 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictHook(t *testing.T) {
+	var got []WindowPoint
+	var w *SlidingWindow
+	w = New(3*time.Second, 4, WithEMAAlpha(0.1), WithEvictHook(func(pt WindowPoint) {
+		got = append(got, pt)
+		_ = w.SumVolume() // 写锁已释放，回调内可以读窗口
+	}))
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 环容量 4：第 5 个点覆盖最旧的点
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	if len(got) != 1 || got[0].Price.Float(w.priceScale) != 100 {
+		t.Fatalf("overwrite eviction: %+v", got)
+	}
+
+	// 按时间过期：10s 时前面的点全部出窗，按出窗顺序回调
+	w.AddWindowPoint(SideSell, 110, 1, t0.Add(10*time.Second))
+	if len(got) != 5 {
+		t.Fatalf("got %d evictions, want 5", len(got))
+	}
+	for i, pt := range got {
+		if pt.Price.Float(w.priceScale) != 100+float64(i) {
+			t.Fatalf("eviction %d out of order: %+v", i, pt)
+		}
+	}
+
+	w.SetEvictHook(nil)
+	w.ExpireNow(t0.Add(time.Minute))
+	if len(got) != 5 {
+		t.Fatal("hook should be disabled")
+	}
+}
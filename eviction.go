@@ -0,0 +1,72 @@
+package sliding_window
+
+import "time"
+
+// EvictionPolicy 窗口的出窗方式
+type EvictionPolicy uint8
+
+const (
+	// EvictByTime 按时间出窗：保留最近 duration 内的点（默认）
+	EvictByTime EvictionPolicy = iota
+	// EvictByCount 按点数出窗：始终保留最近 N 个点，没有时间边界
+	EvictByCount
+	// EvictHybrid 时间与点数任一条件先满足即出窗
+	EvictHybrid
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictByCount:
+		return "count"
+	case EvictHybrid:
+		return "hybrid"
+	default:
+		return "time"
+	}
+}
+
+// NewSlidingWindowCount 按点数出窗的窗口：保留最近 n 个点，适合逐笔（tick-count）研究。
+// 依赖窗口时长的指标（DeltaAcceleration、VWAPReversion 的近端区间、RVOL 等）改用窗口内数据的实际跨度
+func NewSlidingWindowCount(n int, emaAlpha float64) *SlidingWindow {
+	return New(0, n, WithEMAAlpha(emaAlpha), WithEvictionPolicy(EvictByCount, n))
+}
+
+// NewSlidingWindowHybrid 混合出窗：超过 duration 或超过 n 个点，任一条件先满足即出窗
+func NewSlidingWindowHybrid(duration time.Duration, n int, emaAlpha float64) *SlidingWindow {
+	return New(duration, n, WithEMAAlpha(emaAlpha), WithEvictionPolicy(EvictHybrid, n))
+}
+
+// thresholdUnlocked 以 now 为最新时间的过期边界（Ts <= 边界的点出窗）；纯计数模式没有时间边界
+func (w *SlidingWindow) thresholdUnlocked(now time.Time) time.Time {
+	if w.evict == EvictByCount {
+		return time.Time{}
+	}
+	return expireThreshold(now, w.duration)
+}
+
+// countFullUnlocked 点数已达上限，写入前需要先移出最旧的点（要求持有锁）
+func (w *SlidingWindow) countFullUnlocked() bool {
+	return w.maxPoints > 0 && w.size >= w.maxPoints
+}
+
+// trimCountedUnlocked 计数出窗后，标注、混合序列与自有成交按最旧点的时间同步过期（要求持有写锁）
+func (w *SlidingWindow) trimCountedUnlocked() {
+	if w.maxPoints <= 0 || w.size == 0 {
+		return
+	}
+	head := w.atUnlocked(0).Ts.Add(-1)
+	w.trimMarkersUnlocked(head)
+	w.trimBlendUnlocked(head)
+	w.trimOwnFillsUnlocked(head)
+}
+
+// windowDurationUnlocked 窗口的有效时长：按时间出窗时为 duration，纯计数模式为最早到最新一笔的跨度（要求持有锁）
+func (w *SlidingWindow) windowDurationUnlocked() time.Duration {
+	if w.evict != EvictByCount {
+		return w.duration
+	}
+	if w.size < 2 {
+		return 0
+	}
+	return w.lastUnlocked().Ts.Sub(w.atUnlocked(0).Ts)
+}
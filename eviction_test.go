@@ -0,0 +1,50 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictByCount(t *testing.T) {
+	w := NewSlidingWindowCount(5, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	// 间隔远大于任何时间窗口，也只按点数出窗
+	for i := 0; i < 8; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Hour))
+	}
+
+	if w.size != 5 || w.SumVolume() != 5 || w.at(0).PriceFloat(w.priceScale) != 103 {
+		t.Fatalf("size %d volume %v head %+v", w.size, w.SumVolume(), w.at(0))
+	}
+	if c := w.Counters(); c.Overflows != 0 || c.Evictions != 3 {
+		t.Fatalf("counters: %+v", c)
+	}
+	if hi, lo, _ := w.HighLow(); hi != 107 || lo != 103 {
+		t.Fatalf("high/low %v/%v", hi, lo)
+	}
+	if cfg := w.Config(); cfg.EvictionPolicy != "count" || cfg.MaxPoints != 5 {
+		t.Fatalf("config: %+v", cfg)
+	}
+}
+
+func TestEvictHybrid(t *testing.T) {
+	w := NewSlidingWindowHybrid(10*time.Second, 4, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 点密时按点数出窗
+	for i := 0; i < 6; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	if w.size != 4 {
+		t.Fatalf("count bound: size %d", w.size)
+	}
+
+	// 点疏时按时间出窗
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(14*time.Second))
+	if w.size != 2 {
+		t.Fatalf("time bound: size %d", w.size)
+	}
+	if issues := w.Check(); len(issues) > 0 {
+		t.Fatalf("check: %v", issues)
+	}
+}
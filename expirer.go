@@ -0,0 +1,115 @@
+package sliding_window
+
+import (
+	"context"
+	"time"
+)
+
+// Expirable 可以按外部时间主动过期的对象（SlidingWindow、Manager）
+type Expirable interface {
+	ExpireNow(now time.Time) int
+}
+
+// ExpireNow 以 now 为当前时间移出 Ts <= now - duration 的点并刷新缓存统计（写锁），返回移出的点数。
+// 正常情况下只有 Add 会触发清理，行情静默时 SumVolume、买卖量和 Snapshot 会一直停留在旧数据上；
+// 定期调用 ExpireNow（或挂载 Expirer）让指标随时间衰减。now 应与成交时间同一时钟，按点数出窗时无效果
+func (w *SlidingWindow) ExpireNow(now time.Time) int {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	threshold := w.thresholdUnlocked(now)
+	if w.size == 0 || threshold.IsZero() || w.atUnlocked(0).Ts.After(threshold) {
+		return 0
+	}
+
+	before := w.size
+	w.finishAddUnlocked(threshold)
+	return before - w.size
+}
+
+// ExpireNow 对全部窗口执行 ExpireNow，返回移出的点数之和
+func (m *Manager) ExpireNow(now time.Time) int {
+	var n int
+	for _, t := range m.resolve(nil) {
+		n += t.w.ExpireNow(now)
+	}
+	return n
+}
+
+// expireByOwnClock 按自身时钟过期的目标（SlidingWindow、Manager 下的每个窗口）
+type expireByOwnClock interface {
+	expireOwnClock() int
+}
+
+// expireOwnClock 以窗口时钟的当前时间执行 ExpireNow
+func (w *SlidingWindow) expireOwnClock() int { return w.ExpireNow(w.now()) }
+
+// expireOwnClock 每个窗口以各自时钟的当前时间执行 ExpireNow，返回移出的点数之和
+func (m *Manager) expireOwnClock() int {
+	var n int
+	for _, t := range m.resolve(nil) {
+		n += t.w.expireOwnClock()
+	}
+	return n
+}
+
+// Expirer 后台过期组件：每隔 interval 对目标执行 ExpireNow。
+// 默认每个窗口使用自己的时钟（见 SlidingWindow.SetClock），其他 Expirable 使用系统时钟
+type Expirer struct {
+	interval time.Duration
+	targets  []Expirable
+	clock    Clock // nil 时按目标各自的时钟
+	run      runner
+}
+
+// NewExpirer interval <= 0 时取 1s
+func NewExpirer(interval time.Duration, targets ...Expirable) *Expirer {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Expirer{interval: interval, targets: targets}
+}
+
+// SetClock 让所有目标统一使用 c 的当前时间过期（Start 之前调用），nil 恢复按目标各自的时钟。
+// 触发间隔仍按真实时间，回测中可以直接调用 ExpireNow
+func (e *Expirer) SetClock(c Clock) {
+	e.clock = c
+}
+
+// Start 在后台运行 Run(ctx)
+func (e *Expirer) Start(ctx context.Context) error { return e.run.start(ctx, e.Run) }
+
+// Close 停止过期协程并等待退出
+func (e *Expirer) Close() error { return e.run.close() }
+
+// Run 阻塞运行直到 ctx 结束
+func (e *Expirer) Run(ctx context.Context) error {
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			e.tick()
+		}
+	}
+}
+
+func (e *Expirer) tick() {
+	if e.clock != nil {
+		now := e.clock.Now()
+		for _, t := range e.targets {
+			t.ExpireNow(now)
+		}
+		return
+	}
+	for _, t := range e.targets {
+		if c, ok := t.(expireByOwnClock); ok {
+			c.expireOwnClock()
+		} else {
+			t.ExpireNow(time.Now())
+		}
+	}
+}
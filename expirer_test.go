@@ -0,0 +1,50 @@
+package sliding_window
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExpireNow(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for s := 0; s < 5; s++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0.Add(time.Duration(s)*time.Second))
+	}
+	v := w.Version()
+
+	if n := w.ExpireNow(t0.Add(5 * time.Second)); n != 0 || w.Version() != v {
+		t.Fatalf("nothing should expire: n=%d", n)
+	}
+	if n := w.ExpireNow(t0.Add(12 * time.Second)); n != 3 {
+		t.Fatalf("expired %d, want 3", n)
+	}
+	if got := w.SumVolume(); got != 2 {
+		t.Fatalf("sum volume = %v, want 2", got)
+	}
+	w.ExpireNow(t0.Add(time.Minute))
+	if got, imb := w.SumVolume(), w.Imbalance(); got != 0 || imb != 0 {
+		t.Fatalf("stale after quiet period: vol=%v imb=%v", got, imb)
+	}
+}
+
+func TestExpirer(t *testing.T) {
+	m := NewWindowManager(time.Second, 64, 0.1)
+	m.AddWindowPoint("BTC", SideBuy, 100, 1, time.Now().Add(-time.Minute))
+
+	e := NewExpirer(time.Millisecond, m)
+	if err := e.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	w, _ := m.Get("BTC")
+	deadline := time.Now().Add(time.Second)
+	for w.SumVolume() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expirer did not trim the quiet window")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,60 @@
+package sliding_window
+
+import "time"
+
+// ExportOptions 导出窗口时的脱敏选项，零值为原样深拷贝
+type ExportOptions struct {
+	RoundTs  time.Duration // >0 时成交时间向下取整到该粒度（不会打乱顺序）
+	Rebase   bool          // 时间整体平移，使第一笔成交位于 Unix 零点
+	DropSide bool          // 丢弃买卖方向
+}
+
+// WindowExport 导出的窗口：点是独立的深拷贝，可以直接 JSON 序列化后附到问题报告里
+type WindowExport struct {
+	DurationMs  int64         `json:"duration_ms"`
+	Capacity    int           `json:"capacity"`
+	PriceScale  int64         `json:"price_scale"`
+	VolumeScale int64         `json:"volume_scale"`
+	Points      []WindowPoint `json:"points"`
+}
+
+// Export 按 opts 拷贝窗口内的点（读锁）
+func (w *SlidingWindow) Export(opts ExportOptions) WindowExport {
+	w.mu.RLock()
+	out := WindowExport{
+		DurationMs:  w.duration.Milliseconds(),
+		Capacity:    w.capacityUnlocked(),
+		PriceScale:  int64(w.priceScale),
+		VolumeScale: int64(w.volumeScale),
+		Points:      make([]WindowPoint, w.size),
+	}
+	for i := range out.Points {
+		out.Points[i] = w.atUnlocked(i)
+	}
+	w.mu.RUnlock()
+
+	if len(out.Points) == 0 {
+		return out
+	}
+
+	var shift time.Duration
+	if opts.Rebase {
+		shift = -time.Duration(out.Points[0].Ts.UnixNano())
+	}
+	for i := range out.Points {
+		p := &out.Points[i]
+		p.Ts = exportTs(p.Ts, shift, opts.RoundTs)
+		if opts.DropSide {
+			p.Side = SideUnknown
+		}
+	}
+	return out
+}
+
+func exportTs(ts time.Time, shift, round time.Duration) time.Time {
+	ts = ts.Add(shift).UTC()
+	if round > 0 {
+		ts = ts.Truncate(round)
+	}
+	return ts
+}
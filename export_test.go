@@ -0,0 +1,33 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 16, 0.1)
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 123e6, time.UTC)
+	w.Add(
+		WindowPoint{Ts: t0, Price: NewQtyLoz(100, w.priceScale), Volume: NewQtyLoz(1, w.volumeScale), Side: SideBuy},
+		WindowPoint{Ts: t0.Add(1500 * time.Millisecond), Price: NewQtyLoz(101, w.priceScale), Volume: NewQtyLoz(2, w.volumeScale), Side: SideSell},
+	)
+
+	raw := w.Export(ExportOptions{})
+	if len(raw.Points) != 2 || !raw.Points[0].Ts.Equal(t0) || raw.Points[0].Side != SideBuy {
+		t.Fatalf("raw: %+v", raw)
+	}
+	raw.Points[0].Price = 0
+	if w.atUnlocked(0).Price == 0 {
+		t.Fatal("export shares storage with the window")
+	}
+
+	anon := w.Export(ExportOptions{RoundTs: time.Second, Rebase: true, DropSide: true})
+	p0, p1 := anon.Points[0], anon.Points[1]
+	if !p0.Ts.Equal(time.Unix(0, 0)) || !p1.Ts.Equal(time.Unix(1, 0)) {
+		t.Fatalf("ts: %v %v", p0.Ts, p1.Ts)
+	}
+	if p0.Side != SideUnknown || p1.Volume != NewQtyLoz(2, w.volumeScale) {
+		t.Fatalf("anon: %+v", anon.Points)
+	}
+}
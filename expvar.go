@@ -0,0 +1,163 @@
+package sliding_window
+
+import (
+	"encoding/json"
+	"expvar"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// windowCounters 内部事件计数，只增不减
+type windowCounters struct {
+	overflows       atomic.Int64 // 环满覆盖最旧点
+	rejects         atomic.Int64 // 到达时已经过期而被丢弃的点
+	recomputes      atomic.Int64 // high/low 全量重算次数
+	evictions       atomic.Int64 // 出窗点数（过期 + 覆盖）
+	slowAdds        atomic.Int64 // 超过处理时限的 add 次数
+	merged          atomic.Int64 // 因写入限速被合并的点数
+	hookPanics      atomic.Int64 // 用户回调 panic 次数
+	lateDrops       atomic.Int64 // 超过乱序缓冲容忍时长而被丢弃的点
+	duplicates      atomic.Int64 // TradeID 重复而被忽略的点
+	negativeRejects atomic.Int64 // 负成交量被丢弃的点（含找不到原成交的更正）
+	corrections     atomic.Int64 // 按 TradeID 生效的成交更正
+}
+
+type Counters struct {
+	Overflows       int64 `json:"overflows"`
+	Rejects         int64 `json:"rejects"`
+	Recomputes      int64 `json:"recomputes"`
+	Evictions       int64 `json:"evictions"`
+	SlowAdds        int64 `json:"slow_adds"`
+	Merged          int64 `json:"merged"`
+	HookPanics      int64 `json:"hook_panics"`
+	LateDrops       int64 `json:"late_drops"`
+	Duplicates      int64 `json:"duplicates"`
+	NegativeRejects int64 `json:"negative_rejects"`
+	Corrections     int64 `json:"corrections"`
+}
+
+// Counters 内部事件计数快照（无锁）
+func (w *SlidingWindow) Counters() Counters {
+	return Counters{
+		Overflows:       w.counters.overflows.Load(),
+		Rejects:         w.counters.rejects.Load(),
+		Recomputes:      w.counters.recomputes.Load(),
+		Evictions:       w.counters.evictions.Load(),
+		SlowAdds:        w.counters.slowAdds.Load(),
+		Merged:          w.counters.merged.Load(),
+		HookPanics:      w.counters.hookPanics.Load(),
+		LateDrops:       w.counters.lateDrops.Load(),
+		Duplicates:      w.counters.duplicates.Load(),
+		NegativeRejects: w.counters.negativeRejects.Load(),
+		Corrections:     w.counters.corrections.Load(),
+	}
+}
+
+// debugVars expvar 输出的窗口核心统计
+func (w *SlidingWindow) debugVars() map[string]any {
+	w.mu.RLock()
+	size := w.size
+	capacity := w.capacityUnlocked()
+	sumVolume := w.sumVolume.Float(w.volumeScale)
+	w.mu.RUnlock()
+
+	vars := map[string]any{
+		"size":          size,
+		"capacity":      capacity,
+		"duration_ms":   w.duration.Milliseconds(),
+		"sum_volume":    sumVolume,
+		"n_trades":      w.nTrades.Load(),
+		"highest_price": QtyLoz(w.HighestPrice.Load()).Float(w.priceScale),
+		"lowest_price":  QtyLoz(w.LowestPrice.Load()).Float(w.priceScale),
+		"latest_price":  QtyLoz(w.LatestPrice.Load()).Float(w.priceScale),
+		"imbalance":     w.Imbalance(),
+		"version":       w.version.Load(),
+		"counters":      w.Counters(),
+	}
+	if len(w.tags) > 0 {
+		vars["tags"] = w.tags
+	}
+	if segs, spare, ok := w.segmentStats(); ok {
+		vars["segments"] = segs
+		vars["spare_segments"] = spare
+	}
+	return vars
+}
+
+var (
+	expvarOnce sync.Once
+	expvarRoot *expvar.Map
+)
+
+// expvarWindows 包级 expvar 根节点 "sliding_window"，/debug/vars 下可见
+func expvarWindows() *expvar.Map {
+	expvarOnce.Do(func() {
+		expvarRoot = expvar.NewMap("sliding_window")
+	})
+	return expvarRoot
+}
+
+// windowVar 单个窗口的 expvar 条目，记住所属窗口，撤销时只删除仍属于该窗口的条目
+type windowVar struct{ w *SlidingWindow }
+
+func (v windowVar) String() string {
+	b, _ := json.Marshal(v.w.debugVars())
+	return string(b)
+}
+
+// PublishExpvar 把窗口的核心统计和内部计数以 name 发布到 expvar（重复发布会覆盖）。
+// expvar 是进程级的，窗口停用前应调用 UnpublishExpvar，否则会一直被引用；由 Manager 管理的窗口在 Remove / 替换时自动撤销
+func (w *SlidingWindow) PublishExpvar(name string) {
+	expvarWindows().Set(name, windowVar{w: w})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !slices.Contains(w.expvarNames, name) {
+		w.expvarNames = append(w.expvarNames, name)
+	}
+}
+
+// UnpublishExpvar 撤销本窗口以 name 发布的 expvar 条目；该名字已被其他窗口覆盖时不删除
+func (w *SlidingWindow) UnpublishExpvar(name string) {
+	w.mu.Lock()
+	w.expvarNames = slices.DeleteFunc(w.expvarNames, func(n string) bool { return n == name })
+	w.mu.Unlock()
+
+	w.deleteExpvar(name)
+}
+
+// unpublishExpvarAll 撤销本窗口发布过的全部 expvar 条目
+func (w *SlidingWindow) unpublishExpvarAll() {
+	w.mu.Lock()
+	names := w.expvarNames
+	w.expvarNames = nil
+	w.mu.Unlock()
+
+	for _, name := range names {
+		w.deleteExpvar(name)
+	}
+}
+
+func (w *SlidingWindow) deleteExpvar(name string) {
+	root := expvarWindows()
+	if v, ok := root.Get(name).(windowVar); ok && v.w == w {
+		root.Delete(name)
+	}
+}
+
+// PublishExpvar 以 name 发布 manager 下所有窗口的统计，按 symbol 展开，新增的窗口自动可见、移除的窗口随之消失
+func (m *Manager) PublishExpvar(name string) {
+	expvarWindows().Set(name, expvar.Func(func() any {
+		out := make(map[string]any)
+		for _, t := range m.resolve(nil) {
+			out[t.symbol] = t.w.debugVars()
+		}
+		return out
+	}))
+}
+
+// UnpublishExpvar 撤销 manager 以 name 发布的 expvar 条目
+func (m *Manager) UnpublishExpvar(name string) {
+	expvarWindows().Delete(name)
+}
@@ -0,0 +1,115 @@
+package sliding_window
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+)
+
+// readExpvar 读取 "sliding_window" 根节点下 name 的 JSON 输出
+func readExpvar(t *testing.T, name string) map[string]any {
+	t.Helper()
+	v := expvarWindows().Get(name)
+	if v == nil {
+		t.Fatalf("%s not published", name)
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(v.String()), &out); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return out
+}
+
+func TestPublishExpvar(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	w.AddWindowPoint(SideBuy, 100, 2, t0)
+	w.AddWindowPoint(SideSell, 102, 1, t0.Add(time.Second))
+	w.PublishExpvar("expvar_test_window")
+
+	if expvar.Get("sliding_window") == nil {
+		t.Fatal("root map missing from /debug/vars")
+	}
+	vars := readExpvar(t, "expvar_test_window")
+	if vars["size"] != 2.0 || vars["sum_volume"] != 3.0 || vars["duration_ms"] != 60000.0 {
+		t.Fatalf("vars: %v", vars)
+	}
+	if vars["highest_price"] != 102.0 || vars["latest_price"] != 102.0 {
+		t.Fatalf("prices: %v", vars)
+	}
+	counters, ok := vars["counters"].(map[string]any)
+	if !ok || counters["evictions"] != 0.0 {
+		t.Fatalf("counters: %v", vars["counters"])
+	}
+
+	// 函数型变量：之后的写入在下次读取时可见
+	w.AddWindowPoint(SideBuy, 101, 1, t0.Add(2*time.Second))
+	if vars := readExpvar(t, "expvar_test_window"); vars["size"] != 3.0 {
+		t.Fatalf("size after add = %v", vars["size"])
+	}
+}
+
+func TestManager_PublishExpvar(t *testing.T) {
+	m := NewWindowManager(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	m.AddWindowPoint("BTC", SideBuy, 100, 1, t0)
+	m.PublishExpvar("expvar_test_manager")
+
+	// 发布之后新增的 symbol 自动可见
+	m.AddWindowPoint("ETH", SideSell, 10, 2, t0)
+	vars := readExpvar(t, "expvar_test_manager")
+	if len(vars) != 2 {
+		t.Fatalf("symbols: %v", vars)
+	}
+	eth, ok := vars["ETH"].(map[string]any)
+	if !ok || eth["sum_volume"] != 2.0 {
+		t.Fatalf("ETH: %v", vars["ETH"])
+	}
+}
+
+func TestUnpublishExpvar(t *testing.T) {
+	root := expvarWindows()
+	a := NewSlidingWindow(time.Minute, 64, 0.1)
+	b := NewSlidingWindow(time.Minute, 64, 0.1)
+	a.PublishExpvar("expvar_test_unpublish")
+	a.UnpublishExpvar("expvar_test_unpublish")
+	if root.Get("expvar_test_unpublish") != nil {
+		t.Fatal("entry should be removed")
+	}
+
+	// 同名被其他窗口覆盖后，旧窗口撤销不影响新窗口的条目
+	a.PublishExpvar("expvar_test_shared")
+	b.PublishExpvar("expvar_test_shared")
+	a.unpublishExpvarAll()
+	if v, ok := root.Get("expvar_test_shared").(windowVar); !ok || v.w != b {
+		t.Fatal("overwritten entry must stay with the new window")
+	}
+	root.Delete("expvar_test_shared")
+}
+
+func TestManager_RemoveUnpublishesExpvar(t *testing.T) {
+	root := expvarWindows()
+	m := NewWindowManager(time.Minute, 64, 0.1)
+	old := m.GetOrCreate("BTC")
+	old.PublishExpvar("expvar_test_btc")
+
+	// 替换窗口：旧窗口的条目撤销
+	m.Set("BTC", NewSlidingWindow(time.Minute, 64, 0.1))
+	if root.Get("expvar_test_btc") != nil {
+		t.Fatal("replaced window should be unpublished")
+	}
+
+	w := m.GetOrCreate("ETH")
+	w.PublishExpvar("expvar_test_eth")
+	m.Remove("ETH")
+	if root.Get("expvar_test_eth") != nil {
+		t.Fatal("removed window should be unpublished")
+	}
+
+	m.PublishExpvar("expvar_test_mgr")
+	m.UnpublishExpvar("expvar_test_mgr")
+	if root.Get("expvar_test_mgr") != nil {
+		t.Fatal("manager entry should be removed")
+	}
+}
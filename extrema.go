@@ -0,0 +1,98 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// Extremum 价格路径上的一个摆动高点或低点
+type Extremum struct {
+	Ts    time.Time `json:"ts"`
+	Price float64   `json:"price"`
+	High  bool      `json:"high"` // true 为摆动高点，false 为摆动低点
+	// Prominence 显著性（价格单位）：进入与离开该点的两段摆动幅度中较小者
+	Prominence float64 `json:"prominence"`
+}
+
+// Extrema 用 zigzag 找出窗口内的摆动高低点（读锁），按时间升序，高低交替。
+// 价格从候选极值反向运动至少 minProminence（价格单位）才确认该极值，
+// 噪声级别的小波动不会产生拐点。可用于更高高点、双顶等结构判断。
+func (w *SlidingWindow) Extrema(minProminence float64) []Extremum {
+	if minProminence <= 0 {
+		return nil
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 3 {
+		return nil
+	}
+
+	first := w.atUnlocked(0)
+	startPx := first.Price.Float(w.priceScale)
+
+	var pivots []Extremum
+	// dir: 0 尚未确定方向，1 正在寻找高点，-1 正在寻找低点
+	dir := 0
+	cand := Extremum{Ts: first.Ts, Price: startPx}
+	hi, lo := cand, cand
+
+	for i := 1; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		px := pt.Price.Float(w.priceScale)
+		cur := Extremum{Ts: pt.Ts, Price: px}
+
+		switch dir {
+		case 0:
+			if px > hi.Price {
+				hi = cur
+			}
+			if px < lo.Price {
+				lo = cur
+			}
+			if hi.Price-lo.Price >= minProminence {
+				// 先出现的那个极值作为第一个拐点
+				if hi.Ts.Before(lo.Ts) {
+					hi.High = true
+					pivots = append(pivots, hi)
+					dir, cand = -1, lo
+				} else {
+					pivots = append(pivots, lo)
+					dir, cand = 1, hi
+				}
+			}
+		case 1:
+			if px > cand.Price {
+				cand = cur
+			} else if cand.Price-px >= minProminence {
+				cand.High = true
+				pivots = append(pivots, cand)
+				dir, cand = -1, cur
+			}
+		case -1:
+			if px < cand.Price {
+				cand = cur
+			} else if px-cand.Price >= minProminence {
+				pivots = append(pivots, cand)
+				dir, cand = 1, cur
+			}
+		}
+	}
+
+	// 显著性：左侧摆动取到前一个拐点（首个拐点取到窗口起点），
+	// 右侧摆动取到下一个拐点（最后一个拐点取到当前未确认的候选极值）
+	for i := range pivots {
+		left := startPx
+		if i > 0 {
+			left = pivots[i-1].Price
+		}
+		right := cand.Price
+		if i+1 < len(pivots) {
+			right = pivots[i+1].Price
+		}
+		p := pivots[i].Price
+		pivots[i].Prominence = math.Min(math.Abs(p-left), math.Abs(p-right))
+	}
+	return pivots
+}
@@ -0,0 +1,29 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtrema_DoubleTop(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	path := []float64{100, 101, 103, 105, 104, 102, 100, 101, 103, 105, 104.5, 102, 100}
+	for i, px := range path {
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*time.Second))
+	}
+
+	ex := w.Extrema(2)
+	if len(ex) != 4 {
+		t.Fatalf("got %d extrema, want 4: %+v", len(ex), ex)
+	}
+	if !ex[1].High || ex[1].Price != 105 || !ex[3].High || ex[3].Price != 105 || ex[2].High || ex[2].Price != 100 {
+		t.Fatalf("unexpected pivots %+v", ex)
+	}
+	if ex[1].Prominence != 5 {
+		t.Fatalf("prominence = %v, want 5", ex[1].Prominence)
+	}
+	if got := w.Extrema(10); len(got) != 0 {
+		t.Fatalf("large threshold should yield no pivots, got %+v", got)
+	}
+}
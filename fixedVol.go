@@ -0,0 +1,67 @@
+package sliding_window
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fixedReturnScale 定点收益率的缩放（12 位小数），平方后累加在 128 位整数里
+const fixedReturnScale = 1_000_000_000_000
+
+// RealizedVolFixed 定点版 realized vol（读锁），用于审计复现。
+//
+// 每段收益在 tick 整数域里用对称近似 r ≈ 2(p_i − p_{i−1}) / (p_i + p_{i−1}) 计算
+// （与 log return 的误差为 O(r³)），以 1e-12 定点表示，平方和用 128 位整数精确累加，
+// 只在最后做一次转换和开方（IEEE 754 的开方是正确舍入的），
+// 因此结果在不同架构、不同编译器下逐位一致，与 RealizedVol 在小收益下数值接近。
+//
+//metric:name=realized_vol_fixed unit=return cost=O(n) min_points=2
+func (w *SlidingWindow) RealizedVolFixed() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 2 {
+		return 0, false
+	}
+
+	var hi, lo uint64 // Σ r_fp²
+	prev := w.atUnlocked(0).Price.Int64()
+	n := 0
+	for i := 1; i < w.size; i++ {
+		cur := w.atUnlocked(i).Price.Int64()
+		if cur <= 0 || prev <= 0 {
+			prev = cur
+			continue
+		}
+		r := fixedReturn(prev, cur)
+		sqHi, sqLo := bits.Mul64(r, r)
+		var carry uint64
+		lo, carry = bits.Add64(lo, sqLo, 0)
+		hi += sqHi + carry
+		prev = cur
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+
+	sumsq := (float64(hi)*(1<<64) + float64(lo)) / (float64(fixedReturnScale) * float64(fixedReturnScale))
+	return math.Sqrt(sumsq), true
+}
+
+// fixedReturn |2(cur − prev) / (cur + prev)| × 1e12，要求 prev、cur 均为正
+func fixedReturn(prev, cur int64) uint64 {
+	d := cur - prev
+	if d < 0 {
+		d = -d
+	}
+	den := uint64(cur) + uint64(prev)
+	// 2·|d|·1e12 可能超过 64 位，用 128 位乘除；|d| < den，商不超过 2e12
+	h, l := bits.Mul64(uint64(d), 2*fixedReturnScale)
+	q, _ := bits.Div64(h, l, den)
+	return q
+}
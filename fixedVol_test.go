@@ -0,0 +1,25 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRealizedVolFixed_MatchesFloat(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 500; i++ {
+		px := 30000 + 50*math.Sin(float64(i)*0.3) + float64(i%7)
+		w.AddWindowPoint(SideBuy, px, 1, base.Add(time.Duration(i)*10*time.Millisecond))
+	}
+
+	fixed, ok := w.RealizedVolFixed()
+	if !ok {
+		t.Fatal("not ok")
+	}
+	float, _ := w.RealizedVol()
+	if math.Abs(fixed-float) > 1e-6*float {
+		t.Fatalf("fixed %v vs float %v", fixed, float)
+	}
+}
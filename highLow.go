@@ -1,6 +1,15 @@
 package sliding_window
 
+import "time"
+
+// HighLow 窗口内最高价 / 最低价
+//
+//metric:name=high_low unit=price cost=O(n) min_points=1
 func (w *SlidingWindow) HighLow() (high, low float64, ok bool) {
+	if !w.IsReady() {
+		return 0, 0, false
+	}
+
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	// 返回
@@ -28,3 +37,55 @@ func (w *SlidingWindow) highLowUnlocked() (float64, float64, bool) {
 
 	return high.Float(w.priceScale), low.Float(w.priceScale), true
 }
+
+// HighLowDetail 带时间的窗口高低点。同价多次出现时取最早的一次（即该价位被创出的时间），
+// Age 相对窗口最新一笔的时间
+type HighLowDetail struct {
+	High    float64       `json:"high"`
+	Low     float64       `json:"low"`
+	HighTs  time.Time     `json:"high_ts"`
+	LowTs   time.Time     `json:"low_ts"`
+	HighAge time.Duration `json:"high_age"`
+	LowAge  time.Duration `json:"low_age"`
+}
+
+// HighLowDetail 窗口最高价 / 最低价及其创出时间和距今时长（读锁）。
+// 55 秒前创出的高点和 1 秒前刚创出的高点对应完全不同的结构
+//
+//metric:name=high_low_detail unit=price cost=O(n) min_points=1
+func (w *SlidingWindow) HighLowDetail() (HighLowDetail, bool) {
+	if !w.IsReady() {
+		return HighLowDetail{}, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.highLowDetailUnlocked()
+}
+
+func (w *SlidingWindow) highLowDetailUnlocked() (HighLowDetail, bool) {
+	var d HighLowDetail
+	if w.size == 0 {
+		return d, false
+	}
+
+	first := w.atUnlocked(0)
+	high, low := first.Price, first.Price
+	d.HighTs, d.LowTs = first.Ts, first.Ts
+
+	for i := 1; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		if pt.Price > high {
+			high, d.HighTs = pt.Price, pt.Ts
+		}
+		if pt.Price < low {
+			low, d.LowTs = pt.Price, pt.Ts
+		}
+	}
+
+	now := w.lastUnlocked().Ts
+	d.High, d.Low = high.Float(w.priceScale), low.Float(w.priceScale)
+	d.HighAge, d.LowAge = now.Sub(d.HighTs), now.Sub(d.LowTs)
+	return d, true
+}
@@ -0,0 +1,31 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHighLowDetail(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if _, ok := w.HighLowDetail(); ok {
+		t.Fatal("empty window should not be ok")
+	}
+
+	t0 := time.Unix(1700000000, 0)
+	prices := []float64{100, 103, 101, 99, 103, 102}
+	for i, px := range prices {
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*10*time.Second))
+	}
+
+	d, ok := w.HighLowDetail()
+	if !ok {
+		t.Fatal("not ok")
+	}
+	// 103 出现两次，取最早创出的时间
+	if d.High != 103 || !d.HighTs.Equal(t0.Add(10*time.Second)) || d.HighAge != 40*time.Second {
+		t.Fatalf("high: %+v", d)
+	}
+	if d.Low != 99 || d.LowAge != 20*time.Second {
+		t.Fatalf("low: %+v", d)
+	}
+}
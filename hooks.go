@@ -0,0 +1,128 @@
+package sliding_window
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// 用户回调的名字，用于 HookPanic 报告和 HookStatus 查询
+const (
+	HookLargeTrade = "large_trade" // SetLargeTradeHook
+	HookArchiver   = "archiver"    // Archiver.OnBarClose
+	HookEvict      = "evict"       // SetEvictHook
+)
+
+// HookPanic 一次用户回调 panic 的报告
+type HookPanic struct {
+	Hook     string
+	Value    any               // recover() 的返回值
+	Stack    []byte            // panic 时的调用栈
+	Failures int               // 该回调累计 panic 次数
+	Disabled bool              // 是否因达到上限被停用
+	Tags     map[string]string // 窗口标签
+}
+
+func (p HookPanic) Error() string {
+	return fmt.Sprintf("sliding_window: hook %s panicked: %v", p.Hook, p.Value)
+}
+
+// hookGuard 用户回调的 panic 隔离：recover 后记账，攒到锁外再交给处理函数
+type hookGuard struct {
+	mu       sync.Mutex
+	onPanic  func(HookPanic)
+	limit    int
+	failures map[string]int
+	disabled map[string]bool
+	pending  []HookPanic
+}
+
+// SetHookPanicHandler 设置用户回调 panic 的处理函数（可为 nil）和停用阈值：
+// 同一回调累计 panic 达到 disableAfter 次后不再调用（<= 0 表示永不停用）。
+//
+// 所有用户回调（大单提醒、Archiver）都在 recover 保护下执行，panic 不会让写锁停在
+// 半更新状态，也不会终止调用 Add 的协程。fn 在写锁释放后调用，可以读取窗口
+func (w *SlidingWindow) SetHookPanicHandler(fn func(HookPanic), disableAfter int) {
+	g := &w.hooks
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.onPanic = fn
+	g.limit = disableAfter
+}
+
+// HookStatus 某个回调累计 panic 次数及是否已停用
+func (w *SlidingWindow) HookStatus(hook string) (failures int, disabled bool) {
+	g := &w.hooks
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.failures[hook], g.disabled[hook]
+}
+
+// ResetHook 清零某个回调的 panic 计数并重新启用
+func (w *SlidingWindow) ResetHook(hook string) {
+	g := &w.hooks
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.failures, hook)
+	delete(g.disabled, hook)
+}
+
+// callHook 在 recover 保护下执行回调，已停用的回调直接跳过；返回是否正常执行完
+func (w *SlidingWindow) callHook(hook string, fn func()) (ok bool) {
+	g := &w.hooks
+	g.mu.Lock()
+	off := g.disabled[hook]
+	g.mu.Unlock()
+	if off {
+		return false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			w.counters.hookPanics.Add(1)
+			g.record(hook, r, debug.Stack(), w.tags)
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+func (g *hookGuard) record(hook string, v any, stack []byte, tags map[string]string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.failures == nil {
+		g.failures = make(map[string]int)
+		g.disabled = make(map[string]bool)
+	}
+	g.failures[hook]++
+	n := g.failures[hook]
+	off := g.limit > 0 && n >= g.limit
+	if off {
+		g.disabled[hook] = true
+	}
+	if g.onPanic != nil {
+		g.pending = append(g.pending, HookPanic{Hook: hook, Value: v, Stack: stack, Failures: n, Disabled: off, Tags: tags})
+	}
+}
+
+// dispatchHookPanics 把攒下的 panic 报告交给处理函数（必须在窗口锁外调用）
+func (w *SlidingWindow) dispatchHookPanics() {
+	g := &w.hooks
+	g.mu.Lock()
+	reports, fn := g.pending, g.onPanic
+	g.pending = nil
+	g.mu.Unlock()
+
+	for _, r := range reports {
+		func() {
+			// 处理函数自身的 panic 直接丢弃，避免无限递归
+			defer func() { recover() }()
+			fn(r)
+		}()
+	}
+}
@@ -0,0 +1,44 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+type panicArchiver struct{}
+
+func (panicArchiver) OnBarClose(Candle) { panic("store down") }
+
+func TestHookPanicIsolation(t *testing.T) {
+	w := NewSlidingWindow(5*time.Second, 256, 0.1)
+	var reports []HookPanic
+	w.SetHookPanicHandler(func(p HookPanic) {
+		w.Snapshot() // 处理函数在锁外调用，可以读取窗口
+		reports = append(reports, p)
+	}, 2)
+	w.SetLargeTradeHook(0.5, func(LargeTrade) { panic("boom") })
+	w.SetArchiver(panicArchiver{}, time.Second)
+
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 100; i++ {
+		w.AddWindowPoint(SideBuy, 100, float64(1+i%7), t0.Add(time.Duration(i)*200*time.Millisecond))
+	}
+
+	for _, hook := range []string{HookLargeTrade, HookArchiver} {
+		n, off := w.HookStatus(hook)
+		if n != 2 || !off {
+			t.Fatalf("%s: failures=%d disabled=%v", hook, n, off)
+		}
+	}
+	if len(reports) != 4 || !reports[1].Disabled || len(reports[0].Stack) == 0 {
+		t.Fatalf("reports: %d %+v", len(reports), reports)
+	}
+	if w.Counters().HookPanics != 4 || !w.Healthy() {
+		t.Fatalf("counters %+v issues %v", w.Counters(), w.Check())
+	}
+
+	w.ResetHook(HookLargeTrade)
+	if _, off := w.HookStatus(HookLargeTrade); off {
+		t.Fatal("reset should re-enable the hook")
+	}
+}
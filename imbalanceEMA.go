@@ -0,0 +1,63 @@
+package sliding_window
+
+// maxImbalanceEMAs 同时维护的 ImbalanceEMA alpha 个数上限，避免调用方传入连续变化的 alpha 拖慢 Add
+const maxImbalanceEMAs = 8
+
+// imbalanceEMA 一个已注册 alpha 的平滑失衡
+type imbalanceEMA struct {
+	alpha float64
+	ema   EMA
+}
+
+// ImbalanceEMA 按 alpha 做 EMA 平滑的成交量失衡。
+// 首次使用某个 alpha 时注册（写锁）并以当前失衡为初值，之后每次 Add 结束时自动更新，
+// 所有消费者对同一 alpha 读到的是同一条平滑序列。注册超过 8 个 alpha 时返回 false。
+func (w *SlidingWindow) ImbalanceEMA(alpha float64) (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	if !w.tracked.Has(TrackImbalanceEMA) {
+		return 0, false
+	}
+	w.mu.RLock()
+	for i := range w.imbEMAs {
+		if w.imbEMAs[i].alpha == alpha {
+			v, ok := w.imbEMAs[i].ema.Get()
+			w.mu.RUnlock()
+			return v, ok
+		}
+	}
+	w.mu.RUnlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// double check
+	for i := range w.imbEMAs {
+		if w.imbEMAs[i].alpha == alpha {
+			return w.imbEMAs[i].ema.Get()
+		}
+	}
+	if len(w.imbEMAs) >= maxImbalanceEMAs {
+		return 0, false
+	}
+
+	e := imbalanceEMA{alpha: alpha, ema: *NewEMA(alpha)}
+	if w.size > 0 {
+		e.ema.Update(imbalanceOf(w.buyVol.Load(), w.sellVol.Load()))
+	}
+	w.imbEMAs = append(w.imbEMAs, e)
+	return e.ema.Get()
+}
+
+// updateImbalanceEMAsUnlocked 每次 add 结束时用最新失衡更新全部已注册的 EMA（要求持有写锁）
+func (w *SlidingWindow) updateImbalanceEMAsUnlocked() {
+	if len(w.imbEMAs) == 0 || w.size == 0 {
+		return
+	}
+	imb := imbalanceOf(w.buyVol.Load(), w.sellVol.Load())
+	for i := range w.imbEMAs {
+		w.imbEMAs[i].ema.Update(imb)
+	}
+}
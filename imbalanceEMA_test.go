@@ -0,0 +1,29 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestImbalanceEMA(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if _, ok := w.ImbalanceEMA(0.5); ok {
+		t.Fatal("empty window should not be ready")
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	w.AddWindowPoint(SideBuy, 100, 1, base)                   // imb = 1
+	w.AddWindowPoint(SideSell, 100, 1, base.Add(time.Second)) // imb = 0
+
+	// 第一次 Add 时 EMA 初始化为 1，第二次更新为 0.5*0 + 0.5*1
+	v, ok := w.ImbalanceEMA(0.5)
+	if !ok || math.Abs(v-0.5) > 1e-12 {
+		t.Fatalf("ImbalanceEMA(0.5) = %v (ok=%v), want 0.5", v, ok)
+	}
+
+	// 新注册的 alpha 以当前失衡为初值
+	if v, ok := w.ImbalanceEMA(0.1); !ok || v != 0 {
+		t.Fatalf("ImbalanceEMA(0.1) = %v (ok=%v), want 0", v, ok)
+	}
+}
@@ -0,0 +1,74 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// ImbalanceHorizons 一次遍历计算多个时间尺度的订单流不平衡 (buy-sell)/(buy+sell)（读锁）。
+// 从最新点向前做后缀累加，按 horizon 由短到长依次落值，结果顺序与入参一致；
+// 某个 horizon 内没有买卖量时对应值为 0。
+//
+//metric:name=imbalance_horizons unit=ratio cost=O(n) min_points=1
+func (w *SlidingWindow) ImbalanceHorizons(horizons ...time.Duration) []float64 {
+	out := make([]float64, len(horizons))
+	if len(horizons) == 0 {
+		return out
+	}
+
+	// 按 horizon 升序处理，order 保存原始下标
+	order := make([]int, len(horizons))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return horizons[order[a]] < horizons[order[b]] })
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size == 0 {
+		return out
+	}
+
+	newest := w.lastUnlocked().Ts
+	var buy, sell int64
+
+	k := 0
+	for i := w.size - 1; i >= 0 && k < len(order); i-- {
+		pt := w.atUnlocked(i)
+
+		// 当前点已经落在较短 horizon 之外：先把这些 horizon 结算掉
+		for k < len(order) && !pt.Ts.After(newest.Add(-horizons[order[k]])) {
+			out[order[k]] = imbalanceOf(buy, sell)
+			k++
+		}
+		if k == len(order) {
+			break
+		}
+
+		v := pt.Volume.Int64()
+		if v <= 0 {
+			continue
+		}
+		switch pt.Side {
+		case SideBuy:
+			buy += v
+		case SideSell:
+			sell += v
+		}
+	}
+
+	// 剩余 horizon 覆盖整个窗口
+	for ; k < len(order); k++ {
+		out[order[k]] = imbalanceOf(buy, sell)
+	}
+	return out
+}
+
+func imbalanceOf(buy, sell int64) float64 {
+	den := buy + sell
+	if den <= 0 {
+		return 0
+	}
+	return float64(buy-sell) / float64(den)
+}
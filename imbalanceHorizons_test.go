@@ -0,0 +1,29 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow_ImbalanceHorizons(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	// 前 50s 全是卖，最后 10s 全是买
+	for i := 0; i < 60; i++ {
+		side := SideSell
+		if i >= 50 {
+			side = SideBuy
+		}
+		w.AddWindowPoint(side, 100, 1, base.Add(time.Duration(i)*time.Second))
+	}
+
+	got := w.ImbalanceHorizons(time.Minute, 5*time.Second, 20*time.Second)
+	want := []float64{(10.0 - 50) / 60, 1, 0}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("horizon %d: got %.6f want %.6f", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package sliding_window
+
+import "time"
+
+// ingestLimit 单窗口写入限速：每个 interval 最多接收 n 个原始点，
+// 超出部分按方向合并成量价聚合点，在下一个 interval 开始（或 FlushIngest）时写入
+type ingestLimit struct {
+	n        int
+	interval time.Duration
+	bucket   time.Time // 当前 interval 起点
+	count    int
+	pending  [3]ingestAgg // 按 Side 下标
+}
+
+// ingestAgg 被合并的超额成交
+type ingestAgg struct {
+	n        int
+	last     time.Time
+	lastPx   int64   // 成交量全为 0 时的兜底价格（ticks）
+	vol      int64   // ticks
+	notional float64 // Σ price·vol（price 为 ticks 值）
+}
+
+// SetIngestLimit 每个 interval（按成交时间对齐）最多接收 n 个点（写锁），n <= 0 关闭。
+// 超出的点不丢弃：同方向的成交量合并为一个点（价格取其量加权均价，时间取最后一笔），
+// 在下一个 interval 的第一笔到来时写入，成交量与买卖量统计保持不变。
+// 用于防止个别异常活跃的 symbol 拖垮共享的写入协程。
+func (w *SlidingWindow) SetIngestLimit(n int, interval time.Duration) {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	w.flushIngestUnlocked()
+	if n <= 0 || interval <= 0 {
+		w.ingest = ingestLimit{}
+		return
+	}
+	w.ingest = ingestLimit{n: n, interval: interval}
+}
+
+// FlushIngest 立即写入限速合并中的聚合点（写锁）
+func (w *SlidingWindow) FlushIngest() {
+	w.lockWrite()
+	defer w.unlockWrite()
+	w.flushIngestUnlocked()
+}
+
+// admitUnlocked 限速判定：返回 true 表示 pt 直接写入；否则已并入聚合（要求持有写锁）。
+// 跨入新 interval 时先把上一 interval 的聚合点写入。
+func (w *SlidingWindow) admitUnlocked(pt WindowPoint, threshold time.Time) bool {
+	l := &w.ingest
+	if l.n <= 0 {
+		return true
+	}
+
+	b := pt.Ts.Truncate(l.interval)
+	if !b.Equal(l.bucket) {
+		w.flushIngestToUnlocked(threshold)
+		l.bucket = b
+		l.count = 0
+	}
+	if l.count < l.n {
+		l.count++
+		return true
+	}
+
+	side := pt.Side
+	if int(side) >= len(l.pending) {
+		side = SideUnknown
+	}
+	v := max(pt.Volume.Int64(), 0)
+	a := &l.pending[side]
+	a.n++
+	a.last = pt.Ts
+	a.lastPx = pt.Price.Int64()
+	a.vol += v
+	a.notional += float64(pt.Price.Int64()) * float64(v)
+	w.counters.merged.Add(1)
+	return false
+}
+
+// flushIngestUnlocked 写入全部聚合点并完成一次 add 的收尾（要求持有写锁）
+func (w *SlidingWindow) flushIngestUnlocked() {
+	if !w.ingestPendingUnlocked() {
+		return
+	}
+	var threshold time.Time
+	if w.size > 0 {
+		threshold = w.thresholdUnlocked(w.lastUnlocked().Ts)
+	}
+	w.flushIngestToUnlocked(threshold)
+	w.finishAddUnlocked(threshold)
+}
+
+func (w *SlidingWindow) ingestPendingUnlocked() bool {
+	for i := range w.ingest.pending {
+		if w.ingest.pending[i].n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// flushIngestToUnlocked 按最后一笔时间顺序写入聚合点（要求持有写锁）
+func (w *SlidingWindow) flushIngestToUnlocked(threshold time.Time) {
+	l := &w.ingest
+	for {
+		// 至多 3 个聚合点，每轮取最早的一个
+		k := -1
+		for side := range l.pending {
+			if l.pending[side].n > 0 && (k < 0 || l.pending[side].last.Before(l.pending[k].last)) {
+				k = side
+			}
+		}
+		if k < 0 {
+			return
+		}
+		a := l.pending[k]
+		l.pending[k] = ingestAgg{}
+
+		px := a.lastPx
+		if a.vol > 0 {
+			px = int64(a.notional/float64(a.vol) + 0.5)
+		}
+		w.insertUnlocked(WindowPoint{
+			Ts:     a.last,
+			Price:  QtyLoz(px),
+			Volume: QtyLoz(a.vol),
+			Side:   Side(k),
+		}, 0, threshold)
+	}
+}
@@ -0,0 +1,37 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestLimit_MergesExcess(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetIngestLimit(2, time.Second)
+	base := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 10; i++ {
+		side := SideBuy
+		if i%2 == 1 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i), 1, base.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	if n := w.CoreStats().Size; n != 2 {
+		t.Fatalf("size within first interval = %d, want 2", n)
+	}
+
+	// 进入下一秒：先写入两个聚合点（买、卖各一），再写入新点
+	w.AddWindowPoint(SideBuy, 120, 1, base.Add(time.Second))
+
+	cs := w.CoreStats()
+	if cs.Size != 5 || cs.Volume != 11 || cs.BuyVolume != 6 || cs.SellVolume != 5 {
+		t.Fatalf("unexpected core stats %+v", cs)
+	}
+	if got := w.Counters().Merged; got != 8 {
+		t.Fatalf("merged = %d, want 8", got)
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+}
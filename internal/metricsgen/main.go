@@ -0,0 +1,142 @@
+// metricsgen 扫描 sliding_window 包内带 //metric: 注解的方法，生成指标注册表 metrics_gen.go。
+//
+// 注解格式（写在方法的 doc comment 里）：
+//
+//	//metric:name=vwap unit=price cost=O(n) min_points=2 requires=
+//
+// 由 registry.go 中的 go:generate 指令调用。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const directive = "//metric:"
+
+type metric struct {
+	Name      string
+	Unit      string
+	Cost      string
+	MinPoints int
+	Requires  string
+	Method    string
+	Doc       string
+}
+
+func main() {
+	out := flag.String("out", "metrics_gen.go", "output file")
+	pkg := flag.String("pkg", "sliding_window", "package name")
+	flag.Parse()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	var metrics []metric
+	seen := map[string]string{}
+
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") || f == *out {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Doc == nil || !fd.Name.IsExported() {
+				continue
+			}
+			for _, c := range fd.Doc.List {
+				if !strings.HasPrefix(c.Text, directive) {
+					continue
+				}
+				m, err := parse(strings.TrimPrefix(c.Text, directive))
+				if err != nil {
+					log.Fatalf("%s: %s: %v", fset.Position(c.Pos()), fd.Name.Name, err)
+				}
+				if prev, dup := seen[m.Name]; dup {
+					log.Fatalf("%s: metric %q already declared by %s", fset.Position(c.Pos()), m.Name, prev)
+				}
+				seen[m.Name] = fd.Name.Name
+				m.Method = fd.Name.Name
+				m.Doc = firstLine(fd.Doc.Text(), fd.Name.Name)
+				metrics = append(metrics, m)
+			}
+		}
+	}
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by internal/metricsgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", *pkg)
+	fmt.Fprintf(&buf, "var metricRegistry = []MetricInfo{\n")
+	for _, m := range metrics {
+		fmt.Fprintf(&buf, "\t{Name: %q, Unit: %q, Cost: %q, MinPoints: %d, Requires: %q, Method: %q, Doc: %q},\n",
+			m.Name, m.Unit, m.Cost, m.MinPoints, m.Requires, m.Method, m.Doc)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse(s string) (metric, error) {
+	var m metric
+	for _, kv := range strings.Fields(s) {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return m, fmt.Errorf("bad field %q", kv)
+		}
+		switch k {
+		case "name":
+			m.Name = v
+		case "unit":
+			m.Unit = v
+		case "cost":
+			m.Cost = v
+		case "min_points":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return m, fmt.Errorf("min_points: %w", err)
+			}
+			m.MinPoints = n
+		case "requires":
+			m.Requires = v
+		default:
+			return m, fmt.Errorf("unknown field %q", k)
+		}
+	}
+	if m.Name == "" {
+		return m, fmt.Errorf("missing name")
+	}
+	return m, nil
+}
+
+// firstLine 取 doc 第一行，并去掉开头的方法名
+func firstLine(doc, name string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(doc), "\n")
+	line = strings.TrimPrefix(line, name)
+	return strings.TrimSpace(strings.TrimLeft(line, ": "))
+}
@@ -0,0 +1,97 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// defaultJumpSigma Jumps 默认的跳跃阈值（单笔收益率 / 稳健波动率）
+const defaultJumpSigma = 4.0
+
+// JumpStats 窗口内相邻成交之间的价格跳跃统计
+type JumpStats struct {
+	Sigma        float64       `json:"sigma"`          // 单笔对数收益率的稳健波动率（二次幂变差估计，不受跳跃本身影响）
+	MaxJump      float64       `json:"max_jump"`       // 最大单笔价格跳跃（绝对价格差，带符号）
+	MaxJumpRet   float64       `json:"max_jump_ret"`   // 对应的对数收益率
+	MaxJumpSigma float64       `json:"max_jump_sigma"` // 以 Sigma 为单位的幅度（绝对值）
+	MaxJumpTs    time.Time     `json:"max_jump_ts"`
+	Count        int           `json:"count"` // 幅度超过阈值的跳跃次数
+	LastJumpTs   time.Time     `json:"last_jump_ts,omitempty"`
+	SinceLast    time.Duration `json:"since_last"` // 最新成交距最近一次跳跃的时长，没有跳跃时为窗口跨度
+}
+
+// Jumps 相邻成交价格跳跃统计（读锁）：最大跳跃（绝对值与波动率单位）、超过 threshold 个
+// 波动率单位的跳跃次数及距最近一次跳跃的时长。threshold <= 0 时取 4。
+// 波动率用二次幂变差 sqrt(π/2·mean(|r_i|·|r_{i-1}|)) 估计，孤立的跳跃不会抬高自身的阈值
+//
+//metric:name=jumps unit=return cost=O(n) min_points=3
+func (w *SlidingWindow) Jumps(threshold float64) (JumpStats, bool) {
+	if !w.IsReady() {
+		return JumpStats{}, false
+	}
+
+	if threshold <= 0 {
+		threshold = defaultJumpSigma
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var js JumpStats
+	if w.size < 3 {
+		return js, false
+	}
+
+	// 第一遍：稳健波动率
+	var bpv float64
+	var pairs int
+	prevAbs := -1.0
+	prev := w.atUnlocked(0).Price.Float(w.priceScale)
+	for i := 1; i < w.size; i++ {
+		cur := w.atUnlocked(i).Price.Float(w.priceScale)
+		if prev <= 0 || cur <= 0 {
+			prev, prevAbs = cur, -1
+			continue
+		}
+		r := math.Abs(math.Log(cur / prev))
+		if prevAbs >= 0 {
+			bpv += r * prevAbs
+			pairs++
+		}
+		prev, prevAbs = cur, r
+	}
+	if pairs == 0 {
+		return js, false
+	}
+	js.Sigma = math.Sqrt(math.Pi / 2 * bpv / float64(pairs))
+
+	// 第二遍：最大跳跃与计数
+	prevPt := w.atUnlocked(0)
+	for i := 1; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		p0, p1 := prevPt.Price.Float(w.priceScale), pt.Price.Float(w.priceScale)
+		prevPt = pt
+		if p0 <= 0 || p1 <= 0 {
+			continue
+		}
+		r := math.Log(p1 / p0)
+		if math.Abs(r) > math.Abs(js.MaxJumpRet) {
+			js.MaxJump, js.MaxJumpRet, js.MaxJumpTs = p1-p0, r, pt.Ts
+		}
+		if js.Sigma > 0 && math.Abs(r) > threshold*js.Sigma {
+			js.Count++
+			js.LastJumpTs = pt.Ts
+		}
+	}
+	if js.Sigma > 0 {
+		js.MaxJumpSigma = math.Abs(js.MaxJumpRet) / js.Sigma
+	}
+
+	newest := w.lastUnlocked().Ts
+	if js.Count > 0 {
+		js.SinceLast = newest.Sub(js.LastJumpTs)
+	} else {
+		js.SinceLast = newest.Sub(w.atUnlocked(0).Ts)
+	}
+	return js, true
+}
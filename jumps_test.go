@@ -0,0 +1,39 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestJumps(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	px := 100.0
+	for i := 0; i < 50; i++ {
+		switch {
+		case i == 20:
+			px *= 1.02 // 跳空 2%
+		case i%2 == 0:
+			px *= 1.0005
+		default:
+			px /= 1.0005
+		}
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	js, ok := w.Jumps(0)
+	if !ok {
+		t.Fatal("not enough points")
+	}
+	if js.Count != 1 || !js.MaxJumpTs.Equal(t0.Add(20*time.Second)) || js.SinceLast != 29*time.Second {
+		t.Fatalf("jumps: %+v", js)
+	}
+	if math.Abs(js.MaxJumpRet-math.Log(1.02)) > 1e-4 || js.MaxJumpSigma < 10 || js.MaxJump <= 0 {
+		t.Fatalf("max jump: %+v", js)
+	}
+	// 稳健波动率与正常单笔波动同一量级，没有被跳跃本身撑大
+	if js.Sigma > 3*math.Log(1.0005) {
+		t.Fatalf("sigma = %v", js.Sigma)
+	}
+}
@@ -0,0 +1,52 @@
+package sliding_window
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// KafkaMessage 交给 KafkaProducer 的一条消息
+type KafkaMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+	Time  time.Time
+}
+
+// KafkaProducer Kafka 客户端的最小适配接口，例如对 segmentio/kafka-go：
+//
+//	func (a adapter) Produce(ctx context.Context, msgs []sw.KafkaMessage) error {
+//		out := make([]kafka.Message, len(msgs))
+//		for i, m := range msgs {
+//			out[i] = kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value, Time: m.Time}
+//		}
+//		return a.w.WriteMessages(ctx, out...)
+//	}
+type KafkaProducer interface {
+	Produce(ctx context.Context, msgs []KafkaMessage) error
+}
+
+// KafkaPublisher 把事件编码为 JSON 交给 KafkaProducer，以 symbol 作为分区键，保证同一 symbol 有序
+type KafkaPublisher struct {
+	Producer    KafkaProducer
+	Topic       string // 快照事件的 topic
+	SignalTopic string // 信号事件的 topic，为空时与 Topic 相同
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, events []Event) error {
+	msgs := make([]KafkaMessage, len(events))
+	for i := range events {
+		ev := &events[i]
+		value, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		topic := p.Topic
+		if ev.Kind == EventSignal && p.SignalTopic != "" {
+			topic = p.SignalTopic
+		}
+		msgs[i] = KafkaMessage{Topic: topic, Key: []byte(ev.Symbol), Value: value, Time: time.UnixMilli(ev.Ts)}
+	}
+	return p.Producer.Produce(ctx, msgs)
+}
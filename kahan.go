@@ -0,0 +1,24 @@
+package sliding_window
+
+import "math"
+
+// compensated Neumaier（改进的 Kahan）补偿求和：把每次加法丢掉的低位累计在 c 中，
+// 长时间高频加减（增量累加器）或大窗口遍历求和时误差不随点数增长
+type compensated struct {
+	sum float64
+	c   float64
+}
+
+func (k *compensated) add(x float64) {
+	t := k.sum + x
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.c += (k.sum - t) + x
+	} else {
+		k.c += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+func (k *compensated) value() float64 {
+	return k.sum + k.c
+}
@@ -0,0 +1,24 @@
+package sliding_window
+
+import "testing"
+
+func TestCompensated_AddRemoveNoDrift(t *testing.T) {
+	var k compensated
+	var plain float64
+	// 模拟增量累加器：大额成交额进窗后，大量小额成交进出窗口
+	k.add(1e12)
+	plain += 1e12
+	for i := 0; i < 1_000_000; i++ {
+		x := 0.1 + float64(i%7)*0.01
+		k.add(x)
+		plain += x
+		k.add(-x)
+		plain -= x
+	}
+	k.add(-1e12)
+	plain -= 1e12
+
+	if k.value() != 0 {
+		t.Fatalf("compensated residual = %v, want 0 (plain residual %v)", k.value(), plain)
+	}
+}
@@ -0,0 +1,96 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// leadLagSteps 每侧评估的滞后档数，重采样步长 = maxLag / leadLagSteps
+const leadLagSteps = 10
+
+type LeadLagResult struct {
+	Step     time.Duration   `json:"step"`
+	Lags     []time.Duration `json:"lags"` // 正值表示 a 领先 b
+	Corr     []float64       `json:"corr"`
+	BestLag  time.Duration   `json:"best_lag"`
+	BestCorr float64         `json:"best_corr"`
+	Leader   int             `json:"leader"` // 1: a 领先；-1: b 领先；0: 同步或无法判断
+}
+
+// LeadLag 计算两个窗口重采样收益率在 [-maxLag, maxLag] 各个滞后上的互相关，
+// 取相关性绝对值最大的滞后判断谁领先
+func LeadLag(a, b *SlidingWindow, maxLag time.Duration) (LeadLagResult, bool) {
+	var res LeadLagResult
+
+	step := maxLag / leadLagSteps
+	if step <= 0 {
+		return res, false
+	}
+	start, n, ok := commonGrid(a, b, step)
+	if !ok || n < 4*leadLagSteps {
+		return res, false
+	}
+
+	ra := logReturns(a.resampleOn(start, step, n))
+	rb := logReturns(b.resampleOn(start, step, n))
+
+	res.Step = step
+	res.Lags = make([]time.Duration, 0, 2*leadLagSteps+1)
+	res.Corr = make([]float64, 0, 2*leadLagSteps+1)
+
+	best := -1.0
+	for k := -leadLagSteps; k <= leadLagSteps; k++ {
+		c := laggedCorr(ra, rb, k)
+		res.Lags = append(res.Lags, time.Duration(k)*step)
+		res.Corr = append(res.Corr, c)
+		if math.Abs(c) > best {
+			best = math.Abs(c)
+			res.BestLag = time.Duration(k) * step
+			res.BestCorr = c
+		}
+	}
+
+	switch {
+	case res.BestLag > 0:
+		res.Leader = 1
+	case res.BestLag < 0:
+		res.Leader = -1
+	}
+	return res, true
+}
+
+// laggedCorr corr(x[t], y[t+k])；k>0 表示 x 领先 y
+func laggedCorr(x, y []float64, k int) float64 {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+
+	var sx, sy, sxx, syy, sxy float64
+	m := 0
+	for t := 0; t < n; t++ {
+		u := t + k
+		if u < 0 || u >= n {
+			continue
+		}
+		a, b := x[t], y[u]
+		sx += a
+		sy += b
+		sxx += a * a
+		syy += b * b
+		sxy += a * b
+		m++
+	}
+	if m < 2 {
+		return 0
+	}
+
+	fm := float64(m)
+	cov := sxy - sx*sy/fm
+	vx := sxx - sx*sx/fm
+	vy := syy - sy*sy/fm
+	if vx <= 0 || vy <= 0 {
+		return 0
+	}
+	return cov / math.Sqrt(vx*vy)
+}
@@ -0,0 +1,51 @@
+package sliding_window
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestLeadLag_DetectsLeader(t *testing.T) {
+	a := NewSlidingWindow(time.Minute, 8192, 0.1)
+	b := NewSlidingWindow(time.Minute, 8192, 0.1)
+
+	r := rand.New(rand.NewSource(7))
+	base := time.Unix(1_700_000_000, 0)
+	const lag = 30 // b 比 a 晚 300ms
+
+	prices := make([]float64, 600)
+	px := 100.0
+	for i := range prices {
+		px += r.NormFloat64() * 0.05
+		prices[i] = px
+	}
+	for i := range prices {
+		ts := base.Add(time.Duration(i) * 10 * time.Millisecond)
+		a.AddWindowPoint(SideBuy, prices[i], 1, ts)
+		j := i - lag
+		if j < 0 {
+			j = 0
+		}
+		b.AddWindowPoint(SideBuy, prices[j], 1, ts)
+	}
+
+	res, ok := LeadLag(a, b, time.Second)
+	if !ok {
+		t.Fatal("lead-lag should be computable")
+	}
+	if res.Leader != 1 || res.BestLag != 300*time.Millisecond {
+		t.Fatalf("expected a to lead by 300ms, got leader=%d lag=%v corr=%.3f", res.Leader, res.BestLag, res.BestCorr)
+	}
+
+	// 步长过小时网格点数超过上限，直接拒绝而不是分配巨大的网格
+	if _, ok := LeadLag(a, b, time.Microsecond); ok {
+		t.Fatal("tiny maxLag should exceed the grid limit")
+	}
+	if _, px := a.Resample(time.Nanosecond); px != nil {
+		t.Fatalf("1ns resample allocated %d points", len(px))
+	}
+	if _, px := a.Resample(10 * time.Millisecond); len(px) != 600 {
+		t.Fatalf("resample len = %d", len(px))
+	}
+}
@@ -0,0 +1,149 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// ProfileBin 成交量 / 停留时间分布中的一个价格区间
+type ProfileBin struct {
+	Low    float64       `json:"low"`
+	High   float64       `json:"high"`
+	Mid    float64       `json:"mid"`
+	Volume float64       `json:"volume"`
+	Dwell  time.Duration `json:"dwell"` // 价格停留在该区间的开市时长
+	Trades int           `json:"trades"`
+}
+
+// Level 一个支撑 / 阻力价位
+type Level struct {
+	Price    float64       `json:"price"`
+	Low      float64       `json:"low"`
+	High     float64       `json:"high"`
+	Strength float64       `json:"strength"` // 综合强度 [0, 1]
+	Volume   float64       `json:"volume"`
+	Dwell    time.Duration `json:"dwell"`
+}
+
+const (
+	// levelProfileBins Levels 使用的分箱数
+	levelProfileBins = 50
+	// levelVolumeWeight 综合强度中成交量的权重，其余为停留时间
+	levelVolumeWeight = 0.6
+)
+
+// VolumeProfile 把 [low, high] 等分为 bins 个区间，统计各区间的成交量、成交笔数，
+// 以及价格停留时长（每个价格持续到下一笔成交，只计开市时长）（读锁）
+func (w *SlidingWindow) VolumeProfile(bins int) ([]ProfileBin, bool) {
+	if !w.IsReady() {
+		return nil, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.volumeProfileUnlocked(bins)
+}
+
+func (w *SlidingWindow) volumeProfileUnlocked(bins int) ([]ProfileBin, bool) {
+	if bins <= 0 || w.size < 2 {
+		return nil, false
+	}
+
+	lo := QtyLoz(w.LowestPrice.Load()).Float(w.priceScale)
+	hi := QtyLoz(w.HighestPrice.Load()).Float(w.priceScale)
+	if w.hiLoDirty || hi <= lo {
+		return nil, false
+	}
+
+	width := (hi - lo) / float64(bins)
+	out := make([]ProfileBin, bins)
+	for i := range out {
+		out[i].Low = lo + float64(i)*width
+		out[i].High = out[i].Low + width
+		out[i].Mid = out[i].Low + width/2
+	}
+
+	binOf := func(px float64) int {
+		k := int((px - lo) / width)
+		if k < 0 {
+			k = 0
+		}
+		if k >= bins {
+			k = bins - 1
+		}
+		return k
+	}
+
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		b := &out[binOf(pt.Price.Float(w.priceScale))]
+		b.Volume += pt.Volume.Float(w.volumeScale)
+		b.Trades++
+		if i+1 < w.size {
+			b.Dwell += w.openDurationUnlocked(pt.Ts, w.atUnlocked(i+1).Ts)
+		}
+	}
+	return out, true
+}
+
+// Levels 从成交量分布和价格停留时间中提取最强的 n 个价位（读锁），按强度降序。
+// 强度 = 0.6 × 成交量 / 最大成交量 + 0.4 × 停留时长 / 最大停留时长，只保留局部峰值区间，
+// 相邻区间不会重复出现。
+func (w *SlidingWindow) Levels(n int) []Level {
+	if n <= 0 {
+		return nil
+	}
+	profile, ok := w.VolumeProfile(levelProfileBins)
+	if !ok {
+		return nil
+	}
+
+	var maxVol float64
+	var maxDwell time.Duration
+	for _, b := range profile {
+		maxVol = max(maxVol, b.Volume)
+		maxDwell = max(maxDwell, b.Dwell)
+	}
+
+	strength := make([]float64, len(profile))
+	for i, b := range profile {
+		var s float64
+		if maxVol > 0 {
+			s += levelVolumeWeight * b.Volume / maxVol
+		}
+		if maxDwell > 0 {
+			s += (1 - levelVolumeWeight) * float64(b.Dwell) / float64(maxDwell)
+		}
+		strength[i] = s
+	}
+
+	var levels []Level
+	for i, b := range profile {
+		s := strength[i]
+		if s <= 0 {
+			continue
+		}
+		// 局部峰值：不弱于左右相邻区间（平台只取左端）
+		if i > 0 && strength[i-1] >= s {
+			continue
+		}
+		if i+1 < len(profile) && strength[i+1] > s {
+			continue
+		}
+		levels = append(levels, Level{
+			Price:    b.Mid,
+			Low:      b.Low,
+			High:     b.High,
+			Strength: s,
+			Volume:   b.Volume,
+			Dwell:    b.Dwell,
+		})
+	}
+
+	sort.SliceStable(levels, func(a, b int) bool { return levels[a].Strength > levels[b].Strength })
+	if len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
@@ -0,0 +1,36 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLevels(t *testing.T) {
+	w := NewSlidingWindow(time.Hour, 4096, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	// 大部分时间在 100 与 110 附近成交，中间价位一扫而过
+	i := 0
+	add := func(px, vol float64, gap time.Duration) {
+		w.AddWindowPoint(SideBuy, px, vol, base.Add(time.Duration(i)*gap))
+		i++
+	}
+	for k := 0; k < 200; k++ {
+		add(100, 5, time.Second)
+	}
+	for px := 101.0; px < 110; px++ {
+		add(px, 0.1, time.Second)
+	}
+	for k := 0; k < 100; k++ {
+		add(110, 3, time.Second)
+	}
+
+	lv := w.Levels(2)
+	if len(lv) != 2 {
+		t.Fatalf("got %d levels, want 2: %+v", len(lv), lv)
+	}
+	if math.Abs(lv[0].Price-100) > 0.2 || math.Abs(lv[1].Price-110) > 0.2 || lv[0].Strength <= lv[1].Strength {
+		t.Fatalf("unexpected levels %+v", lv)
+	}
+}
@@ -0,0 +1,156 @@
+package sliding_window
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Component 带后台协程的组件（归档、广播、调度等）的统一生命周期：
+// Start 启动后台工作并立即返回，ctx 结束等价于 Close；Close 停止并等待退出，可重复调用。
+type Component interface {
+	Start(ctx context.Context) error
+	Close() error
+}
+
+var errAlreadyStarted = errors.New("component already started")
+
+// runner 把阻塞式的 Run(ctx) 包装成 Start / Close
+type runner struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+func (r *runner) start(ctx context.Context, run func(context.Context) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done != nil {
+		return errAlreadyStarted
+	}
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		err := run(ctx)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			err = nil
+		}
+		r.mu.Lock()
+		r.err = err
+		r.mu.Unlock()
+	}()
+	return nil
+}
+
+// started 是否调用过 start
+func (r *runner) started() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done != nil
+}
+
+func (r *runner) close() error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	cancel()
+	<-done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// components 窗口 / manager 上挂载的组件集合
+type components struct {
+	mu     sync.Mutex
+	list   []Component
+	ctx    context.Context // Start 之后非 nil，之后 Attach 的组件立即启动
+	closed bool
+}
+
+func (c *components) attach(comp Component) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return errors.New("attach after Close")
+	}
+	c.list = append(c.list, comp)
+	if c.ctx != nil {
+		return comp.Start(c.ctx)
+	}
+	return nil
+}
+
+func (c *components) start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ctx != nil {
+		return errAlreadyStarted
+	}
+	c.ctx = ctx
+	var errs []error
+	for _, comp := range c.list {
+		if err := comp.Start(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close 按挂载的逆序关闭
+func (c *components) close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	list := c.list
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(list) - 1; i >= 0; i-- {
+		if err := list[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Attach 挂载一个后台组件（如 BatchArchiver），随窗口 Start / Close；已 Start 时立即启动
+func (w *SlidingWindow) Attach(c Component) error { return w.comps.attach(c) }
+
+// Start 启动挂载在窗口上的组件
+func (w *SlidingWindow) Start(ctx context.Context) error { return w.comps.start(ctx) }
+
+// Close 写入限速合并中的聚合点、收盘未完成的归档 K 线，然后按逆序关闭挂载的组件
+func (w *SlidingWindow) Close() error {
+	w.FlushIngest()
+	w.FlushArchive()
+	return w.comps.close()
+}
+
+// Attach 挂载一个后台组件（Broadcaster、AlignedScheduler 等），随 manager Start / Close
+func (m *Manager) Attach(c Component) error { return m.comps.attach(c) }
+
+// Start 启动挂载在 manager 上的组件
+func (m *Manager) Start(ctx context.Context) error { return m.comps.start(ctx) }
+
+// Close 先关闭 manager 上的组件（停止读取窗口），再关闭全部窗口
+func (m *Manager) Close() error {
+	errs := []error{m.comps.close()}
+	for _, t := range m.resolve(nil) {
+		errs = append(errs, t.w.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package sliding_window
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memCandleStore struct {
+	mu  sync.Mutex
+	got []Candle
+}
+
+func (s *memCandleStore) WriteCandles(c []Candle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.got = append(s.got, c...)
+	return nil
+}
+
+func TestLifecycle_CloseFlushesArchive(t *testing.T) {
+	store := &memCandleStore{}
+	arch := NewBatchArchiver(store, 100, time.Hour, 0)
+
+	w := NewSlidingWindow(time.Second, 1024, 0.1)
+	w.SetArchiver(arch, time.Second)
+	if err := w.Attach(arch); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(nil)
+	m.Set("BTC", w)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 30; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 出窗时收盘的一根 K 线 + Close 时收盘的未完成 K 线
+	if len(store.got) != 2 {
+		t.Fatalf("store got %d candles, want 2", len(store.got))
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("second Close should be a no-op, got %v", err)
+	}
+}
+
+func TestBatchArchiver_CloseWithoutStart(t *testing.T) {
+	store := &memCandleStore{}
+	arch := NewBatchArchiver(store, 2, time.Hour, 8)
+	for i := 0; i < 5; i++ {
+		arch.OnBarClose(Candle{Trades: i})
+	}
+
+	err := arch.Close()
+	if !errors.Is(err, ErrArchiverNotStarted) {
+		t.Fatalf("Close without Start = %v, want ErrArchiverNotStarted", err)
+	}
+	// 队列里的 K 线在 Close 时同步写入，不会丢
+	if len(store.got) != 5 {
+		t.Fatalf("store got %d candles, want 5", len(store.got))
+	}
+	arch.OnBarClose(Candle{})
+	if arch.Dropped() != 1 {
+		t.Fatalf("dropped = %d after Close", arch.Dropped())
+	}
+}
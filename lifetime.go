@@ -0,0 +1,85 @@
+package sliding_window
+
+import "time"
+
+// lifetimeStats 自窗口创建以来单调累加的统计，不随出窗扣减（要求持有写锁更新）
+type lifetimeStats struct {
+	trades     int64
+	volume     compensated // 真实值（合约数）
+	notional   compensated
+	buyVolume  compensated
+	sellVolume compensated
+	maxPx      int64
+	minPx      int64
+	seen       bool
+	first      time.Time
+	last       time.Time
+}
+
+// Lifetime 自窗口创建以来的累计统计
+type Lifetime struct {
+	Trades     int64     `json:"trades"`   // 进入窗口的成交笔数（游程 / 限速合并按原始笔数）
+	Volume     float64   `json:"volume"`   // 累计成交量（真实值，合约数）
+	Notional   float64   `json:"notional"` // 累计成交额
+	BuyVolume  float64   `json:"buy_volume"`
+	SellVolume float64   `json:"sell_volume"`
+	Rejected   int64     `json:"rejected"`  // 到达时已经过期而被丢弃的点
+	MaxPrice   float64   `json:"max_price"` // 历史最高价（含报价更新）
+	MinPrice   float64   `json:"min_price"`
+	FirstTs    time.Time `json:"first_ts"` // 第一笔进入窗口的时间
+	LastTs     time.Time `json:"last_ts"`
+}
+
+// observeLifetimeUnlocked 点首次进入窗口时累加（要求持有写锁）
+func (w *SlidingWindow) observeLifetimeUnlocked(pt WindowPoint) {
+	l := &w.lifetime
+	px := pt.Price.Int64()
+	if !l.seen {
+		l.seen = true
+		l.maxPx, l.minPx = px, px
+		l.first = pt.Ts
+	}
+	l.maxPx, l.minPx = max(l.maxPx, px), min(l.minPx, px)
+	if pt.Ts.After(l.last) {
+		l.last = pt.Ts
+	}
+	if w.isQuoteUnlocked(pt) {
+		return
+	}
+
+	l.trades += pt.Trades()
+	v := max(pt.Volume.Float(w.volumeScale), 0)
+	n := v * pt.Price.Float(w.priceScale)
+	l.volume.add(v)
+	l.notional.add(n)
+	switch pt.Side {
+	case SideBuy:
+		l.buyVolume.add(v)
+	case SideSell:
+		l.sellVolume.add(v)
+	}
+}
+
+// Lifetime 自窗口创建以来单调递增的累计统计（读锁），不受出窗影响，
+// 便于与交易所公布的成交总量、笔数对账
+func (w *SlidingWindow) Lifetime() Lifetime {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	l := &w.lifetime
+	out := Lifetime{
+		Trades:     l.trades,
+		Volume:     l.volume.value(),
+		Notional:   l.notional.value(),
+		BuyVolume:  l.buyVolume.value(),
+		SellVolume: l.sellVolume.value(),
+		Rejected:   w.counters.rejects.Load(),
+		FirstTs:    l.first,
+		LastTs:     l.last,
+	}
+	if l.seen {
+		out.MaxPrice = QtyLoz(l.maxPx).Float(w.priceScale)
+		out.MinPrice = QtyLoz(l.minPx).Float(w.priceScale)
+	}
+	return out
+}
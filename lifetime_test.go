@@ -0,0 +1,43 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLifetime(t *testing.T) {
+	w := NewSlidingWindow(5*time.Second, 64, 0.1)
+	w.SetRunLength(time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	prices := []float64{100, 100, 105, 98, 101, 101, 99, 102, 103, 100}
+	for i, px := range prices {
+		side := SideBuy
+		if i%2 == 1 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, px, 2, t0.Add(time.Duration(i)*time.Second))
+	}
+	// 同一批里按最新一笔判断，第一笔已过期
+	ps := NewQtyScaleFromDecimals(4)
+	vs := NewQtyScaleFromDecimals(8)
+	w.Add(
+		WindowPoint{Ts: t0, Price: NewQtyLoz(200, ps), Volume: NewQtyLoz(1, vs), Side: SideBuy},
+		WindowPoint{Ts: t0.Add(10 * time.Second), Price: NewQtyLoz(100, ps), Volume: NewQtyLoz(2, vs), Side: SideBuy},
+	)
+
+	l := w.Lifetime()
+	if l.Trades != 11 || l.Volume != 22 || l.BuyVolume != 12 || l.SellVolume != 10 {
+		t.Fatalf("totals: %+v", l)
+	}
+	if l.MaxPrice != 105 || l.MinPrice != 98 || l.Rejected != 1 {
+		t.Fatalf("extremes: %+v", l)
+	}
+	if !l.FirstTs.Equal(t0) || !l.LastTs.Equal(t0.Add(10*time.Second)) {
+		t.Fatalf("span: %+v", l)
+	}
+	// 窗口内只剩最近 5 秒，累计值不受影响
+	if w.SumVolume() >= l.Volume {
+		t.Fatalf("window volume %v should be below lifetime %v", w.SumVolume(), l.Volume)
+	}
+}
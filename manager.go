@@ -0,0 +1,194 @@
+package sliding_window
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WindowFactory 按 symbol 创建新窗口
+type WindowFactory func(symbol string) *SlidingWindow
+
+// Manager 多 symbol 窗口管理（并发安全）
+type Manager struct {
+	mu      sync.RWMutex
+	windows map[string]*SlidingWindow
+	factory WindowFactory
+	comps   components
+	clock   atomic.Pointer[clockRef]
+}
+
+func NewManager(factory WindowFactory) *Manager {
+	return &Manager{
+		windows: make(map[string]*SlidingWindow),
+		factory: factory,
+	}
+}
+
+// WindowManager Manager 的别名：按 symbol 管理窗口的注册表
+type WindowManager = Manager
+
+// NewWindowManager 所有 symbol 使用同一组参数创建窗口的 Manager
+func NewWindowManager(duration time.Duration, capacity int, emaAlpha float64) *WindowManager {
+	return NewManager(func(string) *SlidingWindow {
+		return NewSlidingWindow(duration, capacity, emaAlpha)
+	})
+}
+
+// Get 查找窗口（读锁）
+func (m *Manager) Get(symbol string) (*SlidingWindow, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	w, ok := m.windows[symbol]
+	return w, ok
+}
+
+// GetOrCreate 查找窗口，不存在时用 factory 创建；factory 为空时返回 nil
+func (m *Manager) GetOrCreate(symbol string) *SlidingWindow {
+	if w, ok := m.Get(symbol); ok {
+		return w
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// double check
+	if w, ok := m.windows[symbol]; ok {
+		return w
+	}
+	if m.factory == nil {
+		return nil
+	}
+	w := m.factory(symbol)
+	if w != nil {
+		m.windows[symbol] = w
+	}
+	return w
+}
+
+// Set 注册（或替换）一个外部创建的窗口；被替换的旧窗口撤销其 expvar 条目
+func (m *Manager) Set(symbol string, w *SlidingWindow) {
+	m.mu.Lock()
+	old := m.windows[symbol]
+	m.windows[symbol] = w
+	m.mu.Unlock()
+
+	if old != nil && old != w {
+		old.unpublishExpvarAll()
+	}
+}
+
+// Remove 移除窗口并撤销其 expvar 条目（见 SlidingWindow.PublishExpvar）
+func (m *Manager) Remove(symbol string) {
+	m.mu.Lock()
+	old := m.windows[symbol]
+	delete(m.windows, symbol)
+	m.mu.Unlock()
+
+	if old != nil {
+		old.unpublishExpvarAll()
+	}
+}
+
+func (m *Manager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.windows)
+}
+
+// Symbols 返回排序后的 symbol 列表
+func (m *Manager) Symbols() []string {
+	m.mu.RLock()
+	out := make([]string, 0, len(m.windows))
+	for s := range m.windows {
+		out = append(out, s)
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(out)
+	return out
+}
+
+// AddWindowPoint 把一笔成交路由到 symbol 的窗口（不存在时创建），factory 为空且窗口不存在时返回 false
+func (m *Manager) AddWindowPoint(symbol string, side Side, price, size float64, ts time.Time) bool {
+	w := m.GetOrCreate(symbol)
+	if w == nil {
+		return false
+	}
+	w.AddWindowPoint(side, price, size, ts)
+	return true
+}
+
+// Add 把一批点路由到 symbol 的窗口（不存在时创建），factory 为空且窗口不存在时返回 false
+func (m *Manager) Add(symbol string, pts ...WindowPoint) bool {
+	w := m.GetOrCreate(symbol)
+	if w == nil {
+		return false
+	}
+	w.Add(pts...)
+	return true
+}
+
+// Range 按 symbol 排序遍历全部窗口，fn 返回 false 时停止。
+// manager 锁只在取窗口引用时持有，fn 内可以安全地调用 Manager 的其他方法
+func (m *Manager) Range(fn func(symbol string, w *SlidingWindow) bool) {
+	targets := m.resolve(nil)
+	sort.Slice(targets, func(i, j int) bool { return targets[i].symbol < targets[j].symbol })
+	for _, t := range targets {
+		if !fn(t.symbol, t.w) {
+			return
+		}
+	}
+}
+
+// SnapshotAll 对全部窗口做一轮快照，等价于 SnapshotMany(nil)
+func (m *Manager) SnapshotAll() map[string]*Snapshot {
+	return m.SnapshotMany(nil)
+}
+
+type symbolWindow struct {
+	symbol string
+	w      *SlidingWindow
+}
+
+// resolve 在 manager 读锁内一次性取出窗口引用，之后的计算都在锁外做
+func (m *Manager) resolve(symbols []string) []symbolWindow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if symbols == nil {
+		out := make([]symbolWindow, 0, len(m.windows))
+		for s, w := range m.windows {
+			out = append(out, symbolWindow{symbol: s, w: w})
+		}
+		return out
+	}
+
+	out := make([]symbolWindow, 0, len(symbols))
+	for _, s := range symbols {
+		if w, ok := m.windows[s]; ok {
+			out = append(out, symbolWindow{symbol: s, w: w})
+		}
+	}
+	return out
+}
+
+// SnapshotMany 对多个 symbol 做一轮尽量同时的快照，并打上统一的 BatchTs。
+// symbols 为 nil 时快照全部窗口；不存在或未就绪的 symbol 不出现在结果中。
+// manager 锁只在解析窗口引用时持有，每个窗口只在自己的 Snapshot 内短暂加锁。
+func (m *Manager) SnapshotMany(symbols []string) map[string]*Snapshot {
+	targets := m.resolve(symbols)
+	out := make(map[string]*Snapshot, len(targets))
+
+	batchTs := m.now().UnixMilli()
+	for _, t := range targets {
+		s := t.w.Snapshot()
+		if s == nil {
+			continue
+		}
+		s.BatchTs = batchTs
+		out[t.symbol] = s
+	}
+	return out
+}
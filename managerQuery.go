@@ -0,0 +1,114 @@
+package sliding_window
+
+import (
+	"math"
+	"sort"
+)
+
+// Filter Manager.Query 的筛选条件，零值表示不限制
+type Filter struct {
+	MinVolume       float64              // TotalVolume 下限（展示单位）
+	MinTrades       int64                // NTrades 下限
+	MinAbsImbalance float64              // |Imbalance| 下限
+	Symbols         func(string) bool    // symbol 过滤，比如只要某个交易所前缀
+	Where           func(*Snapshot) bool // 自定义条件，在上面的条件之后判断
+}
+
+func (f Filter) match(symbol string, s *Snapshot) bool {
+	if f.Symbols != nil && !f.Symbols(symbol) {
+		return false
+	}
+	if s.TotalVolume < f.MinVolume || s.NTrades < f.MinTrades {
+		return false
+	}
+	if math.Abs(s.Imbalance) < f.MinAbsImbalance {
+		return false
+	}
+	return f.Where == nil || f.Where(s)
+}
+
+// SortKey Manager.Query 的排序字段：SortSymbol 按字母升序，其余按数值降序，相同时按 symbol
+type SortKey int
+
+const (
+	SortSymbol       SortKey = iota
+	SortImbalance            // 买方最强在前
+	SortAbsImbalance         // 单边最明显在前（不分方向）
+	SortVolume
+	SortDeltaVolume
+	SortMomentum
+	SortVolatility
+	SortNormDist // 偏离均衡区最远（向上）在前
+)
+
+func (k SortKey) value(s *Snapshot) float64 {
+	switch k {
+	case SortImbalance:
+		return s.Imbalance
+	case SortAbsImbalance:
+		return math.Abs(s.Imbalance)
+	case SortVolume:
+		return s.TotalVolume
+	case SortDeltaVolume:
+		return s.DeltaVolume
+	case SortMomentum:
+		return s.Momentum
+	case SortVolatility:
+		return s.Volatility
+	case SortNormDist:
+		return s.NormDist
+	default:
+		return 0
+	}
+}
+
+// QueryRow Query 的一行结果
+type QueryRow struct {
+	Symbol   string    `json:"symbol"`
+	Snapshot *Snapshot `json:"snapshot"`
+}
+
+// Query 面向选股器的分页查询：对全部窗口快照、按 filter 筛选、按 key 排序后返回
+// [offset, offset+limit) 这一页，以及筛选后的总数。limit <= 0 表示不限。
+// 未就绪的窗口不参与；同一次查询的快照打上统一的 BatchTs。
+//
+// 例如成交量不低于 1000 的前 20 个买方失衡最强的品种：
+//
+//	m.Query(Filter{MinVolume: 1000}, SortImbalance, 20, 0)
+func (m *Manager) Query(filter Filter, key SortKey, limit, offset int) ([]QueryRow, int) {
+	targets := m.resolve(nil)
+	rows := make([]QueryRow, 0, len(targets))
+
+	batchTs := m.now().UnixMilli()
+	for _, t := range targets {
+		s := t.w.Snapshot()
+		if s == nil || !filter.match(t.symbol, s) {
+			continue
+		}
+		s.BatchTs = batchTs
+		rows = append(rows, QueryRow{Symbol: t.symbol, Snapshot: s})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if key != SortSymbol {
+			a, b := key.value(rows[i].Snapshot), key.value(rows[j].Snapshot)
+			if a != b {
+				return a > b
+			}
+		}
+		return rows[i].Symbol < rows[j].Symbol
+	})
+
+	total := len(rows)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return nil, total
+	}
+	rows = rows[offset:]
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows, total
+}
@@ -0,0 +1,50 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManager_Query(t *testing.T) {
+	m := NewManager(func(string) *SlidingWindow { return NewSlidingWindow(time.Minute, 1024, 0.1) })
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 买量占比逐个提高；D 成交量太小
+	for i, sym := range []string{"A", "B", "C", "D"} {
+		w := m.GetOrCreate(sym)
+		vol := 10.0
+		if sym == "D" {
+			vol = 0.1
+		}
+		for k := 0; k < 10; k++ {
+			side := SideSell
+			if k <= i*3 {
+				side = SideBuy
+			}
+			w.AddWindowPoint(side, 100+float64(k), vol, t0.Add(time.Duration(k)*time.Second))
+		}
+	}
+	m.GetOrCreate("E") // 未就绪
+
+	rows, total := m.Query(Filter{MinVolume: 50}, SortImbalance, 2, 0)
+	if total != 3 || len(rows) != 2 || rows[0].Symbol != "C" || rows[1].Symbol != "B" {
+		t.Fatalf("page 1: total %d rows %+v", total, rows)
+	}
+	if rows[0].Snapshot.BatchTs == 0 || rows[0].Snapshot.BatchTs != rows[1].Snapshot.BatchTs {
+		t.Fatal("rows should share one batch ts")
+	}
+
+	rows, total = m.Query(Filter{MinVolume: 50}, SortImbalance, 2, 2)
+	if total != 3 || len(rows) != 1 || rows[0].Symbol != "A" {
+		t.Fatalf("page 2: total %d rows %+v", total, rows)
+	}
+
+	rows, total = m.Query(Filter{Symbols: func(s string) bool { return s != "A" }}, SortSymbol, 0, 0)
+	if total != 3 || rows[0].Symbol != "B" || rows[2].Symbol != "D" {
+		t.Fatalf("symbol filter: total %d rows %+v", total, rows)
+	}
+
+	if rows, total = m.Query(Filter{}, SortVolume, 10, 5); rows != nil || total != 4 {
+		t.Fatalf("offset past end: %v %d", rows, total)
+	}
+}
@@ -0,0 +1,76 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowManager_Routing(t *testing.T) {
+	m := NewWindowManager(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 3; i++ {
+		ts := t0.Add(time.Duration(i) * time.Second)
+		m.AddWindowPoint("BTC", SideBuy, 100+float64(i), 1, ts)
+		m.AddWindowPoint("ETH", SideSell, 10, 2, ts)
+	}
+	m.Add("SOL", WindowPoint{Ts: t0, Price: NewQtyLoz(1, NewQtyScaleFromDecimals(4)), Volume: 1, Side: SideBuy})
+
+	if m.Len() != 3 {
+		t.Fatalf("len = %d", m.Len())
+	}
+	if w, _ := m.Get("ETH"); w.SumVolume() != 6 {
+		t.Fatalf("eth volume = %v", w.SumVolume())
+	}
+
+	var seen []string
+	m.Range(func(symbol string, _ *SlidingWindow) bool {
+		seen = append(seen, symbol)
+		return symbol != "ETH"
+	})
+	if len(seen) != 2 || seen[0] != "BTC" || seen[1] != "ETH" {
+		t.Fatalf("range order: %v", seen)
+	}
+
+	// SOL 只有一个点，快照未就绪
+	snaps := m.SnapshotAll()
+	if len(snaps) != 2 || snaps["BTC"] == nil || snaps["BTC"].BatchTs != snaps["ETH"].BatchTs {
+		t.Fatalf("snapshots: %v", snaps)
+	}
+
+	if NewManager(nil).AddWindowPoint("X", SideBuy, 1, 1, t0) {
+		t.Fatal("manager without factory should not create windows")
+	}
+}
+
+func TestManager_SnapshotMany(t *testing.T) {
+	m := NewWindowManager(time.Minute, 64, 0.1)
+	m.SetClock(NewManualClock(time.Unix(1_700_000_100, 0)))
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 3; i++ {
+		ts := t0.Add(time.Duration(i) * time.Second)
+		m.AddWindowPoint("BTC", SideBuy, 100+float64(i), 1, ts)
+		m.AddWindowPoint("ETH", SideSell, 10-float64(i), 2, ts)
+		m.AddWindowPoint("SOL", SideBuy, 1, 1, ts)
+	}
+	m.AddWindowPoint("XRP", SideBuy, 1, 1, t0) // 只有一个点，未就绪
+
+	// 只快照请求的 symbol；不存在或未就绪的不出现在结果中
+	snaps := m.SnapshotMany([]string{"BTC", "ETH", "XRP", "DOGE"})
+	if len(snaps) != 2 || snaps["BTC"] == nil || snaps["ETH"] == nil {
+		t.Fatalf("snapshots: %v", snaps)
+	}
+	batch := time.Unix(1_700_000_100, 0).UnixMilli()
+	for sym, s := range snaps {
+		if s.BatchTs != batch {
+			t.Fatalf("%s batch ts = %d, want %d", sym, s.BatchTs, batch)
+		}
+	}
+	if snaps["BTC"].LatestPrice != 102 || snaps["ETH"].LatestPrice != 8 {
+		t.Fatalf("latest prices: btc %v eth %v", snaps["BTC"].LatestPrice, snaps["ETH"].LatestPrice)
+	}
+
+	if all := m.SnapshotMany(nil); len(all) != 3 {
+		t.Fatalf("nil symbols should snapshot every ready window, got %d", len(all))
+	}
+}
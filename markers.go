@@ -0,0 +1,100 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// maxMarkers 标注数量上限，防止长时间无成交时标注无限堆积
+const maxMarkers = 1024
+
+// Marker 窗口内的外部事件标注（新闻、资金费率、下单等）
+type Marker struct {
+	Ts    time.Time `json:"ts"`
+	Label string    `json:"label"`
+}
+
+// Mark 记录一个外部事件（写锁）。标注随窗口按时间一起过期，
+// 早于当前窗口左边界的标注直接丢弃并返回 false。
+func (w *SlidingWindow) Mark(ts time.Time, label string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && !ts.After(w.thresholdUnlocked(w.lastUnlocked().Ts)) {
+		return false
+	}
+
+	m := Marker{Ts: ts, Label: label}
+	n := len(w.markers)
+	if n == 0 || !ts.Before(w.markers[n-1].Ts) {
+		w.markers = append(w.markers, m)
+	} else {
+		// 乱序标注：插入到正确位置
+		i := sort.Search(n, func(i int) bool { return w.markers[i].Ts.After(ts) })
+		w.markers = append(w.markers, Marker{})
+		copy(w.markers[i+1:], w.markers[i:])
+		w.markers[i] = m
+	}
+
+	if len(w.markers) > maxMarkers {
+		w.markers = append(w.markers[:0], w.markers[len(w.markers)-maxMarkers:]...)
+	}
+	return true
+}
+
+// Markers 返回当前窗口内全部标注的副本（读锁）
+func (w *SlidingWindow) Markers() []Marker {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]Marker, len(w.markers))
+	copy(out, w.markers)
+	return out
+}
+
+// trimMarkersUnlocked 移除 Ts <= threshold 的标注（要求持有写锁）
+func (w *SlidingWindow) trimMarkersUnlocked(threshold time.Time) {
+	i := 0
+	for i < len(w.markers) && !w.markers[i].Ts.After(threshold) {
+		i++
+	}
+	if i > 0 {
+		w.markers = append(w.markers[:0], w.markers[i:]...)
+	}
+}
+
+// WindowRange 区间查询结果：点与同区间内的标注
+type WindowRange struct {
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Points  []WindowPoint `json:"points"`
+	Markers []Marker      `json:"markers"`
+}
+
+// Range 复制 [from, to] 区间内的点和标注（读锁），便于对事件前后做取证分析
+func (w *SlidingWindow) Range(from, to time.Time) WindowRange {
+	r := WindowRange{From: from, To: to}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	lo := w.searchTsUnlocked(from)
+	hi := w.searchTsUnlocked(to.Add(1)) // 包含 to
+	if hi > lo {
+		r.Points = make([]WindowPoint, 0, hi-lo)
+		for i := lo; i < hi; i++ {
+			r.Points = append(r.Points, w.atUnlocked(i))
+		}
+	}
+
+	for _, m := range w.markers {
+		if m.Ts.Before(from) {
+			continue
+		}
+		if m.Ts.After(to) {
+			break
+		}
+		r.Markers = append(r.Markers, m)
+	}
+	return r
+}
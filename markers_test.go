@@ -0,0 +1,39 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarkers(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	for s := 0; s < 5; s++ {
+		w.AddWindowPoint(SideBuy, 100+float64(s), 1, at(s))
+	}
+
+	w.Mark(at(3), "funding")
+	w.Mark(at(1), "news") // 乱序标注插入到正确位置
+	if ms := w.Markers(); len(ms) != 2 || ms[0].Label != "news" || ms[1].Label != "funding" {
+		t.Fatalf("markers: %+v", ms)
+	}
+
+	// 区间两端都包含
+	r := w.Range(at(1), at(3))
+	if len(r.Points) != 3 || r.Points[0].Ts != at(1) || r.Points[2].Ts != at(3) || len(r.Markers) != 2 {
+		t.Fatalf("range: %+v", r)
+	}
+	if r = w.Range(at(2), at(2)); len(r.Points) != 1 || len(r.Markers) != 0 {
+		t.Fatalf("narrow range: %+v", r)
+	}
+
+	// 标注随窗口一起过期，早于左边界的标注被拒绝
+	w.AddWindowPoint(SideBuy, 105, 1, at(12))
+	if ms := w.Markers(); len(ms) != 1 || ms[0].Label != "funding" {
+		t.Fatalf("after expiry: %+v", ms)
+	}
+	if w.Mark(at(2), "late") {
+		t.Fatal("marker before the window should be rejected")
+	}
+}
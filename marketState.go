@@ -0,0 +1,120 @@
+package sliding_window
+
+import "math"
+
+type MarketStateKind int
+
+const (
+	MarketIlliquid MarketStateKind = iota // 成交太少，指标不可信
+	MarketRanging                         // 震荡
+	MarketTrending                        // 趋势
+	MarketVolatile                        // 高波动
+)
+
+// MarketStateConfig 市场状态分类阈值
+type MarketStateConfig struct {
+	MinTrades int     // 少于该笔数判为 Illiquid
+	VolHigh   float64 // 窗口 realized vol 超过该值判为 Volatile
+	TrendR2   float64 // 价格对时间线性回归 R² 超过该值判为 Trending
+}
+
+var DefaultMarketStateConfig = MarketStateConfig{
+	MinTrades: 20,
+	VolHigh:   0.01,
+	TrendR2:   0.6,
+}
+
+// MarketState 分类结果及其底层得分
+type MarketState struct {
+	Kind        MarketStateKind
+	RealizedVol float64 // 窗口 realized vol（不年化）
+	Slope       float64 // 回归斜率 / 均价，单位：每秒收益率
+	R2          float64 // 回归拟合优度 [0,1]
+	RangePos    float64 // 最新价在窗口高低区间中的位置 [0,1]
+	Trades      int
+}
+
+// MarketState 使用默认阈值分类当前窗口的市场状态
+func (w *SlidingWindow) MarketState() (MarketState, bool) {
+	if !w.IsReady() {
+		return MarketState{}, false
+	}
+
+	return w.ClassifyMarketState(DefaultMarketStateConfig)
+}
+
+// ClassifyMarketState 综合 realized vol、趋势回归 R² 与区间位置给出 Trending/Ranging/Volatile/Illiquid（读锁）
+// 优先级：Illiquid > Volatile > Trending > Ranging
+//
+//metric:name=market_state unit=enum cost=O(n) min_points=2
+func (w *SlidingWindow) ClassifyMarketState(cfg MarketStateConfig) (MarketState, bool) {
+	if !w.IsReady() {
+		return MarketState{}, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	// 回归按存储点计算；流动性按成交笔数判断，游程合并与压缩后的点包含多笔
+	n := w.size
+	trades := int(w.nTrades.Load())
+	st := MarketState{Kind: MarketIlliquid, Trades: trades}
+	if n < 2 {
+		return st, false
+	}
+
+	rv, ok := w.realizedVolUnlocked()
+	if !ok {
+		return st, false
+	}
+	st.RealizedVol = rv
+
+	// 一次遍历：回归累加 + 高低点
+	t0 := w.atUnlocked(0).Ts
+	var sx, sy, sxx, sxy, syy float64
+	hi, lo := math.Inf(-1), math.Inf(1)
+	for i := 0; i < n; i++ {
+		pt := w.atUnlocked(i)
+		x := pt.Ts.Sub(t0).Seconds()
+		y := pt.Price.Float(w.priceScale)
+
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+		syy += y * y
+
+		hi = math.Max(hi, y)
+		lo = math.Min(lo, y)
+	}
+
+	fn := float64(n)
+	varX := sxx - sx*sx/fn
+	varY := syy - sy*sy/fn
+	covXY := sxy - sx*sy/fn
+	meanY := sy / fn
+
+	if varX > 0 && meanY > 0 {
+		st.Slope = covXY / varX / meanY
+		if varY > 0 {
+			st.R2 = covXY * covXY / (varX * varY)
+		}
+	}
+
+	if rng := hi - lo; rng > 0 {
+		st.RangePos = (w.lastUnlocked().Price.Float(w.priceScale) - lo) / rng
+	}
+
+	switch {
+	case trades < cfg.MinTrades || varX <= 0:
+		st.Kind = MarketIlliquid
+	case rv >= cfg.VolHigh:
+		st.Kind = MarketVolatile
+	case st.R2 >= cfg.TrendR2:
+		st.Kind = MarketTrending
+	default:
+		st.Kind = MarketRanging
+	}
+
+	return st, true
+}
@@ -0,0 +1,56 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarketState(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	build := func(n int, price func(i int) float64) *SlidingWindow {
+		w := NewSlidingWindow(time.Hour, 256, 0.1)
+		for i := 0; i < n; i++ {
+			w.AddWindowPoint(SideBuy, price(i), 1, t0.Add(time.Duration(i)*time.Second))
+		}
+		return w
+	}
+	alternate := func(step float64) func(int) float64 {
+		return func(i int) float64 { return 100 + step*float64(i%2) }
+	}
+
+	cases := []struct {
+		name  string
+		w     *SlidingWindow
+		want  MarketStateKind
+		check func(MarketState) bool
+	}{
+		{"trending", build(30, func(i int) float64 { return 100 + 0.01*float64(i) }), MarketTrending,
+			func(s MarketState) bool { return s.R2 > 0.99 && s.Slope > 0 && s.RangePos == 1 }},
+		{"ranging", build(30, alternate(0.02)), MarketRanging,
+			func(s MarketState) bool { return s.R2 < 0.1 }},
+		{"volatile", build(30, alternate(2)), MarketVolatile,
+			func(s MarketState) bool { return s.RealizedVol >= DefaultMarketStateConfig.VolHigh }},
+		{"illiquid", build(5, func(i int) float64 { return 100 + float64(i) }), MarketIlliquid,
+			func(s MarketState) bool { return s.Trades == 5 }},
+	}
+	for _, c := range cases {
+		st, ok := c.w.MarketState()
+		if !ok || st.Kind != c.want || !c.check(st) {
+			t.Errorf("%s: %+v ok=%v", c.name, st, ok)
+		}
+	}
+}
+
+func TestMarketState_CountsMergedTrades(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	w := NewSlidingWindow(time.Hour, 256, 0.1)
+	w.SetRunLength(time.Second)
+	// 30 笔成交，每 3 笔同价合并为一个点：10 个点，但成交笔数满足 MinTrades
+	for i := 0; i < 30; i++ {
+		w.AddWindowPoint(SideBuy, 100+0.01*float64(i/3), 1, t0.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	st, ok := w.MarketState()
+	if !ok || st.Trades != 30 || w.size != 10 || st.Kind != MarketTrending {
+		t.Fatalf("state = %+v ok=%v (points=%d)", st, ok, w.size)
+	}
+}
@@ -3,17 +3,36 @@ package sliding_window
 import "sort"
 
 // MedianPrice  对外带锁，锁内只复制，锁外排序计算
+//
+//metric:name=median_price unit=price cost=O(nlogn) min_points=2
 func (w *SlidingWindow) MedianPrice() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
 
+	if v, ok := w.histMedian(); ok {
+		return v, true
+	}
+	if w.metricTTLOn() {
+		return cachedMetric(w, "median_price", "median_price", w.computeMedianPrice)
+	}
+	return w.computeMedianPrice()
+}
+
+func (w *SlidingWindow) computeMedianPrice() (float64, bool) {
 	stats, ok := w.collectStats() // collectStats 内部把 prices 填满
 	if !ok {
 		return 0, false
 	}
+	defer w.releaseStats(&stats)
 
 	return w.medianPrice(stats)
 }
 
 func (w *SlidingWindow) medianPrice(stats WindowStats) (float64, bool) {
+	if v, ok := w.histMedian(); ok {
+		return v, true
+	}
 
 	// 直接对 prices 排序（它就是 stats.Prices）
 	sort.Float64s(stats.Prices)
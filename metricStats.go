@@ -0,0 +1,118 @@
+package sliding_window
+
+import (
+	"math"
+	"sync"
+)
+
+// defaultMetricHistory 派生指标历史的默认长度（次评估）
+const defaultMetricHistory = 64
+
+// historyNames MetricStats 支持的指标名
+var historyNames = [...]string{"imbalance", "vwap_distance", "momentum"}
+
+// metricHistory 最近 N 次评估的派生指标，每个新版本数据的首次 Snapshot 记录一次
+type metricHistory struct {
+	mu    sync.Mutex
+	n     int
+	next  int
+	count int
+	vals  [len(historyNames)][]float64
+}
+
+// MetricStats 某个派生指标在最近 N 次评估上的滚动统计
+type MetricStats struct {
+	N    int     `json:"n"`
+	Last float64 `json:"last"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	Std  float64 `json:"std"`
+	Z    float64 `json:"z"` // (Last - Mean) / Std，Std 为 0 时为 0
+}
+
+// SetMetricHistory 设置派生指标历史长度（n <= 0 时关闭记录），会清空已有历史
+func (w *SlidingWindow) SetMetricHistory(n int) {
+	h := &w.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.n = max(n, 0)
+	h.next, h.count = 0, 0
+	for i := range h.vals {
+		h.vals[i] = nil
+	}
+}
+
+// record 追加一次评估（要求不持有 h.mu）
+func (h *metricHistory) record(vals [len(historyNames)]float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.n <= 0 {
+		return
+	}
+	for i, v := range vals {
+		if len(h.vals[i]) != h.n {
+			h.vals[i] = make([]float64, h.n)
+		}
+		h.vals[i][h.next] = v
+	}
+	h.next = (h.next + 1) % h.n
+	h.count = min(h.count+1, h.n)
+}
+
+// MetricStats 派生指标（imbalance / vwap_distance / momentum）最近 N 次评估的
+// min/max/均值/标准差/z-score，便于在窗口内部完成信号归一化。
+// 历史在 Snapshot 评估新版本数据时记录；指标名未知或还没有历史时返回 false
+func (w *SlidingWindow) MetricStats(name string) (MetricStats, bool) {
+	idx := -1
+	for i, n := range historyNames {
+		if n == name {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return MetricStats{}, false
+	}
+
+	h := &w.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return MetricStats{}, false
+	}
+	ring := h.vals[idx]
+	last := ring[(h.next-1+h.n)%h.n]
+	st := MetricStats{N: h.count, Last: last, Min: last, Max: last}
+
+	var sum compensated
+	for i := 0; i < h.count; i++ {
+		v := ring[i]
+		st.Min = math.Min(st.Min, v)
+		st.Max = math.Max(st.Max, v)
+		sum.add(v)
+	}
+	st.Mean = sum.value() / float64(h.count)
+
+	var ss float64
+	for i := 0; i < h.count; i++ {
+		d := ring[i] - st.Mean
+		ss += d * d
+	}
+	st.Std = math.Sqrt(ss / float64(h.count))
+	if st.Std > 0 {
+		st.Z = (st.Last - st.Mean) / st.Std
+	}
+	return st, true
+}
+
+// recordHistory 记录一次派生指标评估
+func (w *SlidingWindow) recordHistory(d derivedMetrics) {
+	var dist float64
+	if latest := QtyLoz(d.core.latest).Float(w.priceScale); d.vwap > 0 {
+		dist = (latest - d.vwap) / d.vwap
+	}
+	w.history.record([len(historyNames)]float64{imbalanceOf(d.core.buy, d.core.sell), dist, d.momentum})
+}
@@ -0,0 +1,44 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMetricStats(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	w.SetMetricHistory(4)
+	t0 := time.Unix(1700000000, 0)
+
+	if _, ok := w.MetricStats("imbalance"); ok {
+		t.Fatal("no history yet")
+	}
+	for i := 0; i < 6; i++ {
+		side := SideBuy
+		if i%2 == 1 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Second))
+		w.Snapshot()
+		w.Snapshot() // 同一版本不重复记录
+	}
+
+	st, ok := w.MetricStats("imbalance")
+	if !ok || st.N != 4 {
+		t.Fatalf("stats: %+v ok=%v", st, ok)
+	}
+	// 最近 4 次评估的失衡：1/3, 0, 1/5, 0
+	if st.Last != 0 || st.Min != 0 || math.Abs(st.Max-1.0/3) > 1e-12 {
+		t.Fatalf("imbalance stats: %+v", st)
+	}
+	if st.Z >= 0 {
+		t.Fatalf("last below mean should give negative z, got %v", st.Z)
+	}
+	if _, ok := w.MetricStats("vwap_distance"); !ok {
+		t.Fatal("vwap_distance should be tracked")
+	}
+	if _, ok := w.MetricStats("nope"); ok {
+		t.Fatal("unknown metric")
+	}
+}
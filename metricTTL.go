@@ -0,0 +1,131 @@
+package sliding_window
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ttlMetrics 支持 SetMetricTTL 的指标（需要遍历或排序窗口的指标）
+var ttlMetrics = map[string]bool{
+	"vwap":             true,
+	"median_price":     true,
+	"realized_vol":     true,
+	"time_imbalance":   true,
+	"equilibrium_zone": true,
+}
+
+// metricTTLCache 按指标（及参数）缓存的计算结果，同一时刻只有一个读者在计算
+type metricTTLCache struct {
+	on      atomic.Bool // 任一指标设置了 TTL
+	mu      sync.Mutex
+	ttl     map[string]time.Duration
+	entries map[string]*ttlEntry
+}
+
+type ttlEntry struct {
+	at      time.Time
+	version uint64
+	val     any
+	ok      bool
+	valid   bool
+	done    chan struct{} // 非 nil 表示正在计算，其他读者等待它
+}
+
+// SetMetricTTL 为指标设置缓存有效期（无需持锁），ttl <= 0 取消缓存。
+// 有效期内（按窗口时钟）无论调用多频繁都直接返回上次结果，窗口没有新数据时也直接复用；
+// 过期后第一个读者重算，同时到达的其他读者等待并共享这次结果。
+// 支持的指标：vwap、median_price、realized_vol、time_imbalance、equilibrium_zone（按参数分别缓存）
+func (w *SlidingWindow) SetMetricTTL(metric string, ttl time.Duration) error {
+	if !ttlMetrics[metric] {
+		return fmt.Errorf("metric %q does not support ttl caching", metric)
+	}
+
+	c := &w.ttlCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl == nil {
+		c.ttl = make(map[string]time.Duration)
+		c.entries = make(map[string]*ttlEntry)
+	}
+	if ttl <= 0 {
+		delete(c.ttl, metric)
+	} else {
+		c.ttl[metric] = ttl
+	}
+	// 旧结果按新的有效期重新判断，简单起见全部作废
+	for k, e := range c.entries {
+		if e.done == nil {
+			delete(c.entries, k)
+		}
+	}
+	c.on.Store(len(c.ttl) > 0)
+	return nil
+}
+
+// MetricTTL 指标当前的缓存有效期，0 表示不缓存
+func (w *SlidingWindow) MetricTTL(metric string) time.Duration {
+	c := &w.ttlCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl[metric]
+}
+
+// metricTTLOn 是否有指标开启了 TTL 缓存（热路径上先判断，未开启时不构造闭包）
+func (w *SlidingWindow) metricTTLOn() bool {
+	return w.ttlCache.on.Load()
+}
+
+// cachedMetric 按 metric 的 TTL 返回 key 对应的缓存结果，必要时调用 compute（不要求持锁，compute 自行加锁）
+func cachedMetric[T any](w *SlidingWindow, metric, key string, compute func() (T, bool)) (T, bool) {
+	c := &w.ttlCache
+	for {
+		c.mu.Lock()
+		ttl := c.ttl[metric]
+		if ttl <= 0 {
+			c.mu.Unlock()
+			return compute()
+		}
+		e := c.entries[key]
+		if e == nil {
+			e = &ttlEntry{}
+			c.entries[key] = e
+		}
+		now, v := w.now(), w.version.Load()
+		if e.valid && (e.version == v || now.Sub(e.at) < ttl) {
+			val, ok := e.val.(T), e.ok
+			c.mu.Unlock()
+			return val, ok
+		}
+		if e.done != nil {
+			ch := e.done
+			c.mu.Unlock()
+			<-ch
+			continue
+		}
+		e.done = make(chan struct{})
+		c.mu.Unlock()
+
+		return fillTTLEntry(c, e, now, v, compute)
+	}
+}
+
+// fillTTLEntry 计算并写入缓存项，compute panic 时也会唤醒等待者
+func fillTTLEntry[T any](c *metricTTLCache, e *ttlEntry, now time.Time, v uint64, compute func() (T, bool)) (val T, ok bool) {
+	filled := false
+	defer func() {
+		c.mu.Lock()
+		if filled {
+			e.val, e.ok, e.at, e.version, e.valid = val, ok, now, v, true
+		}
+		close(e.done)
+		e.done = nil
+		c.mu.Unlock()
+	}()
+
+	val, ok = compute()
+	filled = true
+	return val, ok
+}
@@ -0,0 +1,57 @@
+package sliding_window
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetricTTL(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	clk := NewManualClock(t0)
+	w := New(time.Minute, 256, WithEMAAlpha(0.1), WithClock(clk))
+
+	if err := w.SetMetricTTL("sum_volume", time.Second); err == nil {
+		t.Fatal("sum_volume should not support ttl caching")
+	}
+	if err := w.SetMetricTTL("equilibrium_zone", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i%5), 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	z1, ok := w.EquilibriumZone(0.4, 0.5)
+	if !ok {
+		t.Fatal("zone should be computable")
+	}
+
+	// 有效期内：新数据不触发重算，并发读者拿到同一个结果
+	w.AddWindowPoint(SideBuy, 120, 5, t0.Add(20*time.Second))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if z, _ := w.EquilibriumZone(0.4, 0.5); z != z1 {
+				t.Errorf("zone recomputed within ttl: %+v", z)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 未开启 TTL 的指标照常实时计算
+	if v, _ := w.VolumeWeightedAveragePrice(); v <= 102 {
+		t.Fatalf("vwap should reflect the new trade: %v", v)
+	}
+
+	clk.Advance(60 * time.Millisecond)
+	if z, _ := w.EquilibriumZone(0.4, 0.5); z == z1 {
+		t.Fatal("zone should be recomputed after ttl")
+	}
+
+	w.SetMetricTTL("equilibrium_zone", 0)
+	if w.MetricTTL("equilibrium_zone") != 0 {
+		t.Fatal("ttl should be cleared")
+	}
+}
@@ -0,0 +1,55 @@
+// Code generated by internal/metricsgen; DO NOT EDIT.
+
+package sliding_window
+
+var metricRegistry = []MetricInfo{
+	{Name: "absorption", Unit: "score", Cost: "O(nlogn)", MinPoints: 2, Requires: "", Method: "AbsorptionDistribution", Doc: "用“成交分布偏移 + 量能放大 + 价格方向”识别吸筹/派发"},
+	{Name: "acceleration", Unit: "return/s2", Cost: "O(logn)", MinPoints: 3, Requires: "", Method: "Acceleration", Doc: "价格加速度：后半窗口速度 - 前半窗口速度，再除以半窗口秒数（每秒²收益率）。"},
+	{Name: "aggression_score", Unit: "score", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "AggressionScore", Doc: "主动性评分 [0,1]（读锁）：大单、成交加速、同向连续成交同时出现时接近 1。"},
+	{Name: "anchored_vwap", Unit: "price", Cost: "O(1)", MinPoints: 1, Requires: "anchor", Method: "AnchoredVWAP", Doc: "自锚点（AnchorVWAP / MarkSessionOpen）以来的 VWAP（读锁），未设锚点或尚无成交时返回 false"},
+	{Name: "arrival_stats", Unit: "trades/s", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "ArrivalStats", Doc: "成交到达率统计（读锁），用于发现喂价异常或刷单式爆发"},
+	{Name: "avg_volume_per_point", Unit: "volume", Cost: "O(1)", MinPoints: 1, Requires: "", Method: "AvgVolumePerPoint", Doc: "Window 内每笔成交的平均成交量（不是时间归一化的；游程等聚合点按所含笔数计）"},
+	{Name: "blended_price", Unit: "price", Cost: "O(1)", MinPoints: 1, Requires: "", Method: "BlendedPrice", Doc: "最新混合价格（读锁），未开启混合时返回 false"},
+	{Name: "breakout_strength", Unit: "price", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "BreakoutStrength", Doc: "最新价相对窗口内（除最新点外）高低点的突破强度"},
+	{Name: "clock_skew", Unit: "duration", Cost: "O(1)", MinPoints: 2, Requires: "recv_ts", Method: "ClockSkew", Doc: "估计交易所时钟与本地接收时钟的偏差和漂移（读锁），只统计经 AddTrades 写入、带 RecvTs 且仍在窗口内的成交"},
+	{Name: "delta_acceleration", Unit: "volume/s2", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "DeltaAcceleration", Doc: "主动买卖差的加速度（读锁）：把 CVD 按桶求导得到每秒买卖差，再对最近几个桶求斜率。"},
+	{Name: "delta_volume", Unit: "volume", Cost: "O(1)", MinPoints: 0, Requires: "", Method: "DeltaVolume", Doc: "buy - sell （单位：成交量，已经除以 volumeScale 后的真实值）"},
+	{Name: "equilibrium_zone", Unit: "price", Cost: "O(nlogn)", MinPoints: 2, Requires: "", Method: "EquilibriumZone", Doc: "VWAP 与中位数加权得到均衡价及其通道"},
+	{Name: "execution_pressure", Unit: "ratio", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "ExecutionPressure", Doc: "(VWAP − TWAP) / TWAP（读锁），两者都来自增量累加器，O(1)。"},
+	{Name: "high_low", Unit: "price", Cost: "O(n)", MinPoints: 1, Requires: "", Method: "HighLow", Doc: "窗口内最高价 / 最低价"},
+	{Name: "high_low_detail", Unit: "price", Cost: "O(n)", MinPoints: 1, Requires: "", Method: "HighLowDetail", Doc: "窗口最高价 / 最低价及其创出时间和距今时长（读锁）。"},
+	{Name: "imbalance", Unit: "ratio", Cost: "O(1)", MinPoints: 0, Requires: "", Method: "Imbalance", Doc: "(buy - sell) / (buy + sell)，范围 [-1, 1]"},
+	{Name: "imbalance_ex_own", Unit: "ratio", Cost: "O(k)", MinPoints: 1, Requires: "own_fills", Method: "ImbalanceExOwn", Doc: "扣除自有成交后的 (buy - sell) / (buy + sell)（读锁），扣除后没有成交量时返回 false"},
+	{Name: "imbalance_horizons", Unit: "ratio", Cost: "O(n)", MinPoints: 1, Requires: "", Method: "ImbalanceHorizons", Doc: "一次遍历计算多个时间尺度的订单流不平衡 (buy-sell)/(buy+sell)（读锁）。"},
+	{Name: "jumps", Unit: "return", Cost: "O(n)", MinPoints: 3, Requires: "", Method: "Jumps", Doc: "相邻成交价格跳跃统计（读锁）：最大跳跃（绝对值与波动率单位）、超过 threshold 个"},
+	{Name: "last_trade_percentile", Unit: "ratio", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "LastTradePercentile", Doc: "最近一笔成交的量相对（进窗前）窗口分布的百分位（读锁）"},
+	{Name: "market_state", Unit: "enum", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "ClassifyMarketState", Doc: "综合 realized vol、趋势回归 R² 与区间位置给出 Trending/Ranging/Volatile/Illiquid（读锁）"},
+	{Name: "max_drawdown", Unit: "return", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "MaxDrawdown", Doc: "窗口价格路径上的最大回撤（峰值到其后谷值的最大跌幅）"},
+	{Name: "max_run_up", Unit: "return", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "MaxRunUp", Doc: "窗口价格路径上的最大上冲（谷值到其后峰值的最大涨幅）"},
+	{Name: "median_price", Unit: "price", Cost: "O(nlogn)", MinPoints: 2, Requires: "", Method: "MedianPrice", Doc: "对外带锁，锁内只复制，锁外排序计算"},
+	{Name: "momentum", Unit: "return", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "Momentum", Doc: "计算简单“价格 + 量能”动能因子 avgVolume 建议用 EMA.Value 作为参考平均成交量"},
+	{Name: "momentum_level", Unit: "enum", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "ClassifyMomentum", Doc: "根据阈值分级"},
+	{Name: "momentum_score", Unit: "score", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "ScoreWithMomentum", Doc: "计算价格趋势 + 动量 + 订单流贝叶斯置信后的综合得分。"},
+	{Name: "own_participation", Unit: "ratio", Cost: "O(k)", MinPoints: 1, Requires: "own_fills", Method: "OwnParticipation", Doc: "自有成交占窗口成交量的比例（读锁）"},
+	{Name: "price_quantile", Unit: "price", Cost: "O(k)", MinPoints: 1, Requires: "", Method: "QuantileEstimate", Doc: "价格分位数估计，q ∈ [0,1]（读锁）。"},
+	{Name: "price_quantile_approx", Unit: "price", Cost: "O(blogb)", MinPoints: 1, Requires: "", Method: "PriceQuantileApprox", Doc: "由价格直方图给出的价格分位数，q ∈ [0,1]（读锁），未开启直方图时返回 false"},
+	{Name: "realized_vol", Unit: "return", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "RealizedVol", Doc: "sqrt(sum(log(p_i/p_{i-1})^2))，窗口内 realized vol（不年化）"},
+	{Name: "realized_vol_estimate", Unit: "return", Cost: "O(k)", MinPoints: 2, Requires: "", Method: "RealizedVolEstimate", Doc: "realized vol 估计（读锁）。"},
+	{Name: "realized_vol_fixed", Unit: "return", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "RealizedVolFixed", Doc: "定点版 realized vol（读锁），用于审计复现。"},
+	{Name: "rvol", Unit: "ratio", Cost: "O(1)", MinPoints: 1, Requires: "historical_profile", Method: "RVOL", Doc: "相对成交量：当前窗口成交量 / 历史同一时段同样时长的平均成交量（读锁）。"},
+	{Name: "side_vwap_horizons", Unit: "price", Cost: "O(n)", MinPoints: 1, Requires: "", Method: "SideVWAPHorizons", Doc: "一次遍历计算多个时间尺度的买卖分方向 VWAP（读锁），"},
+	{Name: "size_quantile", Unit: "volume", Cost: "O(1)", MinPoints: 1, Requires: "", Method: "SizeQuantile", Doc: "窗口内单笔成交量的 q 分位（读锁，直方图近似，相对误差约 19%）"},
+	{Name: "sum_volume", Unit: "volume", Cost: "O(1)", MinPoints: 0, Requires: "", Method: "SumVolume", Doc: "返回当前窗口内成交量总和（读锁）"},
+	{Name: "sweep", Unit: "return", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "SweepStats", Doc: "识别窗口内的分笔成交 burst，统计首末成交价差（扫单滑移）和量价偏离（读锁）"},
+	{Name: "time_imbalance", Unit: "ratio", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "TimeWeightedImbalance", Doc: "时间加权主动方向失衡（读锁），范围 [-1, 1]。"},
+	{Name: "time_to_revert", Unit: "duration", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "TimeToRevert", Doc: "从窗口历史估计价格偏离均衡价 level（比例，比如 0.002 即 0.2%）之后，"},
+	{Name: "twap", Unit: "price", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "TWAP", Doc: "时间加权均价（读锁）：每个价格持续到下一笔成交，只计开市时长"},
+	{Name: "velocity", Unit: "return/s", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "Velocity", Doc: "价格变化速度：窗口收益率 / 窗口跨度秒数（读锁）"},
+	{Name: "vol_regime_factor", Unit: "ratio", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "VolRegimeFactor", Doc: "当前 realized vol / 跨快照基准，类似 VolumeFactor 之于成交量："},
+	{Name: "volume_factor", Unit: "ratio", Cost: "O(1)", MinPoints: 1, Requires: "", Method: "VolumeFactor", Doc: "带锁计算交易量基准"},
+	{Name: "volume_factor_ex_own", Unit: "ratio", Cost: "O(k)", MinPoints: 1, Requires: "own_fills", Method: "VolumeFactorExOwn", Doc: "扣除自有成交（成交量与笔数）后的每笔均量 / 成交量 EMA 基准（读锁）。"},
+	{Name: "volume_per_second", Unit: "volume/s", Cost: "O(1)", MinPoints: 2, Requires: "", Method: "VolumePerSecond", Doc: "按时间归一化的成交量（每秒多少量）"},
+	{Name: "vwap", Unit: "price", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "VolumeWeightedAveragePrice", Doc: "计算VWAP价格（复用窗口快照）"},
+	{Name: "vwap_divergence", Unit: "sigma", Cost: "O(n)", MinPoints: 2, Requires: "anchor", Method: "VWAPDivergence", Doc: "(窗口 VWAP - 锚定 VWAP) / 窗口内成交量加权价格标准差（读锁）。"},
+	{Name: "vwap_reversion", Unit: "signal", Cost: "O(n)", MinPoints: 2, Requires: "", Method: "VWAPReversion", Doc: "价格偏离 VWAP 超过 k 个 VWAP-σ 后开始回落（短周期收益率反向）时给出一次入场信号。"},
+}
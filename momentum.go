@@ -5,12 +5,22 @@ import (
 )
 
 // Momentum 计算简单“价格 + 量能”动能因子 avgVolume 建议用 EMA.Value 作为参考平均成交量
+//
+//metric:name=momentum unit=return cost=O(1) min_points=2
 func (w *SlidingWindow) Momentum() (momentum float64, ok bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
 
 	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.momentumUnlocked()
+}
+
+func (w *SlidingWindow) momentumUnlocked() (momentum float64, ok bool) {
 	vf, ok1 := w.volumeFactor()
 	ret, ok2 := w.structuralReturn()
-	w.mu.RUnlock()
 
 	if ok1 && ok2 {
 		// 动能 = 收益率 * log(1 + volFactor)
@@ -23,6 +33,8 @@ func (w *SlidingWindow) Momentum() (momentum float64, ok bool) {
 }
 
 // ClassifyMomentum 根据阈值分级
+//
+//metric:name=momentum_level unit=enum cost=O(1) min_points=2
 func (w *SlidingWindow) ClassifyMomentum(avgVolume, weak, strong float64) (MomentumSignal, bool) {
 	var empty MomentumSignal
 	if avgVolume <= 0 {
@@ -32,6 +44,32 @@ func (w *SlidingWindow) ClassifyMomentum(avgVolume, weak, strong float64) (Momen
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	return w.classifyMomentumUnlocked(avgVolume, weak, strong)
+}
+
+// ClassifyMomentumAuto 自适应阈值分级：weak/strong 阈值 = 倍数 × 窗口 realized vol，
+// 同一套参数可同时用于高波动（BTC）和低波动品种
+func (w *SlidingWindow) ClassifyMomentumAuto(avgVolume, weakMult, strongMult float64) (MomentumSignal, bool) {
+	var empty MomentumSignal
+	if avgVolume <= 0 {
+		return empty, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	rv, ok := w.realizedVolUnlocked()
+	if !ok || rv <= 0 {
+		return empty, false
+	}
+
+	return w.classifyMomentumUnlocked(avgVolume, weakMult*rv, strongMult*rv)
+}
+
+// classifyMomentumUnlocked 无锁版分级（要求调用方已持有 RLock 或 Lock）
+func (w *SlidingWindow) classifyMomentumUnlocked(avgVolume, weak, strong float64) (MomentumSignal, bool) {
+	var empty MomentumSignal
+
 	if w.size < 2 {
 		return empty, false
 	}
@@ -72,6 +110,8 @@ func (w *SlidingWindow) ClassifyMomentum(avgVolume, weak, strong float64) (Momen
 		Value:     val,
 		Ret:       ret,
 		VolFactor: volFactor,
+		Weak:      weak,
+		Strong:    strong,
 	}, true
 }
 
@@ -90,4 +130,6 @@ type MomentumSignal struct {
 	Value     float64 // 原始动能值
 	Ret       float64 // 窗口价格收益率
 	VolFactor float64 // 成交量放大倍数
+	Weak      float64 // 实际使用的弱阈值
+	Strong    float64 // 实际使用的强阈值
 }
@@ -0,0 +1,52 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClassifyMomentumAuto(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	build := func(noise float64) *SlidingWindow {
+		w := NewSlidingWindow(time.Minute, 64, 0.1)
+		for i := 0; i <= 20; i++ {
+			px := 100 + 0.05*float64(i)
+			if i > 0 && i < 20 {
+				px += noise * float64(1-2*(i%2))
+			}
+			w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*time.Second))
+		}
+		return w
+	}
+
+	// 首尾相同、涨幅相同，只有路径波动不同
+	calm, choppy := build(0), build(2)
+	for _, c := range []struct {
+		name string
+		w    *SlidingWindow
+		want MomentumLevel
+	}{
+		{"calm", calm, MomentumStrongUp},
+		{"choppy", choppy, MomentumNeutral},
+	} {
+		sig, ok := c.w.ClassifyMomentumAuto(21, 1, 2)
+		if !ok || sig.Level != c.want {
+			t.Fatalf("%s: %+v ok=%v", c.name, sig, ok)
+		}
+		rv, _ := c.w.RealizedVol()
+		if math.Abs(sig.Weak-rv) > 1e-12 || math.Abs(sig.Strong-2*rv) > 1e-12 {
+			t.Fatalf("%s: thresholds %v/%v, want %v/%v", c.name, sig.Weak, sig.Strong, rv, 2*rv)
+		}
+	}
+
+	// 固定阈值下两者分级相同
+	a, _ := calm.ClassifyMomentum(21, 0.001, 0.002)
+	b, _ := choppy.ClassifyMomentum(21, 0.001, 0.002)
+	if a.Level != b.Level {
+		t.Fatalf("fixed thresholds: %v vs %v", a.Level, b.Level)
+	}
+	if _, ok := calm.ClassifyMomentumAuto(0, 1, 2); ok {
+		t.Fatal("avgVolume <= 0 should be rejected")
+	}
+}
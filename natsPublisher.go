@@ -0,0 +1,185 @@
+package sliding_window
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSPublisher 直接使用 NATS 文本协议发布事件（不依赖客户端库）。
+// 主题为 <Subject>.<kind>.<symbol>，如 sliding_window.snapshot.BTCUSDT；
+// 每批末尾发送 PING 并等待 PONG，确认服务端已处理整批。连接在首次发布时建立；
+// 复用的连接写入或往返出错时断开并立即重连重发一次（此时该批可能部分重复），新连接出错则返回错误，下次发布重连
+type NATSPublisher struct {
+	Addr       string        // host:port
+	Subject    string        // 主题前缀，默认 "sliding_window"
+	Timeout    time.Duration // 连接与单批往返超时，默认 5s（ctx 的截止时间更早时以 ctx 为准）
+	User, Pass string        // 可选用户名密码认证
+	Token      string        // 可选 token 认证
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+	wr   *bufio.Writer
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, events []Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reused := p.conn != nil
+	err := p.publishLocked(ctx, events)
+	if err != nil && reused && ctx.Err() == nil {
+		// 复用的连接可能已被服务端或网络断开，重连后重发
+		p.closeLocked()
+		err = p.publishLocked(ctx, events)
+	}
+	if err != nil {
+		p.closeLocked()
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	return nil
+}
+
+func (p *NATSPublisher) publishLocked(ctx context.Context, events []Event) error {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if p.conn == nil {
+		if err := p.connectLocked(ctx, deadline); err != nil {
+			return err
+		}
+	}
+	if err := p.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	prefix := p.Subject
+	if prefix == "" {
+		prefix = "sliding_window"
+	}
+	for i := range events {
+		payload, err := json.Marshal(&events[i])
+		if err != nil {
+			return err
+		}
+		subj := prefix + "." + string(events[i].Kind) + "." + natsToken(events[i].Symbol)
+		if _, err := fmt.Fprintf(p.wr, "PUB %s %d\r\n", subj, len(payload)); err != nil {
+			return err
+		}
+		if _, err := p.wr.Write(payload); err != nil {
+			return err
+		}
+		if _, err := p.wr.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	if _, err := p.wr.WriteString("PING\r\n"); err != nil {
+		return err
+	}
+	if err := p.wr.Flush(); err != nil {
+		return err
+	}
+	return p.awaitPongLocked()
+}
+
+func (p *NATSPublisher) connectLocked(ctx context.Context, deadline time.Time) error {
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return err
+	}
+	p.conn, p.rd, p.wr = conn, bufio.NewReader(conn), bufio.NewWriter(conn)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// 服务端先发 INFO
+	line, err := p.rd.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+
+	opts := map[string]any{"verbose": false, "pedantic": false, "name": "sliding_window", "lang": "go"}
+	if p.User != "" {
+		opts["user"] = p.User
+		opts["pass"] = p.Pass
+	}
+	if p.Token != "" {
+		opts["auth_token"] = p.Token
+	}
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(p.wr, "CONNECT %s\r\n", b)
+	return err
+}
+
+// awaitPongLocked 读到 PONG 为止；期间服务端的 PING 需要回应，-ERR 视为失败
+func (p *NATSPublisher) awaitPongLocked() error {
+	for {
+		line, err := p.rd.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.wr.WriteString("PONG\r\n"); err != nil {
+				return err
+			}
+			if err := p.wr.Flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func (p *NATSPublisher) closeLocked() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.rd, p.wr = nil, nil, nil
+	}
+}
+
+// Close 断开连接
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeLocked()
+	return nil
+}
+
+// natsToken 主题中的一段不能包含空白和 '.'
+func natsToken(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ' ', '\t', '\r', '\n', '*', '>':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,107 @@
+package sliding_window
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeNATS 只实现 INFO / CONNECT / PUB / PING 的最小 NATS 服务端；
+// dropAfterPong 为 true 时每个连接回完第一个 PONG 就断开，模拟连接被服务端关闭
+func fakeNATS(t *testing.T, got chan<- string, dropAfterPong bool) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		rd := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		for {
+			line, err := rd.ReadString('\n')
+			if err != nil {
+				return
+			}
+			f := strings.Fields(line)
+			switch {
+			case len(f) == 0:
+			case f[0] == "CONNECT":
+				got <- strings.TrimSpace(line)
+			case f[0] == "PING":
+				conn.Write([]byte("PONG\r\n"))
+				if dropAfterPong {
+					return
+				}
+			case f[0] == "PUB":
+				n, _ := strconv.Atoi(f[len(f)-1])
+				payload := make([]byte, n+2)
+				io.ReadFull(rd, payload)
+				got <- f[1] + " " + string(payload[:n])
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestNATSPublisher(t *testing.T) {
+	got := make(chan string, 4)
+	p := &NATSPublisher{Addr: fakeNATS(t, got, false), Subject: "md", User: "u", Pass: "secret"}
+	defer p.Close()
+
+	err := p.Publish(context.Background(), []Event{
+		{Kind: EventSnapshot, Symbol: "BTC.PERP", Snapshot: &Snapshot{LatestPrice: 42}},
+		{Kind: EventSignal, Symbol: "ETH", Signal: &SignalEvent{Name: "breakout", Dir: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connect, first, second := <-got, <-got, <-got
+	if !strings.Contains(connect, `"user":"u"`) || !strings.Contains(connect, `"pass":"secret"`) {
+		t.Fatalf("connect: %s", connect)
+	}
+	if !strings.HasPrefix(first, "md.snapshot.BTC_PERP {") || !strings.Contains(first, `"latest_price":42`) {
+		t.Fatalf("first: %s", first)
+	}
+	if !strings.HasPrefix(second, "md.signal.ETH {") {
+		t.Fatalf("second: %s", second)
+	}
+}
+
+func TestNATSPublisher_ReconnectsBrokenConnection(t *testing.T) {
+	got := make(chan string, 8)
+	p := &NATSPublisher{Addr: fakeNATS(t, got, true)}
+	defer p.Close()
+
+	batch := []Event{{Kind: EventSnapshot, Symbol: "BTC", Snapshot: &Snapshot{LatestPrice: 1}}}
+	if err := p.Publish(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+	<-got // CONNECT
+	<-got // PUB
+
+	// 服务端已断开第一个连接：写入或等待 PONG 失败后重连重发，而不是静默丢掉这一批
+	if err := p.Publish(context.Background(), batch); err != nil {
+		t.Fatalf("publish after server drop: %v", err)
+	}
+	if connect := <-got; !strings.HasPrefix(connect, "CONNECT") {
+		t.Fatalf("expected a reconnect, got %s", connect)
+	}
+	if pub := <-got; !strings.HasPrefix(pub, "sliding_window.snapshot.BTC {") {
+		t.Fatalf("republished: %s", pub)
+	}
+}
@@ -0,0 +1,117 @@
+package sliding_window
+
+// NegativeVolumePolicy 负成交量点（交易所推送的成交更正 / 撤销）的处理方式
+type NegativeVolumePolicy uint8
+
+const (
+	// NegativeVolumeClamp 负成交量按 0 计入（默认，兼容旧行为）
+	NegativeVolumeClamp NegativeVolumePolicy = iota
+	// NegativeVolumeReject 丢弃负成交量点，计入 Counters().NegativeRejects
+	NegativeVolumeReject
+	// NegativeVolumeCorrect 视为对窗口内同一 TradeID 成交的更正：从原成交中扣减 |volume|（最多扣到 0），
+	// 价格、时间不变；只有开启本策略后经 AddTrades 写入的成交能被更正，
+	// TradeID 为空或原成交已不在窗口内时按 NegativeVolumeReject 处理
+	NegativeVolumeCorrect
+)
+
+func (p NegativeVolumePolicy) String() string {
+	switch p {
+	case NegativeVolumeReject:
+		return "reject"
+	case NegativeVolumeCorrect:
+		return "correct"
+	default:
+		return "clamp"
+	}
+}
+
+// negativeVolume 负成交量处理配置与复用缓冲
+type negativeVolume struct {
+	policy NegativeVolumePolicy
+	out    []WindowPoint // 过滤后点的复用缓冲
+	trades tradeIDSet    // NegativeVolumeCorrect 下记住的成交，按 TradeID 定位原成交
+}
+
+// SetNegativeVolumePolicy 设置负成交量点的处理方式（写锁），只影响之后写入的点
+func (w *SlidingWindow) SetNegativeVolumePolicy(p NegativeVolumePolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.negative.policy = p
+	switch {
+	case p != NegativeVolumeCorrect:
+		w.negative.trades = tradeIDSet{}
+	case w.negative.trades.seen == nil:
+		w.negative.trades = newTradeIDSet(0)
+	}
+}
+
+// negativeVolumeUnlocked 按策略拦下负成交量点（要求持有写锁）。点不带 TradeID，
+// NegativeVolumeCorrect 下同样只能拒收（更正走 addTradesUnlocked）。
+// 没有负成交量点时原样返回 pts；否则返回内部复用缓冲，在下一次调用前有效
+func (w *SlidingWindow) negativeVolumeUnlocked(pts []WindowPoint) []WindowPoint {
+	n := &w.negative
+	var out []WindowPoint
+	filtered := false // 遇到第一个负成交量点后才拷贝到 out
+	for i, pt := range pts {
+		if pt.Volume >= 0 {
+			if filtered {
+				out = append(out, pt)
+			}
+			continue
+		}
+		if !filtered {
+			out, filtered = append(n.out[:0], pts[:i]...), true
+		}
+		w.counters.negativeRejects.Add(1)
+	}
+	if !filtered {
+		return pts
+	}
+	n.out = out
+	return out
+}
+
+// correctTradeUnlocked 从窗口内同 TradeID 的成交中扣减 |tr.Volume|，找到返回 true（要求持有写锁）。
+// 按记住的时间与方向定位原成交所在的点，同一时间有多个点时优先取价格相同的
+func (w *SlidingWindow) correctTradeUnlocked(tr Trade) bool {
+	ref, ok := w.negative.trades.seen[tr.TradeID]
+	if tr.TradeID == "" || !ok {
+		return false
+	}
+	at := -1
+	for i := w.size - 1; i >= 0; i-- {
+		p := w.atUnlocked(i)
+		if p.Ts.Before(ref.ts) {
+			break
+		}
+		if !p.Ts.Equal(ref.ts) || p.Side != ref.side || p.Volume <= 0 {
+			continue
+		}
+		at = i
+		if p.Price == ref.price {
+			break
+		}
+	}
+
+	if at < 0 {
+		return false
+	}
+
+	old := w.atUnlocked(at)
+	fixed := old
+	fixed.Volume = max(old.Volume+tr.Volume, 0)
+	w.applyRemovePointUnlocked(old)
+	w.setUnlocked(at, fixed)
+	w.applyAddPointUnlocked(fixed)
+	// applyAdd 会把被更正点的价格记为最新价
+	w.LatestPrice.Store(w.lastUnlocked().Price.Int64())
+	w.hiLoDirty = true
+
+	if a := &w.anchored; a.active && !old.Ts.Before(a.anchor) && !w.isQuoteUnlocked(old) {
+		dv := (old.Volume - fixed.Volume).Float(w.volumeScale)
+		a.sumPV.add(-old.Price.Float(w.priceScale) * dv)
+		a.sumV.add(-dv)
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNegativeVolumePolicy(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	pt := func(id string, px, v float64, ts time.Time) Trade {
+		return Trade{
+			WindowPoint: WindowPoint{
+				Ts:     ts,
+				Price:  NewQtyLoz(px, w.priceScale),
+				Volume: NewQtyLoz(v, w.volumeScale),
+				Side:   SideBuy,
+			},
+			TradeMeta: TradeMeta{TradeID: id},
+		}
+	}
+
+	w.SetNegativeVolumePolicy(NegativeVolumeReject)
+	w.AddTrades(pt("a", 100, 2, t0), pt("a", 100, -1, t0.Add(time.Second)))
+	if w.size != 1 || w.Counters().NegativeRejects != 1 {
+		t.Fatalf("reject: len=%d counters=%+v", w.size, w.Counters())
+	}
+
+	w.SetNegativeVolumePolicy(NegativeVolumeCorrect)
+	w.AddTrades(pt("c", 100, 2, t0.Add(1500*time.Millisecond)))
+	w.AddTrades(pt("b", 102, 3, t0.Add(2*time.Second)))
+	w.AddTrades(pt("c", 100, -1.5, t0.Add(3*time.Second)))
+	if got := w.SumVolume(); got != 5.5 {
+		t.Fatalf("sum volume after correction = %v, want 5.5", got)
+	}
+	if px := QtyLoz(w.LatestPrice.Load()).Float(w.priceScale); px != 102 {
+		t.Fatalf("correction must not move latest price, got %v", px)
+	}
+
+	// 找不到原成交（包括开启更正前写入的 "a"）的更正按拒绝计数；超额撤销最多扣到 0
+	w.AddTrades(pt("zz", 100, -1, t0.Add(4*time.Second)), pt("a", 100, -1, t0.Add(4*time.Second)), pt("b", 102, -5, t0.Add(4*time.Second)))
+	c := w.Counters()
+	if c.Corrections != 2 || c.NegativeRejects != 3 {
+		t.Fatalf("counters = %+v", c)
+	}
+	if got := w.SumVolume(); got != 2.5 {
+		t.Fatalf("sum volume = %v, want 2.5", got)
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("check: %v", issues)
+	}
+	if w.Config().NegativeVolume != "correct" {
+		t.Fatal("config should report policy")
+	}
+}
@@ -0,0 +1,98 @@
+package sliding_window
+
+import "time"
+
+// openingRangeState 开盘区间的增量状态：独立于窗口长度，窗口比区间短时也能得到完整结果
+type openingRangeState struct {
+	active     bool
+	open       time.Time
+	end        time.Time // 区间结束（不含）
+	high, low  int64     // ticks
+	seen       bool      // 区间内是否已有成交
+	breakoutTs time.Time
+	breakout   int // 首次突破方向：1 向上，-1 向下
+}
+
+// OpeningRange 开盘区间（initial balance）分析结果
+type OpeningRange struct {
+	Open     time.Time `json:"open"`
+	End      time.Time `json:"end"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Complete bool      `json:"complete"` // 区间是否已结束
+	// Position 最新价相对区间：1 在上方，-1 在下方，0 在区间内
+	Position   int       `json:"position"`
+	BreakoutTs time.Time `json:"breakout_ts,omitempty"` // 区间结束后首次突破的时间
+	Breakout   int       `json:"breakout"`              // 首次突破方向：1 向上，-1 向下，0 尚未突破
+}
+
+// MarkSessionOpen 标记交易时段开盘（写锁），rangeDur 为开盘区间长度（如 30 分钟）。
+// 之后 [ts, ts+rangeDur) 内的成交构成区间高低点，区间结束后记录首次突破；再次调用会重置。
+// 同时以 ts 重新锚定 AnchoredVWAP。
+func (w *SlidingWindow) MarkSessionOpen(ts time.Time, rangeDur time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.openRange = openingRangeState{active: rangeDur > 0, open: ts, end: ts.Add(rangeDur)}
+	w.anchorVWAPUnlocked(ts)
+}
+
+// observeOpeningRangeUnlocked 成交进窗时更新开盘区间（要求持有写锁）
+func (w *SlidingWindow) observeOpeningRangeUnlocked(pt WindowPoint) {
+	r := &w.openRange
+	if !r.active || pt.Ts.Before(r.open) {
+		return
+	}
+	px := pt.Price.Int64()
+
+	if pt.Ts.Before(r.end) {
+		if !r.seen {
+			r.high, r.low, r.seen = px, px, true
+			return
+		}
+		r.high = max(r.high, px)
+		r.low = min(r.low, px)
+		return
+	}
+
+	if r.breakout != 0 || !r.seen {
+		return
+	}
+	switch {
+	case px > r.high:
+		r.breakout, r.breakoutTs = 1, pt.Ts
+	case px < r.low:
+		r.breakout, r.breakoutTs = -1, pt.Ts
+	}
+}
+
+// OpeningRange 当前时段的开盘区间（读锁）；未标记开盘或区间内还没有成交时返回 false
+func (w *SlidingWindow) OpeningRange() (OpeningRange, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	r := w.openRange
+	if !r.active || !r.seen {
+		return OpeningRange{}, false
+	}
+
+	out := OpeningRange{
+		Open:       r.open,
+		End:        r.end,
+		High:       QtyLoz(r.high).Float(w.priceScale),
+		Low:        QtyLoz(r.low).Float(w.priceScale),
+		BreakoutTs: r.breakoutTs,
+		Breakout:   r.breakout,
+	}
+	if w.size > 0 {
+		last := w.lastUnlocked()
+		out.Complete = !last.Ts.Before(r.end)
+		switch px := last.Price.Int64(); {
+		case px > r.high:
+			out.Position = 1
+		case px < r.low:
+			out.Position = -1
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,35 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpeningRange(t *testing.T) {
+	// 窗口只有 1 分钟，比 5 分钟的开盘区间短
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	open := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	w.MarkSessionOpen(open, 5*time.Minute)
+
+	for i := 0; i < 300; i++ {
+		px := 100.0
+		switch i {
+		case 10:
+			px = 104
+		case 200:
+			px = 97
+		}
+		w.AddWindowPoint(SideBuy, px, 1, open.Add(time.Duration(i)*time.Second))
+	}
+	r, ok := w.OpeningRange()
+	if !ok || r.High != 104 || r.Low != 97 || r.Complete || r.Breakout != 0 {
+		t.Fatalf("in range: %+v (ok=%v)", r, ok)
+	}
+
+	w.AddWindowPoint(SideSell, 96, 1, open.Add(6*time.Minute))
+	w.AddWindowPoint(SideBuy, 105, 1, open.Add(7*time.Minute))
+	r, _ = w.OpeningRange()
+	if !r.Complete || r.Breakout != -1 || !r.BreakoutTs.Equal(open.Add(6*time.Minute)) || r.Position != 1 {
+		t.Fatalf("after range: %+v", r)
+	}
+}
@@ -0,0 +1,80 @@
+package sliding_window
+
+// Option New 的可选配置
+type Option func(*windowOptions)
+
+type windowOptions struct {
+	emaAlpha       float64
+	priceDecimals  int
+	volumeDecimals int
+	evict          EvictionPolicy
+	maxPoints      int
+	segmentSize    int // > 0 表示使用分段存储
+	tracked        TrackedMetrics
+	poolCap        int
+	clock          Clock
+	tags           map[string]string
+	evictHook      func(WindowPoint)
+}
+
+func defaultWindowOptions() windowOptions {
+	return windowOptions{
+		priceDecimals:  4,
+		volumeDecimals: 8,
+		tracked:        TrackAll,
+		poolCap:        256,
+	}
+}
+
+// WithEMAAlpha 成交量 EMA 基准的 alpha，不设置时取 NewEMA 的默认值
+func WithEMAAlpha(alpha float64) Option {
+	return func(o *windowOptions) { o.emaAlpha = alpha }
+}
+
+// WithPriceDecimals 价格的定点小数位数，默认 4（取值范围 1~18）
+func WithPriceDecimals(decimals int) Option {
+	return func(o *windowOptions) { o.priceDecimals = decimals }
+}
+
+// WithVolumeDecimals 成交量的定点小数位数，默认 8（取值范围 1~18）
+func WithVolumeDecimals(decimals int) Option {
+	return func(o *windowOptions) { o.volumeDecimals = decimals }
+}
+
+// WithEvictionPolicy 出窗方式，maxPoints 为 EvictByCount / EvictHybrid 的点数上限。
+// EvictByCount 且 capacity <= 0 时容量取 maxPoints
+func WithEvictionPolicy(policy EvictionPolicy, maxPoints int) Option {
+	return func(o *windowOptions) {
+		o.evict = policy
+		o.maxPoints = 0
+		if policy != EvictByTime {
+			o.maxPoints = maxPoints
+		}
+	}
+}
+
+// WithSegments 使用分段存储（见 NewSlidingWindowSegmented），此时忽略 capacity；segmentSize <= 0 时取 1024
+func WithSegments(segmentSize int) Option {
+	return func(o *windowOptions) {
+		if segmentSize <= 0 {
+			segmentSize = 1024
+		}
+		o.segmentSize = segmentSize
+	}
+}
+
+// WithTracked 只维护 tracked 中列出的增量结构（见 NewSlidingWindowTracked），默认 TrackAll
+func WithTracked(tracked TrackedMetrics) Option {
+	return func(o *windowOptions) { o.tracked = tracked }
+}
+
+// WithPricePoolCapacity 中位数 / 均衡区计算所用价格缓冲池的初始容量，默认 256，
+// 窗口点数通常远大于默认值时设为预期点数可以减少扩容
+func WithPricePoolCapacity(n int) Option {
+	return func(o *windowOptions) { o.poolCap = max(n, 0) }
+}
+
+// WithClock 窗口的时间来源（见 SetClock），默认系统时钟
+func WithClock(c Clock) Option {
+	return func(o *windowOptions) { o.clock = c }
+}
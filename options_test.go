@@ -0,0 +1,45 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewOptions(t *testing.T) {
+	clk := NewManualClock(time.Unix(1_700_000_000, 0))
+	w := New(time.Minute, 0,
+		WithEMAAlpha(0.2),
+		WithPriceDecimals(2),
+		WithVolumeDecimals(3),
+		WithEvictionPolicy(EvictByCount, 3),
+		WithTracked(TrackAll&^TrackTWAP),
+		WithClock(clk),
+	)
+
+	c := w.Config()
+	if c.PriceScale != 100 || c.VolumeScale != 1000 || c.EMAAlpha != 0.2 ||
+		c.EvictionPolicy != "count" || c.MaxPoints != 3 || c.Capacity != 3 {
+		t.Fatalf("config: %+v", c)
+	}
+	if w.Tracked().Has(TrackTWAP) {
+		t.Fatal("TWAP should not be tracked")
+	}
+
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100.123, 1.2344, clk.Now().Add(time.Duration(i)*time.Hour))
+	}
+	if got := w.SumVolume(); got != 3*1.234 {
+		t.Fatalf("sum volume = %v, want %v", got, 3*1.234)
+	}
+	if s := w.Snapshot(); s == nil || s.LatestPrice != 100.12 || s.Ts != clk.Now().UnixMilli() {
+		t.Fatalf("snapshot: %+v", s)
+	}
+
+	// 旧签名仍是等价的薄封装
+	if a, b := NewSlidingWindow(time.Minute, 8, 0.1).Config(), New(time.Minute, 8, WithEMAAlpha(0.1)).Config(); a != b {
+		t.Fatalf("wrapper mismatch:\n%+v\n%+v", a, b)
+	}
+	if s := New(time.Minute, 8, WithSegments(0)); s.seg == nil || s.seg.segSize != 1024 {
+		t.Fatal("segmented option not applied")
+	}
+}
@@ -0,0 +1,176 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// maxOwnFills 自有成交记录上限，防止长时间无行情时无限堆积
+const maxOwnFills = 4096
+
+// OwnFill 自己的一笔成交回报
+type OwnFill struct {
+	Ts    time.Time `json:"ts"`
+	Price float64   `json:"price"`
+	Size  float64   `json:"size"`
+	Side  Side      `json:"side"` // 该成交在行情中的主动方向：吃单时为自己的方向，挂单成交时相反
+}
+
+// ownFlow 窗口内自己的成交，按时间升序，随窗口一起过期
+type ownFlow struct {
+	fills []ownFill
+}
+
+type ownFill struct {
+	ts     time.Time
+	price  QtyLoz
+	volume QtyLoz
+	side   Side
+}
+
+// MarkOwnFill 登记自己的一笔成交（写锁）。同一笔成交仍会出现在公开行情里，
+// 登记后 ImbalanceExOwn / VolumeFactorExOwn 等指标从窗口统计中扣除自有部分，避免自身冲击污染信号；
+// 早于当前窗口左边界或 size <= 0 时丢弃并返回 false
+func (w *SlidingWindow) MarkOwnFill(ts time.Time, price, size float64, side Side) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if size <= 0 {
+		return false
+	}
+	if w.size > 0 && !ts.After(w.thresholdUnlocked(w.lastUnlocked().Ts)) {
+		return false
+	}
+
+	f := ownFill{
+		ts:     ts,
+		price:  NewQtyLoz(price, w.priceScale),
+		volume: NewQtyLoz(size, w.volumeScale),
+		side:   side,
+	}
+	fills := w.own.fills
+	n := len(fills)
+	if n == 0 || !ts.Before(fills[n-1].ts) {
+		fills = append(fills, f)
+	} else {
+		// 回报乱序：插入到正确位置
+		i := sort.Search(n, func(i int) bool { return fills[i].ts.After(ts) })
+		fills = append(fills, ownFill{})
+		copy(fills[i+1:], fills[i:])
+		fills[i] = f
+	}
+	if len(fills) > maxOwnFills {
+		fills = append(fills[:0], fills[len(fills)-maxOwnFills:]...)
+	}
+	w.own.fills = fills
+	return true
+}
+
+// OwnFills 窗口内已登记的自有成交副本（读锁）
+func (w *SlidingWindow) OwnFills() []OwnFill {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]OwnFill, 0, len(w.own.fills))
+	for _, f := range w.own.fills {
+		out = append(out, OwnFill{
+			Ts:    f.ts,
+			Price: f.price.Float(w.priceScale),
+			Size:  f.volume.Float(w.volumeScale),
+			Side:  f.side,
+		})
+	}
+	return out
+}
+
+// trimOwnFillsUnlocked 移除 Ts <= threshold 的自有成交（要求持有写锁）
+func (w *SlidingWindow) trimOwnFillsUnlocked(threshold time.Time) {
+	fills := w.own.fills
+	i := 0
+	for i < len(fills) && !fills[i].ts.After(threshold) {
+		i++
+	}
+	if i > 0 {
+		w.own.fills = append(fills[:0], fills[i:]...)
+	}
+}
+
+// ownVolumesUnlocked 仍在窗口内（不早于最旧点）的自有成交：买、卖、无方向成交量与笔数（要求持有锁）。
+// 环满覆盖出窗时，比最旧点更早的登记不再扣除
+func (w *SlidingWindow) ownVolumesUnlocked() (buy, sell, other QtyLoz, n int) {
+	if w.size == 0 {
+		return 0, 0, 0, 0
+	}
+	head := w.atUnlocked(0).Ts
+	for _, f := range w.own.fills {
+		if f.ts.Before(head) {
+			continue
+		}
+		switch f.side {
+		case SideBuy:
+			buy += f.volume
+		case SideSell:
+			sell += f.volume
+		default:
+			other += f.volume
+		}
+		n++
+	}
+	return buy, sell, other, n
+}
+
+// ImbalanceExOwn 扣除自有成交后的 (buy - sell) / (buy + sell)（读锁），扣除后没有成交量时返回 false
+//
+//metric:name=imbalance_ex_own unit=ratio cost=O(k) min_points=1 requires=own_fills
+func (w *SlidingWindow) ImbalanceExOwn() (float64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	ob, os, _, _ := w.ownVolumesUnlocked()
+	bv := max(QtyLoz(w.buyVol.Load())-ob, 0).Float(w.volumeScale)
+	sv := max(QtyLoz(w.sellVol.Load())-os, 0).Float(w.volumeScale)
+	den := bv + sv
+	if den <= 0 {
+		return 0, false
+	}
+	return (bv - sv) / den, true
+}
+
+// VolumeFactorExOwn 扣除自有成交（成交量与笔数）后的每笔均量 / 成交量 EMA 基准（读锁）。
+// EMA 基准本身不做扣除，自有成交占比很小时两者差别可以忽略
+//
+//metric:name=volume_factor_ex_own unit=ratio cost=O(k) min_points=1 requires=own_fills
+func (w *SlidingWindow) VolumeFactorExOwn() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	baseline, ok := w.volumeBaselineUnlocked()
+	if !ok || baseline <= 0 {
+		return 0, false
+	}
+	ob, os, oo, n := w.ownVolumesUnlocked()
+	trades := w.nTrades.Load() - int64(n)
+	vol := w.sumVolume - ob - os - oo
+	if trades <= 0 || vol <= 0 {
+		return 0, false
+	}
+	return vol.Float(w.volumeScale) / float64(trades) / baseline, true
+}
+
+// OwnParticipation 自有成交占窗口成交量的比例（读锁）
+//
+//metric:name=own_participation unit=ratio cost=O(k) min_points=1 requires=own_fills
+func (w *SlidingWindow) OwnParticipation() (float64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.sumVolume <= 0 {
+		return 0, false
+	}
+	ob, os, oo, _ := w.ownVolumesUnlocked()
+	return min(float64(ob+os+oo)/float64(w.sumVolume), 1), true
+}
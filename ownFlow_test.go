@@ -0,0 +1,43 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMarkOwnFill(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.5)
+	t0 := time.Unix(1700000000, 0)
+	w.AddWindowPoint(SideSell, 100, 4, t0)
+	w.AddWindowPoint(SideBuy, 100, 2, t0.Add(time.Second))
+	w.AddWindowPoint(SideBuy, 101, 6, t0.Add(2*time.Second)) // 自己的吃单
+
+	if !w.MarkOwnFill(t0.Add(2*time.Second), 101, 6, SideBuy) {
+		t.Fatal("fill inside window should be accepted")
+	}
+	if w.Imbalance() <= 0 {
+		t.Fatal("raw imbalance should be buy-heavy")
+	}
+	imb, ok := w.ImbalanceExOwn()
+	if !ok || math.Abs(imb-(2-4)/6.0) > 1e-12 {
+		t.Fatalf("imbalance ex own = %v %v", imb, ok)
+	}
+	if p, ok := w.OwnParticipation(); !ok || p != 0.5 {
+		t.Fatalf("participation = %v %v", p, ok)
+	}
+	vf, ok1 := w.VolumeFactor()
+	vfEx, ok2 := w.VolumeFactorExOwn()
+	if !ok1 || !ok2 || vfEx >= vf {
+		t.Fatalf("volume factor %v ex own %v", vf, vfEx)
+	}
+
+	// 自有成交随窗口一起过期
+	w.AddWindowPoint(SideSell, 100, 1, t0.Add(2*time.Minute+time.Second))
+	if len(w.OwnFills()) != 0 {
+		t.Fatalf("own fills should expire: %+v", w.OwnFills())
+	}
+	if w.MarkOwnFill(t0, 100, 1, SideSell) {
+		t.Fatal("fill before window should be rejected")
+	}
+}
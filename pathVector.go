@@ -0,0 +1,53 @@
+package sliding_window
+
+import "time"
+
+// PathVector 把窗口价格路径按时间等分重采样为 n 个点并做 min-max 归一化到 [0, 1]（读锁），
+// 首尾分别对齐最早和最新成交，适合做形态相似度检索。价格无波动时全部为 0.5；
+// n < 2 或窗口不足两个点时返回 nil
+func (w *SlidingWindow) PathVector(n int) []float64 {
+	if n < 2 {
+		return nil
+	}
+
+	w.mu.RLock()
+	if w.size < 2 {
+		w.mu.RUnlock()
+		return nil
+	}
+	out := make([]float64, n)
+	start := w.atUnlocked(0).Ts
+	step := w.lastUnlocked().Ts.Sub(start) / time.Duration(n-1)
+	if step > 0 {
+		w.resamplePricesUnlocked(start, step, out)
+	} else {
+		// 所有点同一时刻：前面都取最早价格，最后一个点取最新价格
+		first := w.atUnlocked(0).Price.Float(w.priceScale)
+		for i := range out {
+			out[i] = first
+		}
+	}
+	// 步长截断可能让最后一个网格点略早于最新成交
+	out[n-1] = w.lastUnlocked().Price.Float(w.priceScale)
+	w.mu.RUnlock()
+
+	return minMaxScale(out)
+}
+
+// minMaxScale 原地缩放到 [0, 1]，常数序列取 0.5
+func minMaxScale(xs []float64) []float64 {
+	lo, hi := xs[0], xs[0]
+	for _, x := range xs {
+		lo = min(lo, x)
+		hi = max(hi, x)
+	}
+	span := hi - lo
+	for i, x := range xs {
+		if span <= 0 {
+			xs[i] = 0.5
+		} else {
+			xs[i] = (x - lo) / span
+		}
+	}
+	return xs
+}
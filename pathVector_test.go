@@ -0,0 +1,31 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPathVector(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if w.PathVector(5) != nil {
+		t.Fatal("empty window")
+	}
+	t0 := time.Unix(1700000000, 0)
+	// V 形：10s 内从 100 跌到 90 再回到 100
+	w.AddWindowPoint(SideSell, 100, 1, t0)
+	w.AddWindowPoint(SideSell, 95, 1, t0.Add(2*time.Second))
+	w.AddWindowPoint(SideSell, 90, 1, t0.Add(5*time.Second))
+	w.AddWindowPoint(SideBuy, 95, 1, t0.Add(7*time.Second))
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(10*time.Second))
+
+	got := w.PathVector(3)
+	want := []float64{1, 0, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("path = %v, want %v", got, want)
+		}
+	}
+	if len(w.PathVector(50)) != 50 {
+		t.Fatal("length")
+	}
+}
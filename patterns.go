@@ -0,0 +1,153 @@
+package sliding_window
+
+import (
+	"math"
+	"slices"
+)
+
+// DistanceMetric PathVector 与参考形态之间的距离度量
+type DistanceMetric uint8
+
+const (
+	// DistanceEuclidean 均方根距离，两条路径都在 [0, 1] 内，距离也在 [0, 1]
+	DistanceEuclidean DistanceMetric = iota
+	// DistanceCorrelation 1 − Pearson 相关系数，只看形状不看幅度，范围 [0, 2]
+	DistanceCorrelation
+	// DistanceDTW 动态时间规整的平均绝对偏差，容忍形态在时间轴上的伸缩，范围 [0, 1]
+	DistanceDTW
+)
+
+func (m DistanceMetric) String() string {
+	switch m {
+	case DistanceEuclidean:
+		return "euclidean"
+	case DistanceCorrelation:
+		return "correlation"
+	case DistanceDTW:
+		return "dtw"
+	default:
+		return "unknown"
+	}
+}
+
+// PatternMatch 一个参考形态的匹配结果
+type PatternMatch struct {
+	Index    int     `json:"index"`    // 在 library 中的下标
+	Distance float64 `json:"distance"` // 越小越相似
+	Score    float64 `json:"score"`    // 相似度 [0, 1]，越大越相似
+}
+
+// MatchPatterns 用 PathVector 与参考形态库逐一比较，按距离从小到大返回。
+// 每个参考形态按自身长度重采样窗口路径，并同样做 min-max 归一化；长度不足 2 的形态被跳过。
+// 窗口不足两个点时返回 nil
+func (w *SlidingWindow) MatchPatterns(library [][]float64, metric DistanceMetric) []PatternMatch {
+	paths := make(map[int][]float64)
+	out := make([]PatternMatch, 0, len(library))
+
+	for i, ref := range library {
+		n := len(ref)
+		if n < 2 {
+			continue
+		}
+		path, ok := paths[n]
+		if !ok {
+			path = w.PathVector(n)
+			if path == nil {
+				return nil
+			}
+			paths[n] = path
+		}
+
+		ref = minMaxScale(slices.Clone(ref))
+		d, s := pathDistance(path, ref, metric)
+		out = append(out, PatternMatch{Index: i, Distance: d, Score: s})
+	}
+
+	slices.SortStableFunc(out, func(a, b PatternMatch) int {
+		switch {
+		case a.Distance < b.Distance:
+			return -1
+		case a.Distance > b.Distance:
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+// pathDistance 两条等长、已归一化路径的距离和相似度
+func pathDistance(a, b []float64, metric DistanceMetric) (dist, score float64) {
+	switch metric {
+	case DistanceCorrelation:
+		r := pearson(a, b)
+		return 1 - r, (1 + r) / 2
+	case DistanceDTW:
+		d := dtwMeanAbs(a, b)
+		return d, 1 - d
+	default:
+		var ss float64
+		for i := range a {
+			d := a[i] - b[i]
+			ss += d * d
+		}
+		d := math.Sqrt(ss / float64(len(a)))
+		return d, 1 - d
+	}
+}
+
+// pearson 相关系数；任一序列为常数时返回 0
+func pearson(a, b []float64) float64 {
+	n := float64(len(a))
+	var sa, sb float64
+	for i := range a {
+		sa += a[i]
+		sb += b[i]
+	}
+	ma, mb := sa/n, sb/n
+
+	var cov, va, vb float64
+	for i := range a {
+		da, db := a[i]-ma, b[i]-mb
+		cov += da * db
+		va += da * da
+		vb += db * db
+	}
+	if va <= 0 || vb <= 0 {
+		return 0
+	}
+	return cov / math.Sqrt(va*vb)
+}
+
+// dtwMeanAbs DTW 最优规整路径上的平均绝对偏差（两行滚动，O(n²) 时间、O(n) 空间）
+func dtwMeanAbs(a, b []float64) float64 {
+	n := len(b)
+	prevCost, curCost := make([]float64, n), make([]float64, n)
+	prevLen, curLen := make([]int, n), make([]int, n)
+
+	for i := range a {
+		for j := range b {
+			c := math.Abs(a[i] - b[j])
+			switch {
+			case i == 0 && j == 0:
+				curCost[j], curLen[j] = c, 1
+			case i == 0:
+				curCost[j], curLen[j] = curCost[j-1]+c, curLen[j-1]+1
+			case j == 0:
+				curCost[j], curLen[j] = prevCost[j]+c, prevLen[j]+1
+			default:
+				// 三个前驱中取累计代价最小者
+				bc, bl := prevCost[j-1], prevLen[j-1]
+				if prevCost[j] < bc {
+					bc, bl = prevCost[j], prevLen[j]
+				}
+				if curCost[j-1] < bc {
+					bc, bl = curCost[j-1], curLen[j-1]
+				}
+				curCost[j], curLen[j] = bc+c, bl+1
+			}
+		}
+		prevCost, curCost = curCost, prevCost
+		prevLen, curLen = curLen, prevLen
+	}
+	return prevCost[n-1] / float64(prevLen[n-1])
+}
@@ -0,0 +1,38 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchPatterns(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	// V 形反转
+	for i := 0; i <= 40; i++ {
+		px := 100 - float64(i)*0.5
+		if i > 20 {
+			px = 90 + float64(i-20)*0.5
+		}
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	library := [][]float64{
+		{0, 1, 2, 3, 4, 5},    // 单边上涨
+		{5, 3, 1, 0, 2, 4, 5}, // V 形
+		{1},                   // 太短，跳过
+		{0, 1, 0, 1, 0, 1},    // 震荡
+	}
+	for _, m := range []DistanceMetric{DistanceEuclidean, DistanceCorrelation, DistanceDTW} {
+		got := w.MatchPatterns(library, m)
+		if len(got) != 3 || got[0].Index != 1 {
+			t.Fatalf("%v: %+v", m, got)
+		}
+		if got[0].Score < got[1].Score {
+			t.Fatalf("%v: best match should have the highest score: %+v", m, got)
+		}
+	}
+	if got := dtwMeanAbs([]float64{0, 0, 1, 1}, []float64{0, 1, 1, 1}); got != 0 {
+		t.Fatalf("dtw of time-shifted step = %v, want 0", got)
+	}
+}
@@ -0,0 +1,92 @@
+package sliding_window
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPriceHistogramMedian(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 4096, 0.1)
+	if err := w.SetPriceHistogram(0.01, 1); err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	t0 := time.Unix(1_700_000_000, 0)
+	px := 100.0
+	for i := 0; i < 3000; i++ { // 30 秒，前面的点会出窗
+		px += float64(r.IntN(5)-2) * 0.01
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*10*time.Millisecond))
+	}
+
+	// 一个 tick 的分箱与精确中位数一致
+	got, ok := w.MedianPrice()
+	prices := make([]float64, 0, w.size)
+	for _, p := range w.Export(ExportOptions{}).Points {
+		prices = append(prices, p.PriceFloat(w.priceScale))
+	}
+	sort.Float64s(prices)
+	want := quantileSorted(prices, 0.5)
+	if !ok || math.Abs(got-want) > 1e-9 {
+		t.Fatalf("median = %v, want %v", got, want)
+	}
+	if q, _ := w.PriceQuantileApprox(0.9); math.Abs(q-quantileSorted(prices, 0.9)) > 1e-9 {
+		t.Fatalf("q90 = %v, want %v", q, quantileSorted(prices, 0.9))
+	}
+	// 出窗后直方图只保留当前价格区间内的分箱
+	if hi, lo, _ := w.HighLow(); len(w.priceHist.counts) > int(math.Round((hi-lo)/0.01))+1 {
+		t.Fatalf("bins = %d for range %v..%v", len(w.priceHist.counts), lo, hi)
+	}
+
+	// 10 tick 的分箱：误差在半个分箱以内
+	if err := w.SetPriceHistogram(0.01, 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := w.MedianPrice(); math.Abs(got-want) > 0.05 {
+		t.Fatalf("coarse median = %v, want ~%v", got, want)
+	}
+
+	if err := w.SetPriceHistogram(0, 0); err != nil || w.priceHist != nil {
+		t.Fatal("tick 0 should disable the histogram")
+	}
+	if _, ok := w.PriceQuantileApprox(0.5); ok {
+		t.Fatal("disabled histogram should not be ok")
+	}
+}
+
+func TestPriceHistogramOutlier(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	if err := w.SetPriceHistogram(0.01, 1); err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i)*0.01, 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	before, _ := w.MedianPrice()
+	if s := w.Snapshot(); s.MedianPrice != before {
+		t.Fatalf("snapshot median %v, want %v", s.MedianPrice, before)
+	}
+
+	// 价格 0 与 100 倍的胖手指成交各只占一个分箱
+	w.AddWindowPoint(SideSell, 0, 1, t0.Add(11*time.Second))
+	w.AddWindowPoint(SideBuy, 10000, 1, t0.Add(12*time.Second))
+	if n := len(w.priceHist.counts); n != 12 {
+		t.Fatalf("bins = %d, want 12", n)
+	}
+	if got, _ := w.MedianPrice(); math.Abs(got-before) > 0.011 {
+		t.Fatalf("median moved to %v", got)
+	}
+
+	// 切换分箱后快照中位数随之更新
+	want, _ := w.MedianPrice()
+	if err := w.SetPriceHistogram(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	coarse, _ := w.MedianPrice()
+	if s := w.Snapshot(); s.MedianPrice != coarse || coarse == want {
+		t.Fatalf("snapshot median %v, fine %v coarse %v", s.MedianPrice, want, coarse)
+	}
+}
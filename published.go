@@ -0,0 +1,161 @@
+package sliding_window
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// CoreStats 窗口核心统计（真实值），来自双缓冲发布区，读取不经过 mu
+type CoreStats struct {
+	Size       int       `json:"size"`
+	Latest     float64   `json:"latest"`
+	High       float64   `json:"high"`
+	Low        float64   `json:"low"`
+	Volume     float64   `json:"volume"`
+	BuyVolume  float64   `json:"buy_volume"`
+	SellVolume float64   `json:"sell_volume"`
+	Trades     int64     `json:"trades"`
+	First      time.Time `json:"first"`
+	Last       time.Time `json:"last"`
+	Version    uint64    `json:"version"`
+}
+
+// seqStats 一个发布缓冲区。写者只写影子缓冲（当前未被发布的那个），
+// 写完后原子切换 current 指针；seq 奇数表示写入中，读者在前后 seq 一致时得到一致视图。
+// 只有读者跨越两次发布仍未读完时才会与写者撞上同一个缓冲，此时 seq 校验失败并重试。
+// 字段用原子类型，避免数据竞争。
+type seqStats struct {
+	seq       atomic.Uint64
+	size      atomic.Int64
+	latest    atomic.Int64
+	high      atomic.Int64
+	low       atomic.Int64
+	sumVolume atomic.Int64 // 未截断的 sumVolume（ticks）
+	sumV      atomic.Int64
+	buy       atomic.Int64
+	sell      atomic.Int64
+	trades    atomic.Int64
+	first     atomic.Int64 // UnixNano
+	last      atomic.Int64
+	notional  atomic.Uint64 // float64 bits
+	buyNotl   atomic.Uint64
+	sellNotl  atomic.Uint64
+	version   atomic.Uint64
+}
+
+// rawCore 发布区的原始（tick）视图
+type rawCore struct {
+	size, latest, high, low, sumVolume, sumV, buy, sell, trades, first, last int64
+	notional, buyNotional, sellNotional                                      float64
+	version                                                                  uint64
+}
+
+// publishUnlocked 把核心统计写入影子缓冲并切换为当前（要求持有写锁）
+func (w *SlidingWindow) publishUnlocked() {
+	p := &w.published[0]
+	if w.current.Load() == p {
+		p = &w.published[1]
+	}
+
+	p.seq.Add(1) // 奇数：写入中
+
+	p.size.Store(int64(w.size))
+	p.latest.Store(w.LatestPrice.Load())
+	p.high.Store(w.HighestPrice.Load())
+	p.low.Store(w.LowestPrice.Load())
+	p.sumVolume.Store(int64(w.sumVolume))
+	p.sumV.Store(w.SumV.Load())
+	p.buy.Store(w.buyVol.Load())
+	p.sell.Store(w.sellVol.Load())
+	p.trades.Store(w.nTrades.Load())
+	if w.size > 0 {
+		p.first.Store(w.atUnlocked(0).Ts.UnixNano())
+		p.last.Store(w.lastUnlocked().Ts.UnixNano())
+	} else {
+		p.first.Store(0)
+		p.last.Store(0)
+	}
+	p.notional.Store(math.Float64bits(w.sumNotional.value()))
+	p.buyNotl.Store(math.Float64bits(w.buyNotional.value()))
+	p.sellNotl.Store(math.Float64bits(w.sellNotional.value()))
+	p.version.Store(w.version.Load())
+
+	p.seq.Add(1) // 偶数：发布完成
+	w.current.Store(p)
+}
+
+// loadPublished 读取一致的发布视图（无锁）
+func (w *SlidingWindow) loadPublished() rawCore {
+	for spins := 0; ; spins++ {
+		p := w.current.Load()
+		if p == nil {
+			return rawCore{}
+		}
+		s1 := p.seq.Load()
+		if s1&1 == 0 {
+			r := rawCore{
+				size:         p.size.Load(),
+				latest:       p.latest.Load(),
+				high:         p.high.Load(),
+				low:          p.low.Load(),
+				sumVolume:    p.sumVolume.Load(),
+				sumV:         p.sumV.Load(),
+				buy:          p.buy.Load(),
+				sell:         p.sell.Load(),
+				trades:       p.trades.Load(),
+				first:        p.first.Load(),
+				last:         p.last.Load(),
+				notional:     math.Float64frombits(p.notional.Load()),
+				buyNotional:  math.Float64frombits(p.buyNotl.Load()),
+				sellNotional: math.Float64frombits(p.sellNotl.Load()),
+				version:      p.version.Load(),
+			}
+			if p.seq.Load() == s1 {
+				return r
+			}
+		}
+		if spins > 16 {
+			runtime.Gosched()
+		}
+	}
+}
+
+// CoreStats 核心统计快照（无锁），各字段来自同一次发布，互相一致
+func (w *SlidingWindow) CoreStats() CoreStats {
+	r := w.loadPublished()
+	cs := CoreStats{
+		Size:       int(r.size),
+		Latest:     QtyLoz(r.latest).Float(w.priceScale),
+		High:       QtyLoz(r.high).Float(w.priceScale),
+		Low:        QtyLoz(r.low).Float(w.priceScale),
+		Volume:     QtyLoz(r.sumV).Float(w.volumeScale),
+		BuyVolume:  QtyLoz(r.buy).Float(w.volumeScale),
+		SellVolume: QtyLoz(r.sell).Float(w.volumeScale),
+		Trades:     r.trades,
+		Version:    r.version,
+	}
+	if r.size > 0 {
+		cs.First = time.Unix(0, r.first)
+		cs.Last = time.Unix(0, r.last)
+	}
+	return cs
+}
+
+// volumesOf 按展示单位换算发布视图中的 total / buy / sell，与 volumesUnlocked 口径一致
+func (w *SlidingWindow) volumesOf(r rawCore) (total, buy, sell float64) {
+	c := w.volConv.Load()
+	if c != nil && c.unit == VolumeInQuote {
+		return r.notional * c.mult, r.buyNotional * c.mult, r.sellNotional * c.mult
+	}
+
+	m := 1.0
+	if c != nil {
+		m = c.mult
+	}
+	total = QtyLoz(r.sumVolume).Float(w.volumeScale) * m
+	buy = QtyLoz(r.buy).Float(w.volumeScale) * m
+	sell = QtyLoz(r.sell).Float(w.volumeScale) * m
+	return
+}
@@ -0,0 +1,147 @@
+package sliding_window
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublished_SnapshotConsistent(t *testing.T) {
+	w := NewSlidingWindow(time.Second, 256, 0.1)
+	if cs := w.CoreStats(); cs.Size != 0 || cs.Version != 0 {
+		t.Fatalf("fresh window core stats %+v", cs)
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cs := w.CoreStats()
+				if cs.BuyVolume+cs.SellVolume != cs.Volume || int64(cs.Size) != cs.Trades {
+					t.Errorf("torn core stats: %+v", cs)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 3000; i++ {
+		side := SideBuy
+		if i%2 == 0 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i%9), 1, base.Add(time.Duration(i)*time.Millisecond))
+	}
+	close(stop)
+	wg.Wait()
+
+	s := w.Snapshot()
+	if s == nil || s.TotalVolume != s.BuyVolume+s.SellVolume {
+		t.Fatalf("snapshot volumes inconsistent: %+v", s)
+	}
+}
+
+func TestSnapshot_SingleVersion(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, base.Add(time.Duration(i)*time.Second))
+	}
+	d, ok := w.derived()
+	if !ok || d.version != w.Version() || d.core.version != d.version {
+		t.Fatalf("derived version %d core %d window %d", d.version, d.core.version, w.Version())
+	}
+
+	// 写者持锁期间版本号前进：派生指标不等待，返回上一个完整版本
+	w.mu.Lock()
+	w.version.Add(1)
+	d2, ok := w.derived()
+	w.mu.Unlock()
+	if !ok || d2.version != d.version || d2.core != d.core {
+		t.Fatalf("expected previous version %d, got %d", d.version, d2.version)
+	}
+}
+
+func TestSnapshot_DoesNotWaitForWriter(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetRiskOverlay(&RiskOverlay{MaxSize: 100})
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, base.Add(time.Duration(i)*time.Second))
+	}
+	prev := w.Snapshot()
+
+	// 写者持锁期间版本号前进：Snapshot 不等待，返回上一个完整版本
+	w.mu.Lock()
+	w.version.Add(1)
+	done := make(chan *Snapshot)
+	go func() { done <- w.Snapshot() }()
+	var s *Snapshot
+	select {
+	case s = <-done:
+	case <-time.After(time.Second):
+		w.mu.Unlock()
+		t.Fatal("Snapshot blocked on the writer")
+	}
+	w.mu.Unlock()
+
+	if s == nil || s.LatestPrice != prev.LatestPrice || s.VolumeWeightedAveragePrice != prev.VolumeWeightedAveragePrice {
+		t.Fatalf("expected previous version, got %+v", s)
+	}
+
+	// 锁释放后重算，核心字段与派生字段属于同一版本
+	w.AddWindowPoint(SideSell, 120, 10, base.Add(11*time.Second))
+	s = w.Snapshot()
+	if s.LatestPrice != 120 || s.HighestPrice != 120 || s.VolumeWeightedAveragePrice <= prev.VolumeWeightedAveragePrice {
+		t.Fatalf("snapshot not refreshed: %+v", s)
+	}
+}
+
+func TestSnapshot_StaleKeepsVersionAndBoundsAge(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	clk := NewManualClock(t0)
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetClock(clk)
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	s1 := w.Snapshot()
+	if s1 == nil || s1.Stale || s1.Version != w.Version() || s1.Ts != t0.UnixMilli() {
+		t.Fatalf("fresh snapshot: %+v", s1)
+	}
+
+	// 写者持锁：缓存未超龄时复用，保留原来的 Ts / Version 并标记 Stale
+	w.mu.Lock()
+	w.version.Add(1)
+	clk.Advance(10 * time.Millisecond)
+	s2 := w.Snapshot()
+	if s2 == nil || !s2.Stale || s2.Version != s1.Version || s2.Ts != s1.Ts {
+		w.mu.Unlock()
+		t.Fatalf("stale snapshot: %+v", s2)
+	}
+
+	// 缓存超龄后等待写者释放锁再重算
+	clk.Advance(snapshotMaxStale)
+	done := make(chan *Snapshot)
+	go func() { done <- w.Snapshot() }()
+	select {
+	case s := <-done:
+		w.mu.Unlock()
+		t.Fatalf("snapshot older than snapshotMaxStale should wait for the writer: %+v", s)
+	case <-time.After(20 * time.Millisecond):
+	}
+	w.mu.Unlock()
+	s3 := <-done
+	if s3 == nil || s3.Stale || s3.Version != w.Version() || s3.Ts != clk.Now().UnixMilli() {
+		t.Fatalf("snapshot after writer released: %+v", s3)
+	}
+}
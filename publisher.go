@@ -0,0 +1,200 @@
+package sliding_window
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventKind 发布到消息队列的事件类型
+type EventKind string
+
+const (
+	EventSnapshot EventKind = "snapshot"
+	EventSignal   EventKind = "signal"
+)
+
+// SignalEvent 一次信号触发
+type SignalEvent struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Dir   int     `json:"dir"` // 1 多，-1 空，0 无方向
+}
+
+// Event 发布到消息队列的一条事件，JSON 编码后作为消息体
+type Event struct {
+	Kind     EventKind         `json:"kind"`
+	Symbol   string            `json:"symbol"`
+	Ts       int64             `json:"ts"` // 毫秒
+	Snapshot *Snapshot         `json:"snapshot,omitempty"`
+	Signal   *SignalEvent      `json:"signal,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"` // 来源窗口的标签
+}
+
+// Publisher 把一批事件写到外部事件总线（NATS、Kafka 等）。
+// 返回错误表示整批失败，BatchPublisher 会整批重试，实现需要能容忍重复投递
+type Publisher interface {
+	Publish(ctx context.Context, events []Event) error
+}
+
+// BatchPublisher 异步批量发布：Emit 只入队不阻塞，后台协程按条数或时间间隔成批调用 Publisher，
+// 失败按指数退避重试 MaxRetries 次。需要 Start 后才开始发布（或挂载到窗口 / manager 上随之 Start）。
+// 队列满时丢弃并计数；重试耗尽后调用 OnError（可选），Close 返回最后一次发布错误。
+type BatchPublisher struct {
+	pub       Publisher
+	batchSize int
+	every     time.Duration
+	queue     chan Event
+	closed    atomic.Bool
+	dropped   atomic.Int64
+	failed    atomic.Int64
+	run       runner
+
+	errMu   sync.Mutex
+	lastErr error
+
+	MaxRetries   int           // 默认 3
+	RetryBackoff time.Duration // 首次重试等待，之后翻倍，默认 100ms
+	OnError      func(err error, batch []Event)
+}
+
+// NewBatchPublisher batchSize 条或 every 时间到即发布一批，queueSize 为入队缓冲
+func NewBatchPublisher(pub Publisher, batchSize int, every time.Duration, queueSize int) *BatchPublisher {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if every <= 0 {
+		every = time.Second
+	}
+	if queueSize < batchSize {
+		queueSize = batchSize
+	}
+	return &BatchPublisher{
+		pub:          pub,
+		batchSize:    batchSize,
+		every:        every,
+		queue:        make(chan Event, queueSize),
+		MaxRetries:   3,
+		RetryBackoff: 100 * time.Millisecond,
+	}
+}
+
+// Emit 入队一条事件，不阻塞
+func (p *BatchPublisher) Emit(ev Event) {
+	if p.closed.Load() {
+		p.dropped.Add(1)
+		return
+	}
+	select {
+	case p.queue <- ev:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// EmitSnapshot 入队一条快照事件；nil 快照忽略
+func (p *BatchPublisher) EmitSnapshot(symbol string, s *Snapshot) {
+	if s == nil {
+		return
+	}
+	p.Emit(Event{Kind: EventSnapshot, Symbol: symbol, Ts: s.Ts, Snapshot: s, Tags: s.Tags})
+}
+
+// EmitSignal 入队一条信号事件
+func (p *BatchPublisher) EmitSignal(symbol string, sig SignalEvent, ts time.Time) {
+	p.Emit(Event{Kind: EventSignal, Symbol: symbol, Ts: ts.UnixMilli(), Signal: &sig})
+}
+
+// EmitTick 入队一次对齐快照的全部 symbol，可直接作为 NewAlignedScheduler 的回调
+func (p *BatchPublisher) EmitTick(t AlignedTick) {
+	for sym, s := range t.Snaps {
+		p.EmitSnapshot(sym, s)
+	}
+}
+
+// Dropped 因队列满或已关闭而丢弃的事件数
+func (p *BatchPublisher) Dropped() int64 { return p.dropped.Load() }
+
+// Failed 重试耗尽后放弃的事件数
+func (p *BatchPublisher) Failed() int64 { return p.failed.Load() }
+
+// Start 启动后台发布协程，ctx 结束时发布完剩余事件后退出
+func (p *BatchPublisher) Start(ctx context.Context) error { return p.run.start(ctx, p.loop) }
+
+// Close 停止接收，把队列中剩余事件发布完后返回最后一次发布错误
+func (p *BatchPublisher) Close() error {
+	p.closed.Store(true)
+	if err := p.run.close(); err != nil {
+		return err
+	}
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	return p.lastErr
+}
+
+// publish 发布一批，失败时退避重试。关闭时排空队列也会完整重试，
+// 因此 Close 最多多等 RetryBackoff·(2^MaxRetries−1)
+func (p *BatchPublisher) publish(ctx context.Context, batch []Event) {
+	ctx = context.WithoutCancel(ctx)
+	backoff := p.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = p.pub.Publish(ctx, batch); err == nil {
+			return
+		}
+		if attempt >= p.MaxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	p.failed.Add(int64(len(batch)))
+	p.errMu.Lock()
+	p.lastErr = err
+	p.errMu.Unlock()
+	if p.OnError != nil {
+		p.OnError(err, append([]Event(nil), batch...))
+	}
+}
+
+func (p *BatchPublisher) loop(ctx context.Context) error {
+	ticker := time.NewTicker(p.every)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, p.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		p.publish(ctx, batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 排空队列后退出
+			for {
+				select {
+				case ev := <-p.queue:
+					batch = append(batch, ev)
+					if len(batch) >= p.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return nil
+				}
+			}
+		case ev := <-p.queue:
+			batch = append(batch, ev)
+			if len(batch) >= p.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
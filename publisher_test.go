@@ -0,0 +1,74 @@
+package sliding_window
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type flakyProducer struct {
+	mu      sync.Mutex
+	fails   int
+	batches [][]KafkaMessage
+}
+
+func (f *flakyProducer) Produce(_ context.Context, msgs []KafkaMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fails > 0 {
+		f.fails--
+		return errors.New("broker unavailable")
+	}
+	f.batches = append(f.batches, msgs)
+	return nil
+}
+
+func TestBatchPublisherRetry(t *testing.T) {
+	prod := &flakyProducer{fails: 2}
+	bp := NewBatchPublisher(&KafkaPublisher{Producer: prod, Topic: "snaps", SignalTopic: "signals"}, 3, time.Hour, 16)
+	bp.RetryBackoff = time.Millisecond
+	if err := bp.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.UnixMilli(1700000000000)
+	bp.EmitSnapshot("BTC", &Snapshot{LatestPrice: 1, Ts: ts.UnixMilli()})
+	bp.EmitSnapshot("ETH", &Snapshot{LatestPrice: 2, Ts: ts.UnixMilli()})
+	bp.EmitSignal("BTC", SignalEvent{Name: "vwap_reversion", Dir: -1}, ts)
+	bp.EmitSnapshot("SOL", &Snapshot{LatestPrice: 3, Ts: ts.UnixMilli()}) // 凑不满一批，Close 时发出
+
+	if err := bp.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if len(prod.batches) != 2 || len(prod.batches[0]) != 3 || bp.Failed() != 0 {
+		t.Fatalf("batches=%d failed=%d", len(prod.batches), bp.Failed())
+	}
+	sig := prod.batches[0][2]
+	var ev Event
+	if err := json.Unmarshal(sig.Value, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if sig.Topic != "signals" || string(sig.Key) != "BTC" || ev.Signal == nil || ev.Signal.Dir != -1 {
+		t.Fatalf("signal message: %+v %+v", sig, ev)
+	}
+}
+
+func TestBatchPublisherGivesUp(t *testing.T) {
+	prod := &flakyProducer{fails: 100}
+	bp := NewBatchPublisher(&KafkaPublisher{Producer: prod, Topic: "snaps"}, 1, time.Hour, 4)
+	bp.MaxRetries, bp.RetryBackoff = 2, time.Millisecond
+	var reported int
+	bp.OnError = func(err error, batch []Event) { reported += len(batch) }
+	bp.Start(context.Background())
+
+	bp.EmitSnapshot("BTC", &Snapshot{})
+	if err := bp.Close(); err == nil || bp.Failed() != 1 || reported != 1 {
+		t.Fatalf("err=%v failed=%d reported=%d", err, bp.Failed(), reported)
+	}
+	if prod.fails != 97 {
+		t.Fatalf("attempts = %d, want 3", 100-prod.fails)
+	}
+}
@@ -0,0 +1,43 @@
+package sliding_window
+
+import "time"
+
+// ReadyPolicy 窗口级就绪条件，零值表示不限制（各指标仍按自己的最少点数判断）
+type ReadyPolicy struct {
+	MinPoints   int     // 窗口内最少点数
+	MinSpanFrac float64 // 最早到最新一笔的跨度占窗口时长的最小比例，比如 0.5 表示至少积累了半个窗口；计数窗口不适用
+	MinVolume   float64 // 最少成交量（真实值，合约数）
+}
+
+// SetReadyPolicy 设置窗口级就绪条件（写锁）。条件在每次 Add 后求值一次，
+// 未就绪时所有指标读取（VWAP、Median、Momentum、Snapshot 等）统一返回 false / nil，
+// 避免各指标按各自的 size < 2 之类的判断给出口径不一的结果
+func (w *SlidingWindow) SetReadyPolicy(p ReadyPolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.readyPolicy = p
+	w.evalReadyUnlocked()
+}
+
+// IsReady 按 ReadyPolicy 窗口是否就绪（无锁，取最近一次写入后的求值结果）
+func (w *SlidingWindow) IsReady() bool {
+	return !w.unready.Load()
+}
+
+// evalReadyUnlocked 求值就绪条件（要求持有写锁），随版本号递增调用
+func (w *SlidingWindow) evalReadyUnlocked() {
+	p := w.readyPolicy
+	ready := w.size >= p.MinPoints
+	if ready && p.MinSpanFrac > 0 && w.evict != EvictByCount {
+		var span time.Duration
+		if w.size > 0 {
+			span = w.lastUnlocked().Ts.Sub(w.atUnlocked(0).Ts)
+		}
+		ready = w.duration > 0 && span.Seconds() >= p.MinSpanFrac*w.duration.Seconds()
+	}
+	if ready && p.MinVolume > 0 {
+		ready = w.sumVolume.Float(w.volumeScale) >= p.MinVolume
+	}
+	w.unready.Store(!ready)
+}
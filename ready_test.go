@@ -0,0 +1,39 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadyPolicy(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Second))
+	}
+	if !w.IsReady() || w.Snapshot() == nil {
+		t.Fatal("zero policy should not gate metrics")
+	}
+
+	w.SetReadyPolicy(ReadyPolicy{MinPoints: 3, MinSpanFrac: 0.5, MinVolume: 3})
+	if w.IsReady() {
+		t.Fatal("4s of a 1m window should not be ready")
+	}
+	if _, ok := w.VolumeWeightedAveragePrice(); ok {
+		t.Fatal("vwap should be gated")
+	}
+	if _, _, ok := w.HighLow(); ok {
+		t.Fatal("high/low should be gated")
+	}
+	if w.Snapshot() != nil || len(w.Evaluate([]MetricID{MetricVWAP})) != 0 {
+		t.Fatal("snapshot / evaluate should be gated")
+	}
+
+	w.AddWindowPoint(SideBuy, 101, 1, t0.Add(30*time.Second))
+	if !w.IsReady() {
+		t.Fatal("30s span should be ready")
+	}
+	if _, ok := w.Momentum(); !ok || w.Snapshot() == nil {
+		t.Fatal("metrics should be available once ready")
+	}
+}
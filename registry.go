@@ -0,0 +1,31 @@
+package sliding_window
+
+//go:generate go run ./internal/metricsgen -out metrics_gen.go
+
+// MetricInfo 指标元信息，由 internal/metricsgen 从各指标方法的 //metric: 注解生成
+type MetricInfo struct {
+	Name      string `json:"name"`       // 稳定的指标名
+	Unit      string `json:"unit"`       // price / volume / ratio / return / ...
+	Cost      string `json:"cost"`       // 单次计算复杂度
+	MinPoints int    `json:"min_points"` // 窗口至少需要的点数
+	Requires  string `json:"requires"`   // 额外前置条件（如需要先载入画像），为空表示无
+	Method    string `json:"method"`     // 对应的 SlidingWindow 方法名
+	Doc       string `json:"doc"`        // 方法说明第一行
+}
+
+// Metrics 返回全部可用指标（副本），供看板 / 配置界面枚举
+func Metrics() []MetricInfo {
+	out := make([]MetricInfo, len(metricRegistry))
+	copy(out, metricRegistry)
+	return out
+}
+
+// LookupMetric 按名字查找指标元信息
+func LookupMetric(name string) (MetricInfo, bool) {
+	for _, m := range metricRegistry {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return MetricInfo{}, false
+}
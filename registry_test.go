@@ -0,0 +1,26 @@
+package sliding_window
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetrics_RegistryMatchesMethods(t *testing.T) {
+	typ := reflect.TypeOf(&SlidingWindow{})
+	seen := map[string]bool{}
+
+	for _, m := range Metrics() {
+		if seen[m.Name] {
+			t.Fatalf("duplicate metric %q", m.Name)
+		}
+		seen[m.Name] = true
+
+		if _, ok := typ.MethodByName(m.Method); !ok {
+			t.Fatalf("metric %q refers to missing method %s", m.Name, m.Method)
+		}
+	}
+
+	if _, ok := LookupMetric("vwap"); !ok {
+		t.Fatal("vwap should be registered")
+	}
+}
@@ -0,0 +1,112 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// reorderBuffer 乱序缓冲：点先按时间插入 pending，水位线（已见最大时间 - lateness）
+// 之前的点按时间顺序放行写入窗口；比已放行的点还早的点视为过晚丢弃
+type reorderBuffer struct {
+	lateness time.Duration
+	pending  []WindowPoint // 按 Ts 升序，同一时间保持到达顺序
+	out      []WindowPoint // 放行点的复用缓冲
+	maxTs    time.Time     // 已见最大时间
+	flushed  time.Time     // 最后一个放行点的时间
+
+	seqs    []uint64 // 与 pending 一一对应的成交序号，收到过带序号的批次后才维护
+	outSeqs []uint64 // 放行序号的复用缓冲
+}
+
+// SetReorderBuffer 开启乱序缓冲（写锁），maxLateness <= 0 关闭。
+// 点最多被延迟 maxLateness（按成交时间）后按时间顺序写入，窗口指标相应滞后；
+// 晚于 maxLateness 到达、已无法按顺序插入的点被丢弃并计入 Counters().LateDrops。
+// 修改配置前先写入缓冲中的全部点
+func (w *SlidingWindow) SetReorderBuffer(maxLateness time.Duration) {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	w.flushReorderUnlocked()
+	if maxLateness <= 0 {
+		w.reorder = reorderBuffer{}
+		return
+	}
+	w.reorder.lateness = maxLateness
+}
+
+// FlushReorder 立即按时间顺序写入乱序缓冲中的全部点（写锁）
+func (w *SlidingWindow) FlushReorder() {
+	w.lockWrite()
+	defer w.unlockWrite()
+	w.flushReorderUnlocked()
+}
+
+// ReorderPending 乱序缓冲中等待写入的点数（读锁）
+func (w *SlidingWindow) ReorderPending() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.reorder.pending)
+}
+
+// reorderUnlocked 把一批点（及其序号，可为 nil）放入缓冲，返回按时间排好序、可以写入的点与序号（要求持有写锁）。
+// 返回的切片复用内部缓冲，在下一次调用前有效
+func (w *SlidingWindow) reorderUnlocked(pts []WindowPoint, seqs []uint64) ([]WindowPoint, []uint64) {
+	r := &w.reorder
+	if seqs != nil && r.seqs == nil {
+		r.seqs = make([]uint64, len(r.pending), cap(r.pending))
+	}
+	for k, pt := range pts {
+		if !r.flushed.IsZero() && pt.Ts.Before(r.flushed) {
+			w.counters.lateDrops.Add(1)
+			continue
+		}
+		// 插到同一时间的已有点之后，保持到达顺序
+		i := sort.Search(len(r.pending), func(i int) bool {
+			return r.pending[i].Ts.After(pt.Ts)
+		})
+		r.pending = append(r.pending, WindowPoint{})
+		copy(r.pending[i+1:], r.pending[i:])
+		r.pending[i] = pt
+		if r.seqs != nil {
+			var seq uint64
+			if seqs != nil {
+				seq = seqs[k]
+			}
+			r.seqs = append(r.seqs, 0)
+			copy(r.seqs[i+1:], r.seqs[i:])
+			r.seqs[i] = seq
+		}
+		if pt.Ts.After(r.maxTs) {
+			r.maxTs = pt.Ts
+		}
+	}
+
+	watermark := r.maxTs.Add(-r.lateness)
+	n := sort.Search(len(r.pending), func(i int) bool {
+		return r.pending[i].Ts.After(watermark)
+	})
+	return r.releaseUnlocked(n)
+}
+
+// releaseUnlocked 取出 pending 的前 n 个点及其序号（要求持有写锁）
+func (r *reorderBuffer) releaseUnlocked(n int) ([]WindowPoint, []uint64) {
+	if n == 0 {
+		return nil, nil
+	}
+	r.out = append(r.out[:0], r.pending[:n]...)
+	r.flushed = r.out[n-1].Ts
+	r.pending = append(r.pending[:0], r.pending[n:]...)
+	if r.seqs == nil {
+		return r.out, nil
+	}
+	r.outSeqs = append(r.outSeqs[:0], r.seqs[:n]...)
+	r.seqs = append(r.seqs[:0], r.seqs[n:]...)
+	return r.out, r.outSeqs
+}
+
+// flushReorderUnlocked 写入缓冲中的全部点（要求持有写锁）
+func (w *SlidingWindow) flushReorderUnlocked() {
+	if pts, seqs := w.reorder.releaseUnlocked(len(w.reorder.pending)); len(pts) > 0 {
+		w.addOrdered(pts, seqs)
+	}
+}
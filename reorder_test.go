@@ -0,0 +1,42 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReorderBuffer(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetReorderBuffer(2 * time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s float64) time.Time { return t0.Add(time.Duration(s * float64(time.Second))) }
+
+	w.AddWindowPoint(SideBuy, 100, 1, at(0))
+	w.AddWindowPoint(SideBuy, 103, 1, at(3))
+	w.AddWindowPoint(SideSell, 101, 1, at(1)) // 乱序但在容忍范围内
+	w.AddWindowPoint(SideBuy, 102, 1, at(2))
+	w.AddWindowPoint(SideBuy, 105, 1, at(5)) // 水位线推进到 3s
+
+	if got := w.ReorderPending(); got != 1 {
+		t.Fatalf("pending = %d, want 1", got)
+	}
+	w.AddWindowPoint(SideSell, 99, 1, at(0.5)) // 早于已放行的 3s，丢弃
+	if c := w.Counters(); c.LateDrops != 1 || c.Rejects != 0 {
+		t.Fatalf("counters: %+v", c)
+	}
+
+	w.FlushReorder()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.size != 5 {
+		t.Fatalf("size = %d, want 5", w.size)
+	}
+	for i := 1; i < w.size; i++ {
+		if w.atUnlocked(i).Ts.Before(w.atUnlocked(i - 1).Ts) {
+			t.Fatalf("point %d out of order", i)
+		}
+	}
+	if hi, lo := QtyLoz(w.HighestPrice.Load()).Float(w.priceScale), QtyLoz(w.LowestPrice.Load()).Float(w.priceScale); hi != 105 || lo != 100 {
+		t.Fatalf("high/low = %v/%v", hi, lo)
+	}
+}
@@ -0,0 +1,241 @@
+package sliding_window
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrDeltaGap 增量与备机当前镜像的版本 / 位置对不上，需要用 DeltaSince(0) 做一次全量同步
+var ErrDeltaGap = errors.New("sliding_window: delta does not follow mirrored version")
+
+const defaultReplJournal = 1024
+
+// replMark 某个版本发布时的存储位置：点的绝对下标 = 累计出窗数 + 窗口内下标
+type replMark struct {
+	version uint64
+	head    uint64 // 第一个点的绝对下标
+	tail    uint64 // 最后一个点之后的绝对下标
+	dirty   uint64 // 上个版本以来被原地改写的最小绝对下标，MaxUint64 表示没有
+}
+
+// replJournal 主机侧的版本日志，备机侧记录镜像到的主机版本
+type replJournal struct {
+	head   uint64
+	dirty  uint64
+	marks  []replMark // 环形，按版本递增
+	next   int
+	full   bool
+	mirror uint64 // 备机：已镜像的主机版本
+}
+
+// StateDelta 两个版本之间的窗口变化：头部出窗的点数，以及从绝对下标 From 开始的尾部点
+// （包含新写入的点和被原地改写过的点，比如游程合并、同时间戳合并、压缩）
+type StateDelta struct {
+	FromVersion uint64        `json:"from_version"`
+	ToVersion   uint64        `json:"to_version"`
+	Full        bool          `json:"full,omitempty"` // 全量：备机清空后按 Points 重建
+	Head        uint64        `json:"head"`           // 应用后第一个点的绝对下标
+	Evict       int           `json:"evict"`          // 头部出窗点数
+	From        uint64        `json:"from"`           // Points[0] 的绝对下标，备机丢弃自己 >= From 的点
+	NewFrom     uint64        `json:"new_from"`       // 首个新成交的绝对下标，之前的是改写过的旧点
+	Points      []WindowPoint `json:"points"`
+}
+
+// EnableReplication 在主机窗口上开启版本日志（写锁），保留最近 journal 个版本（<= 0 时取 1024），
+// 之后 DeltaSince 可以给出增量；未开启或版本已滚出日志时 DeltaSince 给出全量
+func (w *SlidingWindow) EnableReplication(journal int) {
+	if journal <= 0 {
+		journal = defaultReplJournal
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.repl = &replJournal{dirty: math.MaxUint64, marks: make([]replMark, journal)}
+	w.recordReplUnlocked()
+}
+
+// recordReplUnlocked 版本号递增后记录当前位置（要求持有写锁）
+func (w *SlidingWindow) recordReplUnlocked() {
+	r := w.repl
+	if r == nil || len(r.marks) == 0 {
+		return
+	}
+	r.marks[r.next] = replMark{
+		version: w.version.Load(),
+		head:    r.head,
+		tail:    r.head + uint64(w.size),
+		dirty:   r.dirty,
+	}
+	r.dirty = math.MaxUint64
+	r.next++
+	if r.next == len(r.marks) {
+		r.next, r.full = 0, true
+	}
+}
+
+// markDirtyUnlocked 窗口内下标 i 的点被原地改写（要求持有写锁）
+func (w *SlidingWindow) markDirtyUnlocked(i int) {
+	if r := w.repl; r != nil {
+		r.dirty = min(r.dirty, r.head+uint64(i))
+	}
+}
+
+// resetReplJournalUnlocked 窗口历史被整体改写（如 Backfill）后丢弃日志，
+// 之后对旧版本的 DeltaSince 一律给出全量（要求持有写锁）
+func (w *SlidingWindow) resetReplJournalUnlocked() {
+	if r := w.repl; r != nil {
+		r.next, r.full = 0, false
+		r.dirty = math.MaxUint64
+	}
+}
+
+// DeltaSince 主机侧：返回从 version 到当前版本的变化（读锁）。version 不在日志里时返回全量；
+// version 比当前版本还新（备机镜像的不是这台主机）时返回 false
+func (w *SlidingWindow) DeltaSince(version uint64) (StateDelta, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	cur := w.version.Load()
+	if version > cur {
+		return StateDelta{}, false
+	}
+	d := StateDelta{FromVersion: version, ToVersion: cur}
+
+	r := w.repl
+	if r == nil {
+		return w.fullDeltaUnlocked(d), true
+	}
+	d.Head = r.head
+
+	// 从新到旧找 version 对应的位置，同时收集之后各版本的改写下标
+	dirty := r.dirty
+	n := r.next
+	if r.full {
+		n = len(r.marks)
+	}
+	var base *replMark
+	for k := 1; k <= n; k++ {
+		m := &r.marks[(r.next-k+len(r.marks))%len(r.marks)]
+		if m.version == version {
+			base = m
+			break
+		}
+		if m.version < version {
+			break
+		}
+		dirty = min(dirty, m.dirty)
+	}
+	if base == nil {
+		return w.fullDeltaUnlocked(d), true
+	}
+
+	tail := r.head + uint64(w.size)
+	d.Evict = int(r.head - base.head)
+	d.NewFrom = max(base.tail, r.head)
+	d.From = max(min(d.NewFrom, dirty), r.head)
+	d.Points = make([]WindowPoint, tail-d.From)
+	for i := range d.Points {
+		d.Points[i] = w.atUnlocked(int(d.From-r.head) + i)
+	}
+	return d, true
+}
+
+func (w *SlidingWindow) fullDeltaUnlocked(d StateDelta) StateDelta {
+	d.Full = true
+	if w.repl != nil {
+		d.Head = w.repl.head
+	}
+	d.From, d.NewFrom = d.Head, d.Head
+	d.Points = make([]WindowPoint, w.size)
+	for i := range d.Points {
+		d.Points[i] = w.atUnlocked(i)
+	}
+	return d
+}
+
+// MirroredVersion 备机侧：已镜像到的主机版本，下一次用它调用主机的 DeltaSince
+func (w *SlidingWindow) MirroredVersion() uint64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.repl == nil {
+		return 0
+	}
+	return w.repl.mirror
+}
+
+// ApplyDelta 备机侧：把主机的增量应用到本窗口（写锁）。备机的配置（容量、精度、零量策略等）
+// 应与主机一致，点不经过限速、游程等写入流程，原样镜像；增量对不上时返回 ErrDeltaGap
+func (w *SlidingWindow) ApplyDelta(d StateDelta) error {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	if w.repl == nil {
+		w.repl = &replJournal{dirty: math.MaxUint64}
+	}
+	r := w.repl
+
+	if d.Full {
+		for w.size > 0 {
+			w.evictHeadUnlocked()
+		}
+		r.head = d.Head
+	} else {
+		// 先对照当前状态校验，出错时窗口保持原样
+		tail := r.head + uint64(w.size)
+		if r.mirror != d.FromVersion || d.Evict < 0 || d.Evict > w.size ||
+			r.head+uint64(d.Evict) != d.Head || d.From < d.Head || d.From > tail {
+			return ErrDeltaGap
+		}
+		for k := 0; k < d.Evict; k++ {
+			w.evictHeadUnlocked()
+		}
+		for r.head+uint64(w.size) > d.From {
+			w.popTailUnlocked()
+		}
+	}
+
+	abs := d.From
+	for _, pt := range d.Points {
+		if w.fullUnlocked() {
+			w.counters.overflows.Add(1)
+			w.evictHeadUnlocked()
+		}
+		w.appendUnlocked(pt)
+		if abs >= d.NewFrom {
+			w.observeTradeUnlocked(pt)
+		}
+		abs++
+	}
+	r.mirror = d.ToVersion
+
+	if w.size > 0 {
+		w.LatestPrice.Store(w.lastUnlocked().Price.Int64())
+	} else {
+		w.LatestPrice.Store(0)
+	}
+	w.hiLoDirty = true
+	w.recomputeHighLowIfDirtyUnlocked()
+	w.refreshVolumeCachesUnlocked()
+	w.updateImbalanceEMAsUnlocked()
+	w.bumpVersionUnlocked()
+	return nil
+}
+
+// popTailUnlocked 移除最新的点并扣减统计（要求持有写锁，且 size > 0）
+func (w *SlidingWindow) popTailUnlocked() {
+	if w.tracked.Has(TrackTWAP) {
+		w.twapPopTailUnlocked()
+	}
+	w.applyRemovePointUnlocked(w.lastUnlocked())
+	w.size--
+	if w.seg != nil {
+		w.seg.shrink(w.size)
+	}
+	if w.tracked.Has(TrackExcursion) {
+		w.excursions.popTail()
+	}
+	w.runTailTs = time.Time{}
+}
@@ -0,0 +1,73 @@
+package sliding_window
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReplicationDelta(t *testing.T) {
+	primary := NewSlidingWindow(10*time.Second, 256, 0.1)
+	primary.EnableReplication(16)
+	primary.SetRunLength(time.Second) // 尾部原地改写也要同步
+	standby := NewSlidingWindow(10*time.Second, 256, 0.1)
+
+	t0 := time.Unix(1_700_000_000, 0)
+	for step := 0; step < 40; step++ {
+		for k := 0; k < 3; k++ {
+			i := step*3 + k
+			px := 100 + float64(i%4)
+			if k == 1 {
+				px = 100 + float64((i-1)%4) // 与上一笔同价，触发游程合并
+			}
+			primary.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*300*time.Millisecond))
+		}
+
+		d, ok := primary.DeltaSince(standby.MirroredVersion())
+		if !ok {
+			t.Fatal("delta not ok")
+		}
+		if step > 0 && (d.Full || len(d.Points) > 4) {
+			t.Fatalf("step %d: expected small incremental delta, got full=%v points=%d", step, d.Full, len(d.Points))
+		}
+		if err := standby.ApplyDelta(d); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+	}
+
+	if !reflect.DeepEqual(primary.Export(ExportOptions{}).Points, standby.Export(ExportOptions{}).Points) {
+		t.Fatal("mirrored points differ")
+	}
+	a, _ := primary.TWAP()
+	b, _ := standby.TWAP()
+	if primary.SumVolume() != standby.SumVolume() || math.Abs(a-b) > 1e-9 || primary.nTrades.Load() != standby.nTrades.Load() {
+		t.Fatalf("stats differ: twap %v/%v", a, b)
+	}
+	if issues := standby.Check(); len(issues) > 0 {
+		t.Fatalf("check: %v", issues)
+	}
+
+	// 重放旧增量对不上；落后太多的版本拿到全量
+	if err := standby.ApplyDelta(StateDelta{FromVersion: 1, ToVersion: 2}); !errors.Is(err, ErrDeltaGap) {
+		t.Fatalf("err = %v", err)
+	}
+	// 校验失败不改动备机：From 落在已出窗的位置
+	before, v := standby.SumVolume(), standby.Version()
+	d, _ := primary.DeltaSince(standby.MirroredVersion())
+	d.Evict, d.Head, d.From = 1, d.Head+1, d.Head
+	if err := standby.ApplyDelta(d); !errors.Is(err, ErrDeltaGap) {
+		t.Fatalf("err = %v", err)
+	}
+	if standby.SumVolume() != before || standby.Version() != v || standby.MirroredVersion() != d.FromVersion {
+		t.Fatal("rejected delta modified the standby")
+	}
+	if issues := standby.Check(); len(issues) > 0 {
+		t.Fatalf("check after rejected delta: %v", issues)
+	}
+
+	if d, _ := primary.DeltaSince(1); !d.Full {
+		t.Fatal("rolled-out version should give a full delta")
+	}
+}
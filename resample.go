@@ -0,0 +1,119 @@
+package sliding_window
+
+import "time"
+
+const (
+	// resampleGridPerPoint 重采样网格点数上限相对窗口点数的倍数，防止过小的步长分配巨大的网格
+	resampleGridPerPoint = 8
+	// resampleMinGrid 点数很少的窗口也至少允许这么多网格点
+	resampleMinGrid = 1024
+)
+
+// maxGridPoints 点数为 size 的窗口允许的最大网格点数
+func maxGridPoints(size int) int {
+	return max(resampleGridPerPoint*size, resampleMinGrid)
+}
+
+// gridPoints span 按 step 划分的网格点数，超过 limit 时返回 false
+func gridPoints(span, step time.Duration, limit int) (int, bool) {
+	if step <= 0 || span < 0 || span/step >= time.Duration(limit) {
+		return 0, false
+	}
+	return int(span/step) + 1, true
+}
+
+// timeRange 窗口最早/最新点的时间和点数（读锁）
+func (w *SlidingWindow) timeRange() (oldest, newest time.Time, size int, ok bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size == 0 {
+		return oldest, newest, 0, false
+	}
+	return w.atUnlocked(0).Ts, w.lastUnlocked().Ts, w.size, true
+}
+
+// resamplePricesUnlocked 在 start, start+step, ... 的网格上对价格做前向填充，结果写入 out（要求持有读锁）。
+// 网格时间早于最早点时用最早点的价格。
+func (w *SlidingWindow) resamplePricesUnlocked(start time.Time, step time.Duration, out []float64) {
+	if w.size == 0 || step <= 0 {
+		return
+	}
+
+	i := 0
+	for g := range out {
+		t := start.Add(time.Duration(g) * step)
+		for i+1 < w.size && !w.atUnlocked(i+1).Ts.After(t) {
+			i++
+		}
+		out[g] = w.atUnlocked(i).Price.Float(w.priceScale)
+	}
+}
+
+// Resample 按固定步长对窗口价格做前向填充重采样（读锁），返回网格起点和价格序列。
+// 网格点数超过窗口点数的 8 倍（且超过 1024）时视为步长过小，返回 nil
+func (w *SlidingWindow) Resample(step time.Duration) (time.Time, []float64) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size == 0 || step <= 0 {
+		return time.Time{}, nil
+	}
+
+	start := w.atUnlocked(0).Ts
+	n, ok := gridPoints(w.lastUnlocked().Ts.Sub(start), step, maxGridPoints(w.size))
+	if !ok {
+		return time.Time{}, nil
+	}
+	out := make([]float64, n)
+	w.resamplePricesUnlocked(start, step, out)
+	return start, out
+}
+
+// resampleOn 在给定网格上重采样（读锁）
+func (w *SlidingWindow) resampleOn(start time.Time, step time.Duration, n int) []float64 {
+	out := make([]float64, n)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	w.resamplePricesUnlocked(start, step, out)
+	return out
+}
+
+// commonGrid 两个窗口重叠时间段上的公共网格，点数上限按点数较多的窗口计算
+func commonGrid(a, b *SlidingWindow, step time.Duration) (start time.Time, n int, ok bool) {
+	aOld, aNew, aSize, okA := a.timeRange()
+	bOld, bNew, bSize, okB := b.timeRange()
+	if !okA || !okB || step <= 0 {
+		return start, 0, false
+	}
+
+	start = aOld
+	if bOld.After(start) {
+		start = bOld
+	}
+	end := aNew
+	if bNew.Before(end) {
+		end = bNew
+	}
+	if !end.After(start) {
+		return start, 0, false
+	}
+	n, ok = gridPoints(end.Sub(start), step, maxGridPoints(max(aSize, bSize)))
+	return start, n, ok
+}
+
+// logReturns 相邻价格的 log return，非正价格对应的收益记为 0
+func logReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+	out := make([]float64, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] > 0 && prices[i] > 0 {
+			out[i-1] = logRatio(prices[i], prices[i-1])
+		}
+	}
+	return out
+}
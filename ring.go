@@ -0,0 +1,57 @@
+package sliding_window
+
+import (
+	"slices"
+	"time"
+)
+
+// ring 固定容量的环形数组，SlidingWindow（非分段存储时）与 SeriesWindow 共用的点存储。
+// 点数由持有者维护（SlidingWindow 的分段存储与环形数组共用同一个 size），ring 只负责下标换算；不加锁
+type ring[T any] struct {
+	buf   []T
+	start int
+}
+
+func newRing[T any](capacity int) ring[T] {
+	return ring[T]{buf: make([]T, capacity)}
+}
+
+func (r *ring[T]) capacity() int { return len(r.buf) }
+
+// at 第 i 个点，0 为最旧
+func (r *ring[T]) at(i int) T { return r.buf[(r.start+i)%len(r.buf)] }
+
+func (r *ring[T]) set(i int, v T) { r.buf[(r.start+i)%len(r.buf)] = v }
+
+// spans 前 n 个点按时间顺序拆成的两段连续切片（第二段在回绕时非空），
+// 供热循环直接按下标遍历，避免逐点取模
+func (r *ring[T]) spans(n int) (head, tail []T) {
+	if r.start+n <= len(r.buf) {
+		return r.buf[r.start : r.start+n], nil
+	}
+	return r.buf[r.start:], r.buf[:r.start+n-len(r.buf)]
+}
+
+// push 在已有的 n 个点之后写入（要求 n < capacity）
+func (r *ring[T]) push(n int, v T) {
+	if n == 0 {
+		r.start = 0
+	}
+	r.buf[(r.start+n)%len(r.buf)] = v
+}
+
+// popHead 丢弃最旧的点（要求非空）
+func (r *ring[T]) popHead() { r.start = (r.start + 1) % len(r.buf) }
+
+func (r *ring[T]) clone() ring[T] {
+	return ring[T]{buf: slices.Clone(r.buf), start: r.start}
+}
+
+// expireThreshold 以 latest 为最新时间的过期边界，Ts <= 边界的点出窗；
+// duration <= 0 时没有时间边界（返回零值），只靠容量淘汰
+func expireThreshold(latest time.Time, duration time.Duration) time.Time {
+	if duration <= 0 {
+		return time.Time{}
+	}
+	return latest.Add(-duration)
+}
@@ -0,0 +1,131 @@
+package sliding_window
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RiskOverlay 结合当前持仓、窗口波动率、流动性和均衡区偏离给出单次下单规模上限。
+// 零值字段表示不启用该约束；MaxSize 与流动性上限都未启用时没有可给出的上限。
+type RiskOverlay struct {
+	MaxSize       float64       // 规模绝对上限（与成交量同单位）
+	TargetVol     float64       // 窗口 realized vol 超过该值时按 TargetVol / RV 缩小规模
+	Participation float64       // 执行期内允许占市场成交量的比例（如 0.05）
+	Horizon       time.Duration // 执行时长，与 Participation 一起决定流动性上限
+	ZoneAlpha     float64       // 均衡区参数，默认 CryptoDefaultAlpha / CryptoDefaultBeta
+	ZoneBeta      float64
+
+	mu       sync.Mutex
+	position float64
+}
+
+// SetPosition 更新当前持仓（带方向），可与 SuggestSize 并发调用
+func (o *RiskOverlay) SetPosition(pos float64) {
+	o.mu.Lock()
+	o.position = pos
+	o.mu.Unlock()
+}
+
+func (o *RiskOverlay) Position() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.position
+}
+
+// SizeSuggestion 规模建议及各项约束的分解
+type SizeSuggestion struct {
+	MaxSize      float64 `json:"max_size"`  // 综合后的单次规模上限
+	Remaining    float64 `json:"remaining"` // 扣除当前持仓后还可增加的规模
+	Position     float64 `json:"position"`
+	VolFactor    float64 `json:"vol_factor"`    // 波动率缩放 (0, 1]
+	BandFactor   float64 `json:"band_factor"`   // 均衡区偏离缩放 (0, 1]
+	LiquidityCap float64 `json:"liquidity_cap"` // 流动性上限，未启用时为 0
+	Volatility   float64 `json:"volatility"`
+	NormDist     float64 `json:"norm_dist"`
+}
+
+// SetRiskOverlay 设置（nil 关闭）规模建议的风险约束（写锁）。
+// Snapshot 中的 SuggestedSize 随窗口版本缓存，之后修改 o 的字段在下一次 Add 后生效
+func (w *SlidingWindow) SetRiskOverlay(o *RiskOverlay) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.risk = o
+	w.bumpVersionUnlocked()
+}
+
+// SuggestSize 按 RiskOverlay 计算规模上限。波动率、每秒成交量和均衡区输入在同一把读锁内取出，
+// 互相一致；排序等计算在锁外完成。
+//
+// MaxSize = min(MaxSize × VolFactor × BandFactor, LiquidityCap)，其中
+// VolFactor = min(1, TargetVol / RV)，BandFactor = 1 / (1 + |NormDist|)，
+// LiquidityCap = 每秒成交量 × Horizon × Participation。
+// RiskOverlay.MaxSize <= 0 时不设绝对上限，结果即 LiquidityCap；两者都未启用时返回 false
+func (w *SlidingWindow) SuggestSize() (SizeSuggestion, bool) {
+	if !w.IsReady() {
+		return SizeSuggestion{}, false
+	}
+
+	w.mu.RLock()
+	o := w.risk
+	if o == nil {
+		w.mu.RUnlock()
+		return SizeSuggestion{}, false
+	}
+	rv, okRv := w.realizedVolUnlocked()
+	vps := QtyLoz(w.volPerSecond.Load()).Float(w.volumeScale) * w.volumeRateConvUnlocked()
+	zin, okZone := w.gatherZoneUnlocked()
+	w.mu.RUnlock()
+
+	if !okRv || !okZone {
+		if okZone {
+			w.putPricesBuf(zin.pb)
+		}
+		return SizeSuggestion{}, false
+	}
+
+	alpha, beta := o.zoneParams()
+	zone, ok := w.computeZone(zin, alpha, beta)
+	if !ok {
+		return SizeSuggestion{}, false
+	}
+	return o.suggest(rv, vps, zone)
+}
+
+// zoneParams 均衡区参数，未设置时取 CryptoDefaultAlpha / CryptoDefaultBeta
+func (o *RiskOverlay) zoneParams() (alpha, beta float64) {
+	alpha, beta = o.ZoneAlpha, o.ZoneBeta
+	if alpha == 0 && beta == 0 {
+		alpha, beta = CryptoDefaultAlpha, CryptoDefaultBeta
+	}
+	return alpha, beta
+}
+
+// suggest 由窗口输入计算规模建议（不需要持锁）
+func (o *RiskOverlay) suggest(rv, vps float64, zone EquilibriumZone) (SizeSuggestion, bool) {
+	var out SizeSuggestion
+	out.Volatility = rv
+	out.NormDist = zone.NormDist
+	out.Position = o.Position()
+
+	out.VolFactor = 1
+	if o.TargetVol > 0 && rv > o.TargetVol {
+		out.VolFactor = o.TargetVol / rv
+	}
+	out.BandFactor = 1 / (1 + math.Abs(zone.NormDist))
+
+	size := math.Inf(1)
+	if o.MaxSize > 0 {
+		size = o.MaxSize * out.VolFactor * out.BandFactor
+	}
+	if o.Participation > 0 && o.Horizon > 0 {
+		out.LiquidityCap = vps * o.Horizon.Seconds() * o.Participation
+		size = math.Min(size, out.LiquidityCap)
+	}
+	if math.IsInf(size, 1) {
+		return SizeSuggestion{}, false
+	}
+	out.MaxSize = size
+	out.Remaining = math.Max(0, size-math.Abs(out.Position))
+	return out, true
+}
@@ -0,0 +1,65 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSuggestSize(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	if _, ok := w.SuggestSize(); ok {
+		t.Fatal("no overlay should not be ok")
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 100; i++ {
+		w.AddWindowPoint(SideBuy, 100+math.Sin(float64(i))*0.5, 2, base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	o := &RiskOverlay{MaxSize: 1000, Participation: 0.1, Horizon: 10 * time.Second}
+	o.SetPosition(-1)
+	w.SetRiskOverlay(o)
+
+	s, ok := w.SuggestSize()
+	if !ok {
+		t.Fatal("SuggestSize not ok")
+	}
+	// 每秒约 20 手，10 秒、10% 参与率 → 流动性上限约 20
+	if s.LiquidityCap <= 0 || s.MaxSize != math.Min(s.LiquidityCap, 1000*s.VolFactor*s.BandFactor) {
+		t.Fatalf("unexpected suggestion %+v", s)
+	}
+	if s.Remaining != math.Max(0, s.MaxSize-1) {
+		t.Fatalf("remaining = %v", s.Remaining)
+	}
+	if snap := w.Snapshot(); snap == nil || snap.SuggestedSize != s.MaxSize {
+		t.Fatalf("snapshot suggested size mismatch")
+	}
+}
+
+func TestSuggestSize_NoMaxSize(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 100; i++ {
+		w.AddWindowPoint(SideBuy, 100+math.Sin(float64(i))*0.5, 2, base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	// 只有流动性约束：结果即 LiquidityCap
+	w.SetRiskOverlay(&RiskOverlay{Participation: 0.1, Horizon: 10 * time.Second})
+	s, ok := w.SuggestSize()
+	if !ok || s.MaxSize <= 0 || s.MaxSize != s.LiquidityCap {
+		t.Fatalf("liquidity-only suggestion %+v ok=%v", s, ok)
+	}
+	if snap := w.Snapshot(); snap.SuggestedSize != s.MaxSize {
+		t.Fatalf("snapshot suggested size %v, want %v", snap.SuggestedSize, s.MaxSize)
+	}
+
+	// 没有任何约束：不给出上限
+	w.SetRiskOverlay(&RiskOverlay{TargetVol: 0.01})
+	if s, ok := w.SuggestSize(); ok {
+		t.Fatalf("unbounded overlay should not be ok: %+v", s)
+	}
+	if snap := w.Snapshot(); snap.SuggestedSize != 0 {
+		t.Fatalf("snapshot suggested size %v, want 0", snap.SuggestedSize)
+	}
+}
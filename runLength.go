@@ -0,0 +1,40 @@
+package sliding_window
+
+import "time"
+
+// SetRunLength 开启环内游程编码（写锁）：同价同方向、与游程首笔相距不超过 maxSpan 的连续成交
+// 合并为一个点（累加成交量，Count 记笔数），小 tick 高频品种上可以显著减少占用。
+//
+// 成交量、成交额、VWAP、TWAP、高低点、成交笔数等统计保持精确；游程以首笔时间出窗，
+// 因此窗口边界的精度为 maxSpan。单笔成交量分布（SizeQuantile）按游程计。maxSpan <= 0 关闭，
+// 已合并的点保持不变。
+func (w *SlidingWindow) SetRunLength(maxSpan time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.runSpan = max(maxSpan, 0)
+}
+
+// mergeRunUnlocked 尝试把 pt 并入尾部游程，成功返回 true（要求持有写锁）
+func (w *SlidingWindow) mergeRunUnlocked(pt WindowPoint) bool {
+	if w.runSpan <= 0 || w.size == 0 {
+		return false
+	}
+	tail := w.lastUnlocked()
+	if tail.Price != pt.Price || tail.Side != pt.Side || pt.Volume < 0 ||
+		pt.Ts.Before(tail.Ts) || pt.Ts.Sub(tail.Ts) > w.runSpan ||
+		w.isQuoteUnlocked(pt) || w.isQuoteUnlocked(tail) {
+		return false
+	}
+
+	// 先扣掉旧游程再计入合并后的游程；价格集合不变，高低点无需重算
+	dirty := w.hiLoDirty
+	w.applyRemovePointUnlocked(tail)
+	tail.Volume += pt.Volume
+	tail.Count = uint32(tail.Trades() + pt.Trades())
+	w.setUnlocked(w.size-1, tail)
+	w.applyAddPointUnlocked(tail)
+	w.hiLoDirty = dirty
+	w.runTailTs = pt.Ts
+	return true
+}
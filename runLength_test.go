@@ -0,0 +1,82 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRunLength(t *testing.T) {
+	plain := NewSlidingWindow(time.Minute, 4096, 0.1)
+	rle := NewSlidingWindow(time.Minute, 4096, 0.1)
+	rle.SetRunLength(time.Second)
+
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 1000; i++ {
+		px := 100.0
+		if i/100%2 == 1 {
+			px = 100.01
+		}
+		side := SideBuy
+		if i%300 >= 250 {
+			side = SideSell
+		}
+		ts := t0.Add(time.Duration(i) * 10 * time.Millisecond)
+		plain.AddWindowPoint(side, px, 0.5, ts)
+		rle.AddWindowPoint(side, px, 0.5, ts)
+	}
+
+	if rle.size >= plain.size/10 {
+		t.Fatalf("rle size=%d plain size=%d", rle.size, plain.size)
+	}
+	if rle.nTrades.Load() != 1000 || rle.SumVolume() != plain.SumVolume() || rle.DeltaVolume() != plain.DeltaVolume() {
+		t.Fatalf("rle trades=%d sum=%v delta=%v", rle.nTrades.Load(), rle.SumVolume(), rle.DeltaVolume())
+	}
+	v1, _ := plain.VolumeWeightedAveragePrice()
+	v2, _ := rle.VolumeWeightedAveragePrice()
+	tw1, _ := plain.TWAP()
+	tw2, _ := rle.TWAP()
+	if v1 != v2 || tw1-tw2 > 1e-9 || tw2-tw1 > 1e-9 {
+		t.Fatalf("vwap %v/%v twap %v/%v", v1, v2, tw1, tw2)
+	}
+	if !rle.Healthy() {
+		t.Fatalf("check: %v", rle.Check())
+	}
+
+	// 游程出窗后笔数同步扣减
+	rle.AddWindowPoint(SideBuy, 100, 1, t0.Add(2*time.Minute))
+	if n := rle.nTrades.Load(); n != 1 || !rle.Healthy() {
+		t.Fatalf("after expiry nTrades=%d", n)
+	}
+}
+
+func TestRunLength_VolumeFactorPerTrade(t *testing.T) {
+	plain := NewSlidingWindow(time.Minute, 4096, 0.1)
+	rle := NewSlidingWindow(time.Minute, 4096, 0.1)
+	rle.SetRunLength(time.Second)
+
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 200; i++ {
+		ts := t0.Add(time.Duration(i) * 10 * time.Millisecond)
+		v := float64(1 + i%3)
+		plain.AddWindowPoint(SideBuy, 100, v, ts)
+		rle.AddWindowPoint(SideBuy, 100, v, ts)
+	}
+	f1, ok1 := plain.VolumeFactor()
+	f2, ok2 := rle.VolumeFactor()
+	if !ok1 || !ok2 || math.Abs(f1-f2) > 1e-9 {
+		t.Fatalf("volume factor plain=%v rle=%v", f1, f2)
+	}
+	if a1, a2 := plain.AvgVolumePerPoint(), rle.AvgVolumePerPoint(); a1 != a2 {
+		t.Fatalf("avg per trade plain=%v rle=%v", a1, a2)
+	}
+
+	// 并入游程的点本身是聚合点时按其笔数累加
+	rle.Add(WindowPoint{Ts: t0.Add(2 * time.Second), Price: rle.lastUnlocked().Price, Volume: NewQtyLoz(3, rle.volumeScale), Side: SideBuy, Count: 3})
+	if n := rle.nTrades.Load(); n != 203 {
+		t.Fatalf("nTrades=%d, want 203", n)
+	}
+	if !rle.Healthy() {
+		t.Fatalf("check: %v", rle.Check())
+	}
+}
@@ -0,0 +1,113 @@
+package sliding_window
+
+import (
+	"fmt"
+	"time"
+)
+
+// HistoricalProfile 按日内时段（time-of-day）统计的历史平均成交量
+type HistoricalProfile struct {
+	Bucket   time.Duration  // 时段粒度，需要整除 24h
+	Location *time.Location // 划分交易日的时区，nil 表示 UTC
+	Avg      []float64      // 每个时段的平均成交量（真实值），长度 = 24h / Bucket
+	Sessions int            // 参与平均的交易日数
+}
+
+// NewHistoricalProfile 用过去 N 个交易日的分时段成交量构造画像，
+// sessions[i][j] 为第 i 个交易日第 j 个时段的成交量，缺失时段可以省略（按 0 计）。
+func NewHistoricalProfile(bucket time.Duration, loc *time.Location, sessions ...[]float64) (*HistoricalProfile, error) {
+	const day = 24 * time.Hour
+	if bucket <= 0 || day%bucket != 0 {
+		return nil, fmt.Errorf("bucket %v must evenly divide 24h", bucket)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	n := int(day / bucket)
+	avg := make([]float64, n)
+	for _, s := range sessions {
+		if len(s) > n {
+			return nil, fmt.Errorf("session has %d buckets, want <= %d", len(s), n)
+		}
+		for j, v := range s {
+			avg[j] += v
+		}
+	}
+	for j := range avg {
+		avg[j] /= float64(len(sessions))
+	}
+
+	return &HistoricalProfile{Bucket: bucket, Location: loc, Avg: avg, Sessions: len(sessions)}, nil
+}
+
+// Expected 估计 [from, to) 区间内的历史平均成交量（跨时段按时间比例折算）
+func (p *HistoricalProfile) Expected(from, to time.Time) float64 {
+	if p == nil || len(p.Avg) == 0 || !to.After(from) {
+		return 0
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var sum float64
+	t := from.In(loc)
+	end := to.In(loc)
+	for t.Before(end) {
+		y, m, d := t.Date()
+		midnight := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+		idx := int(t.Sub(midnight) / p.Bucket)
+		if idx >= len(p.Avg) { // DST 等导致的日长变化
+			idx = len(p.Avg) - 1
+		}
+		bucketEnd := midnight.Add(time.Duration(idx+1) * p.Bucket)
+		if bucketEnd.After(end) {
+			bucketEnd = end
+		}
+		if !bucketEnd.After(t) {
+			break
+		}
+
+		seg := bucketEnd.Sub(t)
+		sum += p.Avg[idx] * float64(seg) / float64(p.Bucket)
+		t = bucketEnd
+	}
+	return sum
+}
+
+// LoadHistoricalProfile 载入（或替换）RVOL 使用的历史分时成交量画像（写锁）
+func (w *SlidingWindow) LoadHistoricalProfile(p *HistoricalProfile) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.profile = p
+}
+
+// RVOL 相对成交量：当前窗口成交量 / 历史同一时段同样时长的平均成交量（读锁）。
+// 对有明显日内节奏的市场比基于 EMA 的 VolumeFactor 更有参考价值。
+//
+//metric:name=rvol unit=ratio cost=O(1) min_points=1 requires=historical_profile
+func (w *SlidingWindow) RVOL() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.profile == nil || w.size == 0 {
+		return 0, false
+	}
+
+	end := w.lastUnlocked().Ts
+	expected := w.profile.Expected(end.Add(-w.windowDurationUnlocked()), end)
+	if expected <= 0 {
+		return 0, false
+	}
+
+	return w.sumVolume.Float(w.volumeScale) / expected, true
+}
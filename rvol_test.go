@@ -0,0 +1,53 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRVOL(t *testing.T) {
+	hours := func(vals map[int]float64) []float64 {
+		s := make([]float64, 24)
+		for h, v := range vals {
+			s[h] = v
+		}
+		return s
+	}
+	p, err := NewHistoricalProfile(time.Hour, nil,
+		hours(map[int]float64{9: 20, 10: 60}),
+		hours(map[int]float64{9: 40, 10: 120}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Avg[9] != 30 || p.Avg[10] != 90 || p.Sessions != 2 {
+		t.Fatalf("profile: %+v", p)
+	}
+
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	// 09:30-10:30 跨两个时段：30·½ + 90·½
+	if got := p.Expected(day.Add(9*time.Hour+30*time.Minute), day.Add(10*time.Hour+30*time.Minute)); math.Abs(got-60) > 1e-9 {
+		t.Fatalf("expected = %v, want 60", got)
+	}
+
+	w := NewSlidingWindow(time.Hour, 256, 0.1)
+	for m := 0; m <= 30; m++ {
+		w.AddWindowPoint(SideBuy, 100, 4, day.Add(10*time.Hour+time.Duration(m)*time.Minute))
+	}
+	if _, ok := w.RVOL(); ok {
+		t.Fatal("rvol without a profile")
+	}
+	w.LoadHistoricalProfile(p)
+	// 窗口成交量 31·4 = 124，历史同时段 60
+	if got, ok := w.RVOL(); !ok || math.Abs(got-124.0/60) > 1e-9 {
+		t.Fatalf("rvol = %v %v, want %v", got, ok, 124.0/60)
+	}
+
+	if _, err := NewHistoricalProfile(7*time.Hour, nil, []float64{1}); err == nil {
+		t.Fatal("bucket must divide 24h")
+	}
+	if _, err := NewHistoricalProfile(time.Hour, nil); err == nil {
+		t.Fatal("profile without sessions")
+	}
+}
@@ -0,0 +1,206 @@
+package sliding_window
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+type SampleMetric uint8
+
+const (
+	SampleMedian SampleMetric = 1 << iota
+	SampleQuantile
+	SampleRealizedVol
+
+	SampleAll = SampleMedian | SampleQuantile | SampleRealizedVol
+)
+
+// SamplingConfig 超大窗口的抽样估计配置
+type SamplingConfig struct {
+	Threshold  int          // 窗口点数超过该值才抽样，默认 100_000
+	SampleSize int          // 抽样点数，默认 4096
+	Metrics    SampleMetric // 哪些指标允许抽样
+	Confidence float64      // 误差界的置信度，默认 0.95
+}
+
+func (c SamplingConfig) withDefaults() SamplingConfig {
+	if c.Threshold <= 0 {
+		c.Threshold = 100_000
+	}
+	if c.SampleSize <= 0 {
+		c.SampleSize = 4096
+	}
+	if c.Confidence <= 0 || c.Confidence >= 1 {
+		c.Confidence = 0.95
+	}
+	return c
+}
+
+// Estimate 估计值及其误差界（Sampled=false 时为精确值，ErrBound=0）
+type Estimate struct {
+	Value    float64 `json:"value"`
+	ErrBound float64 `json:"err_bound"` // 置信度下的绝对误差半宽
+	Sampled  bool    `json:"sampled"`
+	N        int     `json:"n"` // 实际参与计算的点数
+}
+
+// SetSampling 配置抽样估计（写锁），Metrics=0 表示全部走精确计算
+func (w *SlidingWindow) SetSampling(cfg SamplingConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sampling = cfg.withDefaults()
+}
+
+func (w *SlidingWindow) shouldSampleUnlocked(m SampleMetric) bool {
+	return w.sampling.Metrics&m != 0 && w.size > w.sampling.Threshold
+}
+
+// sampleRandUnlocked 以版本号做种子：同一窗口状态下的抽样结果可复现
+func (w *SlidingWindow) sampleRandUnlocked() *rand.Rand {
+	return rand.New(rand.NewPCG(w.version.Load(), uint64(w.size)))
+}
+
+// MedianEstimate 中位数估计（读锁）
+func (w *SlidingWindow) MedianEstimate() (Estimate, bool) {
+	if !w.IsReady() {
+		return Estimate{}, false
+	}
+
+	return w.quantileEstimate(0.5, SampleMedian)
+}
+
+// QuantileEstimate 价格分位数估计，q ∈ [0,1]（读锁）。
+// 抽样时误差界由 DKW 不等式给出的秩误差映射回价格。
+//
+//metric:name=price_quantile unit=price cost=O(k) min_points=1
+func (w *SlidingWindow) QuantileEstimate(q float64) (Estimate, bool) {
+	if !w.IsReady() {
+		return Estimate{}, false
+	}
+
+	return w.quantileEstimate(q, SampleQuantile)
+}
+
+func (w *SlidingWindow) quantileEstimate(q float64, m SampleMetric) (Estimate, bool) {
+	var est Estimate
+	if q < 0 || q > 1 {
+		return est, false
+	}
+
+	w.mu.RLock()
+	if w.size == 0 {
+		w.mu.RUnlock()
+		return est, false
+	}
+
+	sampled := w.shouldSampleUnlocked(m)
+	k := w.size
+	if sampled {
+		k = w.sampling.SampleSize
+	}
+	prices, pb := w.getPricesBuf(k)
+	defer w.putPricesBuf(pb)
+
+	if sampled {
+		r := w.sampleRandUnlocked()
+		for i := range prices {
+			prices[i] = w.atUnlocked(r.IntN(w.size)).Price.Float(w.priceScale)
+		}
+	} else {
+		for i := range prices {
+			prices[i] = w.atUnlocked(i).Price.Float(w.priceScale)
+		}
+	}
+	conf := w.sampling.Confidence
+	w.mu.RUnlock()
+
+	sort.Float64s(prices)
+	est.Value = quantileSorted(prices, q)
+	est.N = k
+	est.Sampled = sampled
+
+	if sampled {
+		// DKW：P(sup|F_k - F| > eps) <= 2exp(-2k eps²)
+		eps := math.Sqrt(math.Log(2/(1-conf)) / (2 * float64(k)))
+		lo := quantileSorted(prices, math.Max(0, q-eps))
+		hi := quantileSorted(prices, math.Min(1, q+eps))
+		est.ErrBound = (hi - lo) / 2
+	}
+	return est, true
+}
+
+// RealizedVolEstimate realized vol 估计（读锁）。
+// 抽样时随机取 k 个相邻 log return，用 (n-1)·mean(r²) 估计平方和，误差界用 delta 法换算到 vol。
+//
+//metric:name=realized_vol_estimate unit=return cost=O(k) min_points=2
+func (w *SlidingWindow) RealizedVolEstimate() (Estimate, bool) {
+	if !w.IsReady() {
+		return Estimate{}, false
+	}
+
+	var est Estimate
+
+	w.mu.RLock()
+	if !w.shouldSampleUnlocked(SampleRealizedVol) {
+		rv, ok := w.realizedVolUnlocked()
+		n := w.size
+		w.mu.RUnlock()
+		return Estimate{Value: rv, N: n}, ok
+	}
+
+	n := w.size
+	k := w.sampling.SampleSize
+	conf := w.sampling.Confidence
+	r := w.sampleRandUnlocked()
+
+	var sum, sumSq float64
+	used := 0
+	for j := 0; j < k; j++ {
+		i := 1 + r.IntN(n-1)
+		prev := w.atUnlocked(i - 1).Price.Float(w.priceScale)
+		cur := w.atUnlocked(i).Price.Float(w.priceScale)
+		if prev <= 0 || cur <= 0 {
+			continue
+		}
+		lr := math.Log(cur / prev)
+		x := lr * lr
+		sum += x
+		sumSq += x * x
+		used++
+	}
+	w.mu.RUnlock()
+
+	if used < 2 {
+		return est, false
+	}
+
+	mean := sum / float64(used)
+	variance := math.Max(0, sumSq/float64(used)-mean*mean)
+	total := float64(n-1) * mean
+	seTotal := float64(n-1) * math.Sqrt(variance/float64(used))
+
+	est.Value = math.Sqrt(total)
+	est.Sampled = true
+	est.N = used
+	if est.Value > 0 {
+		z := math.Sqrt2 * math.Erfinv(conf)
+		est.ErrBound = z * seTotal / (2 * est.Value)
+	}
+	return est, true
+}
+
+// quantileSorted 已排序切片上的线性插值分位数
+func quantileSorted(sorted []float64, q float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	pos := q * float64(n-1)
+	i := int(pos)
+	if i >= n-1 {
+		return sorted[n-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + (sorted[i+1]-sorted[i])*frac
+}
@@ -0,0 +1,60 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSamplingEstimates(t *testing.T) {
+	const n = 5000
+	w := NewSlidingWindow(time.Hour, n, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < n; i++ {
+		w.AddWindowPoint(SideBuy, float64(1+(i*7919)%n), 1, t0.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	exact, ok := w.MedianEstimate()
+	if !ok || exact.Sampled || exact.ErrBound != 0 || exact.N != n || exact.Value != 2500.5 {
+		t.Fatalf("exact median: %+v", exact)
+	}
+
+	w.SetSampling(SamplingConfig{Threshold: 1000, SampleSize: 2000, Metrics: SampleAll})
+	est, ok := w.MedianEstimate()
+	if !ok || !est.Sampled || est.N != 2000 || est.ErrBound <= 0 {
+		t.Fatalf("sampled median: %+v", est)
+	}
+	if d := math.Abs(est.Value - exact.Value); d > 2*est.ErrBound {
+		t.Fatalf("sampled median %v is %v away from %v, bound %v", est.Value, d, exact.Value, est.ErrBound)
+	}
+	// 同一窗口状态下抽样可复现
+	if again, _ := w.MedianEstimate(); again != est {
+		t.Fatalf("sampling not reproducible: %+v vs %+v", again, est)
+	}
+	if _, ok := w.QuantileEstimate(1.5); ok {
+		t.Fatal("q out of range")
+	}
+}
+
+func TestRealizedVolEstimate(t *testing.T) {
+	const n = 3000
+	w := NewSlidingWindow(time.Hour, n, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < n; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i%2), 1, t0.Add(time.Duration(i)*time.Millisecond))
+	}
+	exact, _ := w.RealizedVol()
+
+	// 每个 log return 的平方相同，抽样估计应与精确值一致
+	w.SetSampling(SamplingConfig{Threshold: 100, SampleSize: 500, Metrics: SampleRealizedVol})
+	est, ok := w.RealizedVolEstimate()
+	if !ok || !est.Sampled || math.Abs(est.Value-exact) > 1e-9 {
+		t.Fatalf("rv estimate %+v, exact %v", est, exact)
+	}
+
+	// 未开启该指标的抽样时走精确计算
+	w.SetSampling(SamplingConfig{Threshold: 100, Metrics: SampleMedian})
+	if est, _ = w.RealizedVolEstimate(); est.Sampled || est.Value != exact {
+		t.Fatalf("exact rv: %+v", est)
+	}
+}
@@ -0,0 +1,97 @@
+package sliding_window
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// AlignedTick 调度器的一次输出
+type AlignedTick struct {
+	Boundary time.Time            // 对齐的时钟边界（如每秒的 .000）
+	Late     time.Duration        // 实际触发相对边界的延迟
+	CatchUp  bool                 // 是否为补发的历史边界
+	Snaps    map[string]*Snapshot // 各 symbol 快照，Ts / BatchTs 均为 Boundary
+}
+
+// AlignedScheduler 在时钟边界（period 的整数倍，按 Unix 时间对齐）上对 manager 做快照，
+// 使多个进程产出的快照流可以按时间戳逐条对齐比较。
+//
+// 如果一次触发晚于下一个边界（GC、回调阻塞等），CatchUp 为 true 时逐个补发错过的边界
+// （内容为补发时刻的数据，AlignedTick.CatchUp 标记为 true），否则直接跳到最近的边界，
+// 跳过的边界计入 Missed。
+type AlignedScheduler struct {
+	m       *Manager
+	period  time.Duration
+	fn      func(AlignedTick)
+	CatchUp bool
+
+	missed atomic.Int64
+	run    runner
+}
+
+// NewAlignedScheduler period 为对齐周期，fn 在调度协程中同步调用
+func NewAlignedScheduler(m *Manager, period time.Duration, fn func(AlignedTick)) *AlignedScheduler {
+	if period <= 0 {
+		period = time.Second
+	}
+	return &AlignedScheduler{m: m, period: period, fn: fn}
+}
+
+// Missed 未补发而被跳过的边界数
+func (s *AlignedScheduler) Missed() int64 { return s.missed.Load() }
+
+// nextBoundary 严格晚于 t 的下一个边界
+func (s *AlignedScheduler) nextBoundary(t time.Time) time.Time {
+	return t.Truncate(s.period).Add(s.period)
+}
+
+// Start 在后台运行 Run(ctx)
+func (s *AlignedScheduler) Start(ctx context.Context) error { return s.run.start(ctx, s.Run) }
+
+// Close 停止调度并等待当前回调返回
+func (s *AlignedScheduler) Close() error { return s.run.close() }
+
+// Run 阻塞运行直到 ctx 结束
+func (s *AlignedScheduler) Run(ctx context.Context) error {
+	next := s.nextBoundary(time.Now())
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		latest := now.Truncate(s.period) // 已经过去的最近边界（>= next）
+		if s.CatchUp {
+			for b := next; b.Before(latest); b = b.Add(s.period) {
+				s.emit(b, now, true)
+			}
+		} else if n := int64(latest.Sub(next) / s.period); n > 0 {
+			s.missed.Add(n)
+		}
+		s.emit(latest, now, false)
+
+		next = latest.Add(s.period)
+		timer.Reset(time.Until(next))
+	}
+}
+
+func (s *AlignedScheduler) emit(boundary, now time.Time, catchUp bool) {
+	snaps := s.m.SnapshotMany(nil)
+	ms := boundary.UnixMilli()
+	for _, snap := range snaps {
+		snap.Ts = ms
+		snap.BatchTs = ms
+	}
+	s.fn(AlignedTick{
+		Boundary: boundary,
+		Late:     now.Sub(boundary),
+		CatchUp:  catchUp,
+		Snaps:    snaps,
+	})
+}
@@ -0,0 +1,36 @@
+package sliding_window
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAlignedScheduler_Boundaries(t *testing.T) {
+	m := NewManager(func(string) *SlidingWindow { return NewSlidingWindow(time.Minute, 64, 0.1) })
+	w := m.GetOrCreate("BTC")
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, now.Add(time.Duration(i-5)*time.Millisecond))
+	}
+
+	const period = 20 * time.Millisecond
+	ticks := make(chan AlignedTick, 16)
+	s := NewAlignedScheduler(m, period, func(tk AlignedTick) { ticks <- tk })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*period)
+	defer cancel()
+	go s.Run(ctx)
+
+	tk := <-ticks
+	if tk.Boundary.UnixNano()%int64(period) != 0 {
+		t.Fatalf("boundary %v not aligned to %v", tk.Boundary, period)
+	}
+	snap := tk.Snaps["BTC"]
+	if snap == nil || snap.Ts != tk.Boundary.UnixMilli() {
+		t.Fatalf("snapshot not stamped with boundary: %+v", snap)
+	}
+	if tk.Late < 0 {
+		t.Fatalf("negative lateness %v", tk.Late)
+	}
+}
@@ -0,0 +1,111 @@
+package sliding_window
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScoreClamp ScoreWithMomentumDetail 各输入的截断范围，每个零值字段各自取默认值：下界 -1、上界 1
+type ScoreClamp struct {
+	DirMin, DirMax   float64 // 价格方向因子
+	MomMin, MomMax   float64 // 动量因子
+	FlowMin, FlowMax float64 // 订单流置信
+}
+
+func (c ScoreClamp) withDefaults() ScoreClamp {
+	for _, lo := range []*float64{&c.DirMin, &c.MomMin, &c.FlowMin} {
+		if *lo == 0 {
+			*lo = -1
+		}
+	}
+	for _, hi := range []*float64{&c.DirMax, &c.MomMax, &c.FlowMax} {
+		if *hi == 0 {
+			*hi = 1
+		}
+	}
+	return c
+}
+
+// Saturation 一个输入的截断情况
+type Saturation struct {
+	Raw       float64 `json:"raw"`       // 截断前
+	Clamped   float64 `json:"clamped"`   // 截断后
+	Saturated bool    `json:"saturated"` // 是否被截断
+	Excess    float64 `json:"excess"`    // 超出边界的部分（带符号），未截断时为 0
+}
+
+func saturate(x, lo, hi float64) Saturation {
+	s := Saturation{Raw: x, Clamped: x}
+	switch {
+	case x > hi:
+		s.Clamped = hi
+	case x < lo:
+		s.Clamped = lo
+	}
+	s.Excess = x - s.Clamped
+	s.Saturated = s.Excess != 0
+	return s
+}
+
+// ScoreDetail ScoreWithMomentum 的分解结果
+type ScoreDetail struct {
+	Score      float64    `json:"score"`
+	Dir        Saturation `json:"dir"`         // 价格方向因子 = 窗口收益率 / dirScale
+	Mom        Saturation `json:"mom"`         // 动量因子 = currentMomentum / momentumScale
+	Flow       Saturation `json:"flow"`        // 订单流置信
+	Trend      float64    `json:"trend"`       // 0.5·Dir + 0.5·Mom
+	ConfWeight float64    `json:"conf_weight"` // 订单流对趋势方向的认可度 [0, 1]
+}
+
+// AnySaturated 是否有输入被截断
+func (d ScoreDetail) AnySaturated() bool {
+	return d.Dir.Saturated || d.Mom.Saturated || d.Flow.Saturated
+}
+
+// ScoreWithMomentumDetail 与 ScoreWithMomentum 相同的综合得分，同时报告每个输入是否被截断、
+// 超出多少，便于调参；clamp 为 nil 时使用默认截断范围 [-1, 1]（结果与 ScoreWithMomentum 一致）
+func (w *SlidingWindow) ScoreWithMomentumDetail(currentMomentum, dirScale, momentumScale, orderFlowConfidence float64, clamp *ScoreClamp) (ScoreDetail, error) {
+	var d ScoreDetail
+	if dirScale <= 1e-6 || momentumScale <= 1e-6 {
+		return d, fmt.Errorf("the dir scale or momentum scale is zero,%.2f,%.2f\n", dirScale, momentumScale)
+	}
+	var c ScoreClamp
+	if clamp != nil {
+		c = *clamp
+	}
+	c = c.withDefaults()
+
+	// 为保证一致性，需要在同一个读锁内读取 Snapshot 和 Momentum 所需字段
+	w.mu.RLock()
+	if w.size < 2 {
+		n := w.size
+		w.mu.RUnlock()
+		return d, fmt.Errorf("the momentum size is too small,%d\n", n)
+	}
+	pOld := w.atUnlocked(0).Price.Float(w.priceScale)
+	pNew := w.lastUnlocked().Price.Float(w.priceScale)
+	w.mu.RUnlock()
+
+	// 价格侧方向（避免除以 0）
+	var side float64
+	if pOld != 0 {
+		side = (pNew - pOld) / pOld
+	}
+
+	d.Dir = saturate(side/dirScale, c.DirMin, c.DirMax)
+	d.Mom = saturate(currentMomentum/momentumScale, c.MomMin, c.MomMax)
+	d.Flow = saturate(orderFlowConfidence, c.FlowMin, c.FlowMax)
+
+	d.Trend = 0.5*d.Dir.Clamped + 0.5*d.Mom.Clamped
+	if math.Abs(d.Trend) < 1e-8 {
+		return d, nil
+	}
+
+	trendSign := 1.0
+	if d.Trend < 0 {
+		trendSign = -1.0
+	}
+	d.ConfWeight = min(max((1+trendSign*d.Flow.Clamped)/2, 0), 1)
+	d.Score = trendSign * math.Abs(d.Trend) * d.ConfWeight
+	return d, nil
+}
@@ -0,0 +1,40 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreWithMomentumDetail(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	w.AddWindowPoint(SideBuy, 100, 1, t0)
+	w.AddWindowPoint(SideBuy, 110, 1, t0.Add(time.Second)) // +10%，远超 dirScale
+
+	d, err := w.ScoreWithMomentumDetail(0.05, 0.05, 0.1, 1.5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, _ := w.ScoreWithMomentum(0.05, 0.05, 0.1, 1.5)
+	if d.Score != score {
+		t.Fatalf("detail score %v != %v", d.Score, score)
+	}
+	if !d.Dir.Saturated || d.Dir.Clamped != 1 || d.Dir.Excess < 0.99 || d.Mom.Saturated || !d.Flow.Saturated {
+		t.Fatalf("saturation: %+v", d)
+	}
+	// Trend = 0.5·1 + 0.5·0.5，订单流完全认可
+	if d.Trend != 0.75 || d.ConfWeight != 1 || d.Score != 0.75 {
+		t.Fatalf("detail: %+v", d)
+	}
+
+	wide, _ := w.ScoreWithMomentumDetail(0.05, 0.05, 0.1, 0.5, &ScoreClamp{DirMin: -3, DirMax: 3})
+	if wide.Dir.Saturated || wide.Dir.Clamped <= 1.9 || wide.AnySaturated() {
+		t.Fatalf("custom clamp: %+v", wide)
+	}
+
+	// 只设上界时下界仍取默认 -1
+	c := ScoreClamp{DirMax: 3, FlowMin: 0.2}.withDefaults()
+	if c.DirMin != -1 || c.DirMax != 3 || c.FlowMin != 0.2 || c.FlowMax != 1 || c.MomMin != -1 || c.MomMax != 1 {
+		t.Fatalf("defaults: %+v", c)
+	}
+}
@@ -0,0 +1,172 @@
+package sliding_window
+
+import "time"
+
+// segmentSpare 分段存储最多保留的空闲段数，负载回落时多余的段交给 GC
+const segmentSpare = 2
+
+// segmentStore 链式定长分段存储：点数随负载增长，不设容量上限；
+// 头段读空后回收，尾段写满后追加新段
+type segmentStore struct {
+	segSize int
+	segs    [][]WindowPoint // 活跃段，segs[0] 为头段
+	head    int             // 头段内第一个有效点的偏移
+	spare   [][]WindowPoint // 回收待复用的段
+}
+
+func newSegmentStore(segSize int) *segmentStore {
+	if segSize <= 0 {
+		segSize = 1024
+	}
+	return &segmentStore{segSize: segSize}
+}
+
+func (s *segmentStore) at(i int) WindowPoint {
+	j := s.head + i
+	return s.segs[j/s.segSize][j%s.segSize]
+}
+
+func (s *segmentStore) set(i int, pt WindowPoint) {
+	j := s.head + i
+	s.segs[j/s.segSize][j%s.segSize] = pt
+}
+
+// push 在第 size 个位置追加（size 为当前点数）
+func (s *segmentStore) push(pt WindowPoint, size int) {
+	if size == 0 {
+		s.head = 0
+	}
+	j := s.head + size
+	if j/s.segSize == len(s.segs) {
+		s.segs = append(s.segs, s.alloc())
+	}
+	s.segs[j/s.segSize][j%s.segSize] = pt
+}
+
+// popHead 丢弃头部一个点；头段读空时回收
+func (s *segmentStore) popHead() {
+	s.head++
+	if s.head < s.segSize {
+		return
+	}
+	s.release(s.segs[0])
+	n := copy(s.segs, s.segs[1:])
+	s.segs[n] = nil
+	s.segs = s.segs[:n]
+	s.head = 0
+}
+
+// shrink 点数缩减到 size 后回收尾部多余的段
+func (s *segmentStore) shrink(size int) {
+	need := (s.head + size + s.segSize - 1) / s.segSize
+	for len(s.segs) > need {
+		n := len(s.segs) - 1
+		s.release(s.segs[n])
+		s.segs[n] = nil
+		s.segs = s.segs[:n]
+	}
+}
+
+// priceRange 前 size 个点的最高 / 最低价（ticks，要求 size > 0）
+func (s *segmentStore) priceRange(size int) (hi, lo int64) {
+	hi, lo = s.at(0).Price.Int64(), s.at(0).Price.Int64()
+	j := s.head
+	for _, seg := range s.segs {
+		end := min(j+size, len(seg))
+		hi, lo = priceRangeOf(seg[j:end], hi, lo)
+		size -= end - j
+		j = 0
+	}
+	return hi, lo
+}
+
+func (s *segmentStore) alloc() []WindowPoint {
+	if n := len(s.spare); n > 0 {
+		seg := s.spare[n-1]
+		s.spare[n-1] = nil
+		s.spare = s.spare[:n-1]
+		return seg
+	}
+	return make([]WindowPoint, s.segSize)
+}
+
+func (s *segmentStore) release(seg []WindowPoint) {
+	if len(s.spare) < segmentSpare {
+		s.spare = append(s.spare, seg)
+	}
+}
+
+// capacity 当前已分配的活跃槽位数
+func (s *segmentStore) capacity() int {
+	return len(s.segs) * s.segSize
+}
+
+// NewSlidingWindowSegmented 与 NewSlidingWindow 相同，但使用链式分段存储代替固定容量的环形数组：
+// 点数只受时间窗口约束，段随到达率增减（每段 segmentSize 个点，<= 0 时取 1024）。
+// 适合日内到达率相差百倍、难以选定固定容量的品种；代价是按下标访问多一次除法
+func NewSlidingWindowSegmented(duration time.Duration, segmentSize int, emaAlpha float64) *SlidingWindow {
+	return New(duration, 0, WithEMAAlpha(emaAlpha), WithSegments(segmentSize))
+}
+
+// --- 存储后端的统一访问（要求持有锁） ---
+
+// capacityUnlocked 当前容量：环形数组为固定容量，分段存储为已分配槽位
+func (w *SlidingWindow) capacityUnlocked() int {
+	if w.seg != nil {
+		return w.seg.capacity()
+	}
+	return w.ring.capacity()
+}
+
+// fullUnlocked 是否需要覆盖最旧点才能写入（分段存储永远不满）
+func (w *SlidingWindow) fullUnlocked() bool {
+	return w.seg == nil && w.size == w.ring.capacity()
+}
+
+func (w *SlidingWindow) setUnlocked(i int, pt WindowPoint) {
+	w.markDirtyUnlocked(i)
+	w.excursionSetUnlocked(i, pt)
+	if w.seg != nil {
+		w.seg.set(i, pt)
+		return
+	}
+	w.ring.set(i, pt)
+}
+
+// pushUnlocked 尾部写入一个点（要求持有写锁，且未满）
+func (w *SlidingWindow) pushUnlocked(pt WindowPoint) {
+	if w.seg != nil {
+		w.seg.push(pt, w.size)
+	} else {
+		w.ring.push(w.size, pt)
+	}
+	w.size++
+	w.excursionPushUnlocked(pt)
+}
+
+// popHeadUnlocked 丢弃最旧的点（要求持有写锁，且 size > 0）
+func (w *SlidingWindow) popHeadUnlocked() {
+	if w.seg != nil {
+		w.seg.popHead()
+	} else {
+		w.ring.popHead()
+	}
+	w.size--
+	if w.repl != nil {
+		w.repl.head++
+	}
+	if w.tracked.Has(TrackExcursion) {
+		w.excursions.popHead()
+	}
+}
+
+// segmentStats 分段存储的占用情况，环形数组时返回 false
+func (w *SlidingWindow) segmentStats() (segments, spare int, ok bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.seg == nil {
+		return 0, 0, false
+	}
+	return len(w.seg.segs), len(w.seg.spare), true
+}
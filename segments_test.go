@@ -0,0 +1,56 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSegmentedStorage(t *testing.T) {
+	seg := NewSlidingWindowSegmented(10*time.Second, 8, 0.1)
+	ring := NewSlidingWindow(10*time.Second, 4096, 0.1)
+	t0 := time.Unix(1700000000, 0)
+
+	add := func(i int, ts time.Time) {
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		px := 100 + float64(i%17)*0.1
+		seg.AddWindowPoint(side, px, 1, ts)
+		ring.AddWindowPoint(side, px, 1, ts)
+	}
+
+	// 安静期：每秒 1 笔
+	for i := 0; i < 30; i++ {
+		add(i, t0.Add(time.Duration(i)*time.Second))
+	}
+	quiet, _, _ := seg.segmentStats()
+
+	// 爆发期：每秒 100 笔
+	burst0 := t0.Add(30 * time.Second)
+	for i := 0; i < 800; i++ {
+		add(i, burst0.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	peak, _, _ := seg.segmentStats()
+	if seg.size != ring.size || seg.size <= 8 || peak <= quiet {
+		t.Fatalf("len seg=%d ring=%d segments quiet=%d peak=%d", seg.size, ring.size, quiet, peak)
+	}
+
+	s1, s2 := seg.Snapshot(), ring.Snapshot()
+	if s1.VolumeWeightedAveragePrice != s2.VolumeWeightedAveragePrice || s1.MedianPrice != s2.MedianPrice ||
+		s1.HighestPrice != s2.HighestPrice || s1.LowestPrice != s2.LowestPrice || s1.NTrades != s2.NTrades {
+		t.Fatalf("segmented %+v\nring %+v", s1, s2)
+	}
+	if !seg.Healthy() {
+		t.Fatalf("check: %v", seg.Check())
+	}
+
+	// 回到安静期：段随过期点回收
+	for i := 0; i < 30; i++ {
+		add(i, burst0.Add(20*time.Second+time.Duration(i)*time.Second))
+	}
+	after, spare, _ := seg.segmentStats()
+	if after >= peak || spare > segmentSpare {
+		t.Fatalf("segments after=%d peak=%d spare=%d", after, peak, spare)
+	}
+}
@@ -0,0 +1,227 @@
+package sliding_window
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Number SeriesWindow 支持的数值类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// SeriesPoint 通用序列中的一个点
+type SeriesPoint[T Number] struct {
+	Ts    time.Time `json:"ts"`
+	Value T         `json:"value"`
+}
+
+// SeriesStats 通用序列窗口的统计
+type SeriesStats[T Number] struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	Mean  float64 `json:"mean"`
+	Std   float64 `json:"std"` // 总体标准差
+	Min   T       `json:"min"`
+	Max   T       `json:"max"`
+	Last  T       `json:"last"`
+}
+
+// SeriesWindow 任意数值序列（资金费率、价差、延迟等）的时间滑动窗口。
+// 与 SlidingWindow 共用环形存储和过期边界：按最新点时间剔除超过 duration 的点，环满时覆盖最旧点，
+// duration <= 0 时只按容量淘汰；没有价格/成交量语义
+type SeriesWindow[T Number] struct {
+	mu       sync.RWMutex
+	duration time.Duration
+	ring     ring[SeriesPoint[T]]
+	size     int
+
+	sum       compensated
+	moments   rollingMoments
+	hi, lo    T
+	hiLoDirty bool
+}
+
+func NewSeriesWindow[T Number](duration time.Duration, capacity int) *SeriesWindow[T] {
+	return &SeriesWindow[T]{
+		duration: duration,
+		ring:     newRing[SeriesPoint[T]](max(capacity, 1)),
+	}
+}
+
+func (s *SeriesWindow[T]) atUnlocked(i int) SeriesPoint[T] {
+	return s.ring.at(i)
+}
+
+// Add 添加一个点并清理过期点（写锁）；早于窗口左边界的点被丢弃
+func (s *SeriesWindow[T]) Add(v T, ts time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := ts
+	if s.size > 0 {
+		if last := s.atUnlocked(s.size - 1).Ts; last.After(ts) {
+			latest = last
+		}
+	}
+	threshold := expireThreshold(latest, s.duration)
+	if !threshold.IsZero() && !ts.After(threshold) {
+		return
+	}
+
+	if s.size == s.ring.capacity() {
+		s.evictHeadUnlocked()
+	}
+	s.ring.push(s.size, SeriesPoint[T]{Ts: ts, Value: v})
+	s.size++
+
+	f := float64(v)
+	s.sum.add(f)
+	s.moments.add(f)
+	if s.size == 1 || (!s.hiLoDirty && v > s.hi) {
+		s.hi = v
+	}
+	if s.size == 1 || (!s.hiLoDirty && v < s.lo) {
+		s.lo = v
+	}
+
+	for s.size > 0 && !s.atUnlocked(0).Ts.After(threshold) {
+		s.evictHeadUnlocked()
+	}
+	// 与 SlidingWindow 相同，极值在写锁内补算，读者只需读锁
+	s.recomputeHighLowUnlocked()
+}
+
+// evictHeadUnlocked 移除最旧的点（要求持有写锁，且 size > 0）
+func (s *SeriesWindow[T]) evictHeadUnlocked() {
+	old := s.atUnlocked(0)
+	s.ring.popHead()
+	s.size--
+
+	if s.size == 0 {
+		// 清空时顺便消除浮点累计误差
+		s.sum, s.moments = compensated{}, rollingMoments{}
+		s.hiLoDirty = false
+		return
+	}
+	f := float64(old.Value)
+	s.sum.add(-f)
+	s.moments.remove(f)
+	if old.Value == s.hi || old.Value == s.lo {
+		s.hiLoDirty = true
+	}
+}
+
+// recomputeHighLowUnlocked 最旧点恰为极值出窗后重扫（要求持有写锁）
+func (s *SeriesWindow[T]) recomputeHighLowUnlocked() {
+	if !s.hiLoDirty || s.size == 0 {
+		return
+	}
+	s.hi, s.lo = s.atUnlocked(0).Value, s.atUnlocked(0).Value
+	for i := 1; i < s.size; i++ {
+		v := s.atUnlocked(i).Value
+		s.hi = max(s.hi, v)
+		s.lo = min(s.lo, v)
+	}
+	s.hiLoDirty = false
+}
+
+// Len 窗口内点数（读锁）
+func (s *SeriesWindow[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.size
+}
+
+// Last 最新的点（读锁）
+func (s *SeriesWindow[T]) Last() (SeriesPoint[T], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.size == 0 {
+		return SeriesPoint[T]{}, false
+	}
+	return s.atUnlocked(s.size - 1), true
+}
+
+// Points 窗口内所有点的拷贝，按时间升序（读锁）
+func (s *SeriesWindow[T]) Points() []SeriesPoint[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]SeriesPoint[T], s.size)
+	for i := range out {
+		out[i] = s.atUnlocked(i)
+	}
+	return out
+}
+
+// Stats 窗口统计（读锁），窗口为空时返回 false
+func (s *SeriesWindow[T]) Stats() (SeriesStats[T], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.size == 0 {
+		return SeriesStats[T]{}, false
+	}
+
+	sum := s.sum.value()
+	return SeriesStats[T]{
+		Count: s.size,
+		Sum:   sum,
+		Mean:  sum / float64(s.size),
+		Std:   math.Sqrt(s.moments.variance()),
+		Min:   s.lo,
+		Max:   s.hi,
+		Last:  s.atUnlocked(s.size - 1).Value,
+	}, true
+}
+
+// Mean 均值（读锁）
+func (s *SeriesWindow[T]) Mean() (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.size == 0 {
+		return 0, false
+	}
+	return s.sum.value() / float64(s.size), true
+}
+
+// rollingMoments 可加可减的 Welford 均值 / 二阶中心矩：进窗、出窗都是 O(1)，
+// 不会像 Σx²/n − mean² 那样在均值远大于离散度时抵消掉全部有效位
+type rollingMoments struct {
+	n    int
+	mean float64
+	m2   float64 // Σ (x − mean)²
+}
+
+func (m *rollingMoments) add(x float64) {
+	m.n++
+	d := x - m.mean
+	m.mean += d / float64(m.n)
+	m.m2 += d * (x - m.mean)
+}
+
+// remove 撤销一次 add（x 必须是仍在窗口内的值）
+func (m *rollingMoments) remove(x float64) {
+	if m.n <= 1 {
+		*m = rollingMoments{}
+		return
+	}
+	m.n--
+	d := x - m.mean
+	m.mean -= d / float64(m.n)
+	m.m2 = max(m.m2-d*(x-m.mean), 0)
+}
+
+// variance 总体方差
+func (m *rollingMoments) variance() float64 {
+	if m.n == 0 {
+		return 0
+	}
+	return m.m2 / float64(m.n)
+}
@@ -0,0 +1,65 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeriesWindow(t *testing.T) {
+	type Latency time.Duration
+	s := NewSeriesWindow[Latency](10*time.Second, 4)
+	t0 := time.Unix(1700000000, 0)
+
+	if _, ok := s.Stats(); ok {
+		t.Fatal("empty")
+	}
+	for i, v := range []Latency{5, 9, 1, 3} {
+		s.Add(v, t0.Add(time.Duration(i)*time.Second))
+	}
+	st, _ := s.Stats()
+	if st.Count != 4 || st.Min != 1 || st.Max != 9 || st.Sum != 18 || st.Last != 3 {
+		t.Fatalf("stats: %+v", st)
+	}
+
+	// 环满覆盖最旧点，再按时间淘汰
+	s.Add(4, t0.Add(4*time.Second))
+	s.Add(2, t0.Add(12*time.Second))
+	st, _ = s.Stats()
+	if st.Count != 3 || st.Min != 2 || st.Max != 4 || math.Abs(st.Mean-3) > 1e-12 {
+		t.Fatalf("after eviction: %+v %v", st, s.Points())
+	}
+
+	f := NewSeriesWindow[float64](time.Minute, 16)
+	f.Add(0.0001, t0)
+	f.Add(-0.0003, t0.Add(time.Second))
+	if m, _ := f.Mean(); math.Abs(m+0.0001) > 1e-15 {
+		t.Fatalf("mean = %v", m)
+	}
+}
+
+func TestSeriesWindow_StdLargeOffset(t *testing.T) {
+	// 均值远大于离散度时 Σx²/n − mean² 会抵消掉全部有效位
+	s := NewSeriesWindow[float64](time.Minute, 8)
+	t0 := time.Unix(1700000000, 0)
+	for i := 0; i < 200; i++ {
+		s.Add(1e9+float64(i%2), t0.Add(time.Duration(i)*time.Second))
+	}
+	st, _ := s.Stats()
+	if st.Count != 8 || math.Abs(st.Std-0.5) > 1e-6 {
+		t.Fatalf("std = %v (count %d), want 0.5", st.Std, st.Count)
+	}
+}
+
+func TestSeriesWindow_ZeroDuration(t *testing.T) {
+	// duration 为 0 时只按容量淘汰
+	s := NewSeriesWindow[int](0, 3)
+	t0 := time.Unix(1700000000, 0)
+	for i := 1; i <= 5; i++ {
+		s.Add(i, t0.Add(time.Duration(i)*time.Hour))
+	}
+	st, ok := s.Stats()
+	if !ok || st.Count != 3 || st.Min != 3 || st.Max != 5 || st.Sum != 12 {
+		t.Fatalf("stats: %+v %v", st, ok)
+	}
+}
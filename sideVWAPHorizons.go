@@ -0,0 +1,90 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// SideVWAP 某个时间尺度内买方 / 卖方主动成交的 VWAP
+type SideVWAP struct {
+	Horizon    time.Duration `json:"horizon"`
+	Buy        float64       `json:"buy"`
+	Sell       float64       `json:"sell"`
+	BuyVolume  float64       `json:"buy_volume"`
+	SellVolume float64       `json:"sell_volume"`
+	BuyOK      bool          `json:"buy_ok"` // 该尺度内有买方成交
+	SellOK     bool          `json:"sell_ok"`
+}
+
+// SideVWAPHorizons 一次遍历计算多个时间尺度的买卖分方向 VWAP（读锁），
+// 结果顺序与入参一致。例如 10s 卖方 VWAP 明显低于 60s 而买方 VWAP 持平，
+// 说明卖方在越来越低的价位出货、买方仍在承接。
+//
+//metric:name=side_vwap_horizons unit=price cost=O(n) min_points=1
+func (w *SlidingWindow) SideVWAPHorizons(horizons ...time.Duration) []SideVWAP {
+	out := make([]SideVWAP, len(horizons))
+	for i, h := range horizons {
+		out[i].Horizon = h
+	}
+	if len(horizons) == 0 {
+		return out
+	}
+
+	// 与 ImbalanceHorizons 相同：按 horizon 升序做后缀累加
+	order := make([]int, len(horizons))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return horizons[order[a]] < horizons[order[b]] })
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size == 0 {
+		return out
+	}
+
+	newest := w.lastUnlocked().Ts
+	var buyPV, buyV, sellPV, sellV compensated
+
+	settle := func(s *SideVWAP) {
+		if bv, bpv := buyV.value(), buyPV.value(); bv > 0 {
+			s.Buy, s.BuyVolume, s.BuyOK = bpv/bv, w.displayVolume(bv, bpv), true
+		}
+		if sv, spv := sellV.value(), sellPV.value(); sv > 0 {
+			s.Sell, s.SellVolume, s.SellOK = spv/sv, w.displayVolume(sv, spv), true
+		}
+	}
+
+	k := 0
+	for i := w.size - 1; i >= 0 && k < len(order); i-- {
+		pt := w.atUnlocked(i)
+
+		for k < len(order) && !pt.Ts.After(newest.Add(-horizons[order[k]])) {
+			settle(&out[order[k]])
+			k++
+		}
+		if k == len(order) {
+			break
+		}
+
+		if pt.Volume <= 0 {
+			continue
+		}
+		px := pt.Price.Float(w.priceScale)
+		v := pt.Volume.Float(w.volumeScale)
+		switch pt.Side {
+		case SideBuy:
+			buyPV.add(px * v)
+			buyV.add(v)
+		case SideSell:
+			sellPV.add(px * v)
+			sellV.add(v)
+		}
+	}
+
+	for ; k < len(order); k++ {
+		settle(&out[order[k]])
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSideVWAPHorizons(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	// 买方一直在 100 承接；卖方价格逐秒走低
+	for i := 0; i < 60; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		w.AddWindowPoint(SideBuy, 100, 1, ts)
+		w.AddWindowPoint(SideSell, 100-float64(i)*0.1, 2, ts)
+	}
+
+	got := w.SideVWAPHorizons(time.Minute, 10*time.Second)
+	long, short := got[0], got[1]
+	if long.Horizon != time.Minute || !long.BuyOK || !short.SellOK {
+		t.Fatalf("got %+v", got)
+	}
+	if long.Buy != 100 || short.Buy != 100 || short.BuyVolume != 10 || short.SellVolume != 20 {
+		t.Fatalf("buy side: %+v / %+v", long, short)
+	}
+	// 最近 10 秒：i = 50..59，卖价均值 100 - 5.45
+	if math.Abs(short.Sell-94.55) > 1e-9 || math.Abs(long.Sell-97.05) > 1e-9 {
+		t.Fatalf("sell vwap: long %v short %v", long.Sell, short.Sell)
+	}
+}
@@ -0,0 +1,66 @@
+package sliding_window
+
+import (
+	"sync"
+	"time"
+)
+
+type GateState uint8
+
+const (
+	GateIdle  GateState = iota // 等待条件
+	GateArmed                  // 条件成立，等待确认
+	GateFired                  // 已触发，等待条件解除
+)
+
+// SignalGate 信号闸门：条件成立时 arm，确认时只触发一次，条件方向改变后复位。
+// 用于把“连续满足的条件”变成“一次性的入场信号”，避免每次查询都重复出信号。
+type SignalGate struct {
+	mu      sync.Mutex
+	state   GateState
+	dir     int
+	armedAt time.Time
+	firedAt time.Time
+}
+
+// Step 推进一次状态机，返回本次是否触发。
+// arm: 触发条件是否成立；dir: 当前条件所在方向（+1/-1/0）；confirm: 确认条件是否成立。
+// 已 arm 或已触发时若 dir 改变，视为条件解除并复位。
+func (g *SignalGate) Step(arm bool, dir int, confirm bool, ts time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state != GateIdle && dir != g.dir {
+		g.state = GateIdle
+	}
+
+	switch g.state {
+	case GateIdle:
+		if arm && dir != 0 {
+			g.state = GateArmed
+			g.dir = dir
+			g.armedAt = ts
+		}
+	case GateArmed:
+		if confirm {
+			g.state = GateFired
+			g.firedAt = ts
+			return true
+		}
+	}
+	return false
+}
+
+func (g *SignalGate) State() GateState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// Reset 强制回到 Idle
+func (g *SignalGate) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.state = GateIdle
+	g.dir = 0
+}
@@ -0,0 +1,27 @@
+package sliding_window
+
+// lockWrite Add 系列入口加写锁，与 unlockWrite 成对使用。
+// 核心统计在每次版本递增时以双缓冲发布（见 CoreStats），读取核心统计不经过 mu
+func (w *SlidingWindow) lockWrite() {
+	w.mu.Lock()
+}
+
+// unlockWrite 取走本次写入积攒的回调事件后释放写锁，再在锁外派发
+func (w *SlidingWindow) unlockWrite() {
+	large, hook := w.takeLargeTradesUnlocked()
+	evicted, onEvict := w.takeEvictedUnlocked()
+	candles, archiver := w.takeCandlesUnlocked()
+	w.mu.Unlock()
+
+	// 回调在锁外派发，回调内可以安全地读取窗口
+	for _, ev := range large {
+		w.callHook(HookLargeTrade, func() { hook(ev) })
+	}
+	for _, pt := range evicted {
+		w.callHook(HookEvict, func() { onEvict(pt) })
+	}
+	for _, c := range candles {
+		w.callHook(HookArchiver, func() { archiver.OnBarClose(c) })
+	}
+	w.dispatchHookPanics()
+}
@@ -0,0 +1,47 @@
+package sliding_window
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoreStats_ConsistentUnderWrites(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+
+	base := time.Unix(1_700_000_000, 0)
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			cs := w.CoreStats()
+			// 一致视图：买 + 卖 == 总量（全部点都有方向）
+			if cs.BuyVolume+cs.SellVolume != cs.Volume {
+				t.Errorf("torn read: %+v", cs)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 5000; i++ {
+		side := SideBuy
+		if i%3 == 0 {
+			side = SideSell
+		}
+		w.AddValues(base.Add(time.Duration(i)*time.Millisecond).UnixMilli(), 100+float64(i%5), 1, side)
+	}
+	close(stop)
+	wg.Wait()
+
+	cs := w.CoreStats()
+	if cs.Size != 1024 || cs.Trades != 1024 || cs.Version != w.Version() {
+		t.Fatalf("unexpected core stats %+v", cs)
+	}
+}
@@ -0,0 +1,119 @@
+package sliding_window
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// sizeBucketAccum 一个成交量档位的增量统计（ticks）
+type sizeBucketAccum struct {
+	trades    int64
+	vol       int64
+	buy, sell int64
+	sumPV     compensated // Σ price·volume（真实值）
+}
+
+// sizeBuckets 按单笔成交量分档的增量统计，breaks 为档位边界（ticks，升序）
+type sizeBuckets struct {
+	breaks []int64
+	acc    []sizeBucketAccum
+}
+
+// SizeBucket 一个成交量档位的 VWAP 与主动方向统计
+type SizeBucket struct {
+	Min        float64 `json:"min"` // 档位下界（含）
+	Max        float64 `json:"max"` // 档位上界（不含），最后一档为 +Inf
+	Trades     int64   `json:"trades"`
+	Volume     float64 `json:"volume"`
+	BuyVolume  float64 `json:"buy_volume"`
+	SellVolume float64 `json:"sell_volume"`
+	Delta      float64 `json:"delta"`
+	VWAP       float64 `json:"vwap"` // 该档没有成交时为 0
+}
+
+// SetSizeBreakpoints 按单笔成交量分档（写锁），breaks 为升序的档位边界（真实成交量），
+// 例如 {1, 10} 得到 [0,1)、[1,10)、[10,+Inf) 小/中/大三档。窗口内已有的点重新归档；
+// breaks 为空关闭分档统计
+func (w *SlidingWindow) SetSizeBreakpoints(breaks []float64) error {
+	ticks := make([]int64, len(breaks))
+	for i, b := range breaks {
+		if b <= 0 || math.IsNaN(b) || (i > 0 && b <= breaks[i-1]) {
+			return errors.New("size breakpoints must be positive and strictly increasing")
+		}
+		ticks[i] = NewQtyLoz(b, w.volumeScale).Int64()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(ticks) == 0 {
+		w.sizeBuckets = nil
+		return nil
+	}
+	w.sizeBuckets = &sizeBuckets{breaks: ticks, acc: make([]sizeBucketAccum, len(ticks)+1)}
+	for i := 0; i < w.size; i++ {
+		w.applySizeBucketUnlocked(w.atUnlocked(i), 1)
+	}
+	return nil
+}
+
+// applySizeBucketUnlocked 点进窗（sign=1）/ 出窗（sign=-1）时更新分档统计（要求持有写锁）
+func (w *SlidingWindow) applySizeBucketUnlocked(pt WindowPoint, sign int64) {
+	sb := w.sizeBuckets
+	if sb == nil || w.isQuoteUnlocked(pt) {
+		return
+	}
+	v := max(pt.Volume.Int64(), 0)
+	idx, found := slices.BinarySearch(sb.breaks, v)
+	if found {
+		idx++ // 边界归入上一档（下界含）
+	}
+
+	a := &sb.acc[idx]
+	a.trades += sign * pt.Trades()
+	a.vol += sign * v
+	switch pt.Side {
+	case SideBuy:
+		a.buy += sign * v
+	case SideSell:
+		a.sell += sign * v
+	}
+	a.sumPV.add(float64(sign) * pt.Price.Float(w.priceScale) * float64(v) / float64(w.volumeScale))
+}
+
+// SizeBuckets 各成交量档位的成交量、主动买卖、delta 与 VWAP（读锁），O(档位数)。
+// 散户与大单 VWAP 的背离是经典的吸筹信号。未设置分档时返回 false
+func (w *SlidingWindow) SizeBuckets() ([]SizeBucket, bool) {
+	if !w.IsReady() {
+		return nil, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sb := w.sizeBuckets
+	if sb == nil {
+		return nil, false
+	}
+	out := make([]SizeBucket, len(sb.acc))
+	for i, a := range sb.acc {
+		b := &out[i]
+		if i > 0 {
+			b.Min = QtyLoz(sb.breaks[i-1]).Float(w.volumeScale)
+		}
+		b.Max = math.Inf(1)
+		if i < len(sb.breaks) {
+			b.Max = QtyLoz(sb.breaks[i]).Float(w.volumeScale)
+		}
+		b.Trades = a.trades
+		b.Volume = QtyLoz(a.vol).Float(w.volumeScale)
+		b.BuyVolume = QtyLoz(a.buy).Float(w.volumeScale)
+		b.SellVolume = QtyLoz(a.sell).Float(w.volumeScale)
+		b.Delta = b.BuyVolume - b.SellVolume
+		if a.vol > 0 {
+			b.VWAP = a.sumPV.value() / b.Volume
+		}
+	}
+	return out, true
+}
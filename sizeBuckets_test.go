@@ -0,0 +1,48 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSizeBuckets(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	if _, ok := w.SizeBuckets(); ok {
+		t.Fatal("buckets are off by default")
+	}
+	t0 := time.Unix(1700000000, 0)
+	// 散户在高位追买，大单在低位卖出
+	w.AddWindowPoint(SideBuy, 101, 0.5, t0)
+	w.AddWindowPoint(SideBuy, 102, 0.5, t0.Add(time.Second))
+	w.AddWindowPoint(SideSell, 100, 1, t0.Add(2*time.Second)) // 边界归入中档
+	w.AddWindowPoint(SideSell, 99, 20, t0.Add(3*time.Second))
+
+	if err := w.SetSizeBreakpoints([]float64{1, 10}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := w.SizeBuckets()
+	if len(b) != 3 || !math.IsInf(b[2].Max, 1) {
+		t.Fatalf("buckets: %+v", b)
+	}
+	if b[0].Trades != 2 || b[0].VWAP != 101.5 || b[0].Delta != 1 {
+		t.Fatalf("small: %+v", b[0])
+	}
+	if b[1].Trades != 1 || b[1].Min != 1 || b[1].SellVolume != 1 {
+		t.Fatalf("medium: %+v", b[1])
+	}
+	if b[2].VWAP != 99 || b[2].Delta != -20 {
+		t.Fatalf("large: %+v", b[2])
+	}
+
+	// 出窗后增量扣减
+	w.AddWindowPoint(SideBuy, 100, 5, t0.Add(62*time.Second))
+	b, _ = w.SizeBuckets()
+	if b[0].Trades != 0 || b[1].Trades != 1 || b[2].Trades != 1 || b[0].VWAP != 0 {
+		t.Fatalf("after eviction: %+v", b)
+	}
+
+	if err := w.SetSizeBreakpoints([]float64{5, 2}); err == nil {
+		t.Fatal("decreasing breakpoints should fail")
+	}
+}
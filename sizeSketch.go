@@ -0,0 +1,166 @@
+package sliding_window
+
+import (
+	"math"
+	"math/bits"
+)
+
+// sizeSketchSub 每个 2 倍区间的子分箱数，相对误差约 2^(1/4)-1 ≈ 19%
+const (
+	sizeSketchSub  = 4
+	sizeSketchBins = 64 * sizeSketchSub
+)
+
+// sizeSketch 单笔成交量（ticks）的对数分箱直方图，随进窗 / 出窗精确加减，
+// 用于 O(bins) 的分位数与百分位查询
+type sizeSketch struct {
+	counts [sizeSketchBins]int64
+	zeros  int64 // 成交量 <= 0 的点
+	total  int64
+}
+
+// sizeBin v > 0 的分箱：最高位所在的 2 倍区间 × sub + 区间内的子分箱
+func sizeBin(v int64) int {
+	u := uint64(v)
+	exp := 63 - bits.LeadingZeros64(u)
+	var frac uint64
+	if exp >= 2 {
+		frac = (u >> (exp - 2)) & (sizeSketchSub - 1)
+	} else {
+		frac = (u << (2 - exp)) & (sizeSketchSub - 1)
+	}
+	return exp*sizeSketchSub + int(frac)
+}
+
+// sizeBinLow 分箱的下界（ticks）
+func sizeBinLow(b int) float64 {
+	exp := b / sizeSketchSub
+	frac := b % sizeSketchSub
+	return math.Ldexp(1+float64(frac)/sizeSketchSub, exp)
+}
+
+func (s *sizeSketch) apply(v int64, sign int64) {
+	s.total += sign
+	if v <= 0 {
+		s.zeros += sign
+		return
+	}
+	s.counts[sizeBin(v)] += sign
+}
+
+// percentile v 在当前分布中的百分位 [0, 1]：低于 v 所在分箱的占比 + 同分箱的一半
+func (s *sizeSketch) percentile(v int64) (float64, bool) {
+	if s.total <= 0 {
+		return 0, false
+	}
+	if v <= 0 {
+		return float64(s.zeros) / 2 / float64(s.total), true
+	}
+	b := sizeBin(v)
+	below := s.zeros
+	for i := 0; i < b; i++ {
+		below += s.counts[i]
+	}
+	return (float64(below) + float64(s.counts[b])/2) / float64(s.total), true
+}
+
+// quantile 第 q 分位的成交量（ticks），取分箱内线性插值
+func (s *sizeSketch) quantile(q float64) (float64, bool) {
+	if s.total <= 0 || q < 0 || q > 1 {
+		return 0, false
+	}
+	rank := q * float64(s.total)
+	acc := float64(s.zeros)
+	if rank <= acc {
+		return 0, true
+	}
+	for b := range s.counts {
+		c := float64(s.counts[b])
+		if c == 0 {
+			continue
+		}
+		if acc+c >= rank {
+			lo := sizeBinLow(b)
+			hi := sizeBinLow(b + 1)
+			return lo + (hi-lo)*(rank-acc)/c, true
+		}
+		acc += c
+	}
+	return sizeBinLow(sizeSketchBins - 1), true
+}
+
+// largeTradeMinSamples 窗口内至少有这么多笔成交才触发大单提醒，避免冷启动时误报
+const largeTradeMinSamples = 20
+
+// LargeTrade 大单提醒
+type LargeTrade struct {
+	Point      WindowPoint `json:"point"`
+	Percentile float64     `json:"percentile"` // 进窗前相对窗口成交量分布的百分位
+}
+
+// SizeQuantile 窗口内单笔成交量的 q 分位（读锁，直方图近似，相对误差约 19%）
+//
+//metric:name=size_quantile unit=volume cost=O(1) min_points=1
+func (w *SlidingWindow) SizeQuantile(q float64) (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if !w.tracked.Has(TrackSizeSketch) {
+		return 0, false
+	}
+	v, ok := w.sizes.quantile(q)
+	if !ok {
+		return 0, false
+	}
+	return v / float64(w.volumeScale), true
+}
+
+// LastTradePercentile 最近一笔成交的量相对（进窗前）窗口分布的百分位（读锁）
+//
+//metric:name=last_trade_percentile unit=ratio cost=O(1) min_points=2
+func (w *SlidingWindow) LastTradePercentile() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.lastPct, w.lastPctOK
+}
+
+// SetLargeTradeHook 单笔成交量百分位 >= pct 时回调 fn（写锁），fn 为 nil 关闭；
+// 窗口内不足 20 笔时不触发。
+// 回调在写锁释放后、Add 返回前同步调用，可以安全地读取窗口。
+func (w *SlidingWindow) SetLargeTradeHook(pct float64, fn func(LargeTrade)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.largePct = pct
+	w.largeHook = fn
+}
+
+// observeSizeUnlocked 新成交进窗前计算其百分位，必要时登记大单提醒（要求持有写锁）
+func (w *SlidingWindow) observeSizeUnlocked(pt WindowPoint) {
+	if !w.tracked.Has(TrackSizeSketch) {
+		return
+	}
+	w.lastPct, w.lastPctOK = w.sizes.percentile(pt.Volume.Int64())
+	if w.lastPctOK && w.largeHook != nil && w.sizes.total >= largeTradeMinSamples && w.lastPct >= w.largePct {
+		w.pendingLarge = append(w.pendingLarge, LargeTrade{Point: pt, Percentile: w.lastPct})
+	}
+}
+
+// takeLargeTradesUnlocked 取走待派发的大单提醒（要求持有写锁）
+func (w *SlidingWindow) takeLargeTradesUnlocked() ([]LargeTrade, func(LargeTrade)) {
+	if len(w.pendingLarge) == 0 {
+		return nil, nil
+	}
+	evs := w.pendingLarge
+	w.pendingLarge = nil
+	return evs, w.largeHook
+}
@@ -0,0 +1,34 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSizeSketch_LargeTradeHook(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	var got []LargeTrade
+	w.SetLargeTradeHook(0.99, func(ev LargeTrade) {
+		// 回调在锁外，可以读窗口
+		_, _ = w.LastTradePercentile()
+		got = append(got, ev)
+	})
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 200; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1+float64(i%10)*0.1, base.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	if p, ok := w.LastTradePercentile(); !ok || p <= 0 || p >= 0.99 {
+		t.Fatalf("ordinary trade percentile = %v (ok=%v)", p, ok)
+	}
+
+	w.AddWindowPoint(SideSell, 100, 50, base.Add(3*time.Second))
+	if len(got) != 1 || got[0].Percentile != 1 || got[0].Point.Side != SideSell {
+		t.Fatalf("large trade hook: %+v", got)
+	}
+
+	q, ok := w.SizeQuantile(0.5)
+	if !ok || q < 1 || q > 2 {
+		t.Fatalf("median size = %v (ok=%v)", q, ok)
+	}
+}
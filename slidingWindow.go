@@ -1,8 +1,7 @@
 package sliding_window
 
 import (
-	"fmt"
-	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,13 +9,15 @@ import (
 
 type SlidingWindow struct {
 	duration       time.Duration // 窗口长度，比如 60 * time.Second
-	buf            []WindowPoint // 环形数组
+	ring           ring[WindowPoint] // 环形数组（分段存储时不用）
 	pricesPool     sync.Pool
-	start          int          // 头指针
 	size           int          // 当前有效元素个数
 	sumVolume      QtyLoz // 窗口内成交量总和
 	mu             sync.RWMutex // 并发安全
 	ema            *EMA
+	emaLong        *EMA     // 长期均值基准（EMA 衰减目标）
+	emaDecay       EMADecay // 安静期衰减配置
+	lastTradeTs    time.Time
 	volumeScale    QtyScale
 	priceScale     QtyScale
 	avgVolPerPoint atomic.Int64
@@ -30,23 +31,124 @@ type SlidingWindow struct {
 	SumV           atomic.Int64
 	SumPV          atomic.Int64
 	hiLoDirty      bool
+	reversionGate  SignalGate // VWAPReversion 的信号状态
+	markers        []Marker   // 外部事件标注，按时间升序，随窗口一起过期
+	profile        *HistoricalProfile // RVOL 使用的历史分时成交量画像
+	version        atomic.Uint64      // 数据版本号，每次 add 递增
+	expvarNames    []string           // PublishExpvar 发布过的名字
+	cache          atomic.Pointer[derivedMetrics] // Snapshot 派生指标缓存，两次 Snapshot 之间没有 Add 时直接复用
+	history        metricHistory      // 派生指标的滚动历史（MetricStats）
+	sampling       SamplingConfig     // 超大窗口抽样估计配置
+	skew           skewStats          // 交易所/本地时钟偏差统计
+	skewCorrect    bool               // 淘汰时是否按偏差修正
+	burstGap       time.Duration      // 分笔成交归并为同一 burst 的最大间隔
+	calendar       SessionCalendar    // 交易时段日历，nil 表示 7x24
+	counters       windowCounters     // 内部事件计数（overflow / reject / recompute）
+	volConv        atomic.Pointer[volumeConversion] // 成交量展示单位（合约乘数）
+	sumNotional    compensated                      // Σ price·volume（真实值，补偿求和）
+	buyNotional    compensated
+	sellNotional   compensated
+	arrival        arrivalTracker // 成交到达率
+	excursions     excursionQueue // 最大回撤 / 上冲的滑动窗口聚合
+	barInterval    time.Duration  // AddBar 输入 K 线周期
+	twap           twapAccum      // TWAP 增量累加器
+	archiver       Archiver       // 出窗数据归档目标
+	candles        candleBuilder  // 出窗数据的 K 线聚合
+	published      [2]seqStats    // 核心统计双缓冲
+	current        atomic.Pointer[seqStats] // 当前对读者可见的缓冲
+	slow           slowLog        // 超时 add 的环形记录
+	blend          priceBlend     // 成交价 / 标记价混合序列
+	imbEMAs        []imbalanceEMA // 已注册 alpha 的平滑失衡
+	ingest         ingestLimit    // 写入限速与超额合并
+	tracked        TrackedMetrics // 需要增量维护的结构，构造后不变
+	comps          components     // 挂载的后台组件
+	sizes          sizeSketch     // 单笔成交量直方图
+	lastPct        float64        // 最近一笔成交量的百分位
+	lastPctOK      bool
+	largePct       float64 // 大单提醒阈值（百分位）
+	largeHook      func(LargeTrade)
+	pendingLarge   []LargeTrade // 写锁释放后派发
+	risk           *RiskOverlay // SuggestSize 的风险约束
+	openRange      openingRangeState // 开盘区间
+	zeroVol        ZeroVolumePolicy  // 零量点的处理方式
+	runSpan        time.Duration     // 游程编码的最大跨度，0 表示关闭
+	runTailTs      time.Time         // 尾部游程最后一笔的时间（TWAP 结算用）
+	seg            *segmentStore     // 分段存储，nil 表示使用环形数组 buf
+	hooks          hookGuard         // 用户回调的 panic 隔离
+	volRegime      volRegime         // realized vol 的跨快照基准
+	sizeBuckets    *sizeBuckets      // 按单笔成交量分档的统计，nil 表示关闭
+	compact        *compaction       // 长窗口省内存模式，nil 表示关闭
+	dupTs          DuplicateTsPolicy // 同一时间戳多笔成交的处理方式
+	seqTail        seqGroup          // DuplicateSeq 下尾部同时间戳各点的成交序号
+	repl           *replJournal      // 主备复制的版本日志，nil 表示未开启
+	priceHist      *priceHistogram   // 近似中位数的价格直方图，nil 表示关闭
+	readyPolicy    ReadyPolicy       // 窗口级就绪条件
+	unready        atomic.Bool       // 最近一次求值未就绪（零值为就绪）
+	lifetime       lifetimeStats     // 自创建以来的累计统计
+	evict          EvictionPolicy    // 出窗方式
+	maxPoints      int               // 按点数出窗的上限，0 表示不限
+	aggression     AggressionModel   // AggressionScore 的模型，nil 为默认模型
+	reorder        reorderBuffer     // 乱序缓冲，lateness 为 0 表示关闭
+	anchored       anchoredVWAP      // 锚定 VWAP
+	dedup          tradeIDSet        // 按 TradeID 去重，max 为 0 表示关闭
+	tradeIn        tradeIngest       // AddTrades 的复用缓冲
+	clock          atomic.Pointer[clockRef] // 时间来源，nil 为系统时钟
+	tags           map[string]string        // 窗口标签，构造后不可变
+	ttlCache       metricTTLCache           // 按 TTL 缓存的指标结果
+	evictHook      func(WindowPoint)        // 出窗回调
+	pendingEvicted []WindowPoint            // 写锁释放后派发
+	pendingCandles []Candle                 // 已收盘待归档的 K 线，写锁释放后派发
+	negative       negativeVolume           // 负成交量（成交更正）的处理方式
+	own            ownFlow                  // 自有成交，随窗口一起过期
 }
 
 type pricesBuf struct {
 	b []float64
 }
 
+// NewSlidingWindow 按时间出窗的窗口，等价于 New(duration, capacity, WithEMAAlpha(emaAlpha))
 func NewSlidingWindow(duration time.Duration, capacity int, emaAlpha float64) *SlidingWindow {
+	return New(duration, capacity, WithEMAAlpha(emaAlpha))
+}
+
+// New 创建窗口，capacity 为环形数组容量，其余配置通过 Option 设置
+func New(duration time.Duration, capacity int, opts ...Option) *SlidingWindow {
+	o := defaultWindowOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.evict == EvictByCount && capacity <= 0 {
+		capacity = o.maxPoints
+	}
+	if o.segmentSize > 0 {
+		capacity = 0
+	}
+
 	w := &SlidingWindow{
 		duration:    duration,
-		buf:         make([]WindowPoint, capacity),
-		ema:         NewEMA(emaAlpha),
-		volumeScale: NewQtyScaleFromDecimals(8),
-		priceScale:  NewQtyScaleFromDecimals(4),
+		ring:        newRing[WindowPoint](capacity),
+		ema:         NewEMA(o.emaAlpha),
+		volumeScale: NewQtyScaleFromDecimals(o.volumeDecimals),
+		priceScale:  NewQtyScaleFromDecimals(o.priceDecimals),
+		sampling:    SamplingConfig{}.withDefaults(),
+		barInterval: defaultBarInterval,
+		tracked:     o.tracked,
+		evict:       o.evict,
+		maxPoints:   o.maxPoints,
+		tags:        o.tags,
+		evictHook:   o.evictHook,
+	}
+	w.history.n = defaultMetricHistory
+	if o.segmentSize > 0 {
+		w.seg = newSegmentStore(o.segmentSize)
 	}
+	w.SetClock(o.clock)
+
+	w.publishUnlocked()
 
+	poolCap := o.poolCap
 	w.pricesPool.New = func() any {
-		return &pricesBuf{b: make([]float64, 0, 256)}
+		return &pricesBuf{b: make([]float64, 0, poolCap)}
 	}
 
 	return w
@@ -54,13 +156,23 @@ func NewSlidingWindow(duration time.Duration, capacity int, emaAlpha float64) *S
 
 func (w *SlidingWindow) atUnlocked(i int) WindowPoint {
 	// i assumed in [0, w.size)
-	return w.buf[(w.start+i)%len(w.buf)]
+	if w.seg != nil {
+		return w.seg.at(i)
+	}
+	return w.ring.at(i)
 }
 
 func (w *SlidingWindow) lastUnlocked() WindowPoint {
 	return w.atUnlocked(w.size - 1)
 }
 
+// searchTsUnlocked 二分查找第一个 Ts >= t 的下标（假设窗口内时间单调），找不到返回 size
+func (w *SlidingWindow) searchTsUnlocked(t time.Time) int {
+	return sort.Search(w.size, func(i int) bool {
+		return !w.atUnlocked(i).Ts.Before(t)
+	})
+}
+
 // --- 公共方法（带锁） ---
 func (w *SlidingWindow) at(i int) WindowPoint {
 	w.mu.RLock()
@@ -82,23 +194,42 @@ func (w *SlidingWindow) Ready(minPoints int) bool {
 }
 
 // SumVolume 返回当前窗口内成交量总和（读锁）
+//
+//metric:name=sum_volume unit=volume cost=O(1) min_points=0
 func (w *SlidingWindow) SumVolume() float64 {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
-	return w.sumVolume.Float(w.volumeScale)
+	total, _, _ := w.volumesUnlocked()
+	return total
 }
 
-// AvgVolumePerPoint Window 内每个点的平均成交量（不是时间归一化的）
+// AvgVolumePerPoint Window 内每笔成交的平均成交量（不是时间归一化的；游程等聚合点按所含笔数计）
+//
+//metric:name=avg_volume_per_point unit=volume cost=O(1) min_points=1
 func (w *SlidingWindow) AvgVolumePerPoint() float64 {
 	p1 := w.avgVolPerPoint.Load()
-	return QtyLoz(p1).Float(w.volumeScale)
+	if w.volConv.Load() == nil {
+		return QtyLoz(p1).Float(w.volumeScale)
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return QtyLoz(p1).Float(w.volumeScale) * w.volumeRateConvUnlocked()
 }
 
 // VolumePerSecond 按时间归一化的成交量（每秒多少量）
+//
+//metric:name=volume_per_second unit=volume/s cost=O(1) min_points=2
 func (w *SlidingWindow) VolumePerSecond() float64 {
 	p1 := w.volPerSecond.Load()
-	return QtyLoz(p1).Float(w.volumeScale)
+	if w.volConv.Load() == nil {
+		return QtyLoz(p1).Float(w.volumeScale)
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return QtyLoz(p1).Float(w.volumeScale) * w.volumeRateConvUnlocked()
 }
 
 // ScoreWithMomentum 计算价格趋势 + 动量 + 订单流贝叶斯置信后的综合得分。
@@ -106,70 +237,11 @@ func (w *SlidingWindow) VolumePerSecond() float64 {
 // dirScale: 用于归一化方向收益率，比如 0.005 表示 0.5% 涨跌映射到 ±1。
 // momentumScale: 用于归一化动量值。
 // orderFlowConfidence: 订单流置信因子，约定在 [-1,1]
+//
+//metric:name=momentum_score unit=score cost=O(1) min_points=2
 func (w *SlidingWindow) ScoreWithMomentum(currentMomentum, dirScale, momentumScale, orderFlowConfidence float64) (float64, error) {
-	if dirScale <= 1e-6 || momentumScale <= 1e-6 {
-		return 0, fmt.Errorf("the dir scale or momentum scale is zero,%.2f,%.2f\n", dirScale, momentumScale)
-	}
-
-	// 为保证一致性，需要在同一个读锁内读取 Snapshot 和 Momentum 所需字段
-	w.mu.RLock()
-	defer w.mu.RUnlock()
-
-	if w.size < 2 {
-		return 0, fmt.Errorf("the momentum size is too small,%d\n", w.size)
-	}
-	pOld := w.atUnlocked(0).Price
-	pNew := w.lastUnlocked().Price
-
-	// 价格侧方向
-	side := pNew.Float(w.priceScale) - pOld.Float(w.priceScale)
-	if !pOld.IsZero() {
-		side = (pNew.Float(w.priceScale) - pOld.Float(w.priceScale)) / pOld.Float(w.priceScale)
-	} else {
-		// 避免除以0
-		side = 0
-	}
-	// 计算价格方向
-	dirFactor := side / dirScale
-	if dirFactor > 1 {
-		dirFactor = 1
-	} else if dirFactor < -1 {
-		dirFactor = -1
-	}
-	// 引入外部动量
-	momFactor := currentMomentum / momentumScale
-	if momFactor > 1 {
-		momFactor = 1
-	} else if momFactor < -1 {
-		momFactor = -1
-	}
-
-	trendFactor := 0.5*dirFactor + 0.5*momFactor
-	if math.Abs(trendFactor) < 1e-8 {
-		return 0, nil
-	}
-
-	if orderFlowConfidence > 1 {
-		orderFlowConfidence = 1
-	} else if orderFlowConfidence < -1 {
-		orderFlowConfidence = -1
-	}
-
-	trendSign := 1.0
-	if trendFactor < 0 {
-		trendSign = -1.0
-	}
-	strength := math.Abs(trendFactor)
-
-	confWeight := (1 + trendSign*orderFlowConfidence) / 2
-	if confWeight < 0 {
-		confWeight = 0
-	} else if confWeight > 1 {
-		confWeight = 1
-	}
-
-	score := trendSign * strength * confWeight
-	return score, nil
+	d, err := w.ScoreWithMomentumDetail(currentMomentum, dirScale, momentumScale, orderFlowConfidence, nil)
+	return d.Score, err
 }
 
 const (
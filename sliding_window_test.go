@@ -30,3 +30,19 @@ func BenchmarkEquilibriumZone(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkAddValues(b *testing.B) {
+	w := NewSlidingWindow(time.Second, 4096, 0.2)
+	base := time.Now().UnixMilli()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		side := SideBuy
+		if i&1 == 1 {
+			side = SideSell
+		}
+		w.AddValues(base+int64(i), 990+float64(i%10)*0.01, 1, side)
+	}
+}
@@ -0,0 +1,105 @@
+package sliding_window
+
+import "time"
+
+// SlowEvent 一次超过处理时限的 add
+type SlowEvent struct {
+	Ts         time.Time         `json:"ts"`             // 开始处理的本地时间
+	Duration   time.Duration     `json:"duration"`       // 持锁处理耗时
+	Points     int               `json:"points"`         // 本次写入的点数
+	Evicted    int               `json:"evicted"`        // 本次出窗的点数
+	Recomputed bool              `json:"recomputed"`     // 是否触发了 high/low 全量重算
+	Size       int               `json:"size"`           // 处理后的窗口点数
+	Tags       map[string]string `json:"tags,omitempty"` // 窗口标签
+}
+
+// slowLog 慢操作环形记录
+type slowLog struct {
+	deadline time.Duration
+	ring     []SlowEvent
+	next     int
+	full     bool
+}
+
+func (l *slowLog) record(ev SlowEvent) {
+	if len(l.ring) == 0 {
+		return
+	}
+	l.ring[l.next] = ev
+	l.next++
+	if l.next == len(l.ring) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// defaultSlowRing 慢操作环默认容量
+const defaultSlowRing = 64
+
+// SetAddDeadline 设置单次 Add 的处理时限（写锁），0 关闭。
+// 超时的 add 计入 Counters().SlowAdds，并把详情记入最近 ringSize 条慢操作（<=0 取 64）。
+func (w *SlidingWindow) SetAddDeadline(d time.Duration, ringSize int) {
+	if ringSize <= 0 {
+		ringSize = defaultSlowRing
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.slow = slowLog{deadline: d}
+	if d > 0 {
+		w.slow.ring = make([]SlowEvent, ringSize)
+	}
+}
+
+// SlowEvents 最近的慢操作，按时间升序（读锁）
+func (w *SlidingWindow) SlowEvents() []SlowEvent {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	l := &w.slow
+	if !l.full {
+		return append([]SlowEvent(nil), l.ring[:l.next]...)
+	}
+	out := make([]SlowEvent, 0, len(l.ring))
+	out = append(out, l.ring[l.next:]...)
+	return append(out, l.ring[:l.next]...)
+}
+
+// slowProbe add 开始时记录的基线
+type slowProbe struct {
+	start      time.Time
+	evicted    int64
+	recomputes int64
+}
+
+// beginSlowProbeUnlocked 未设置时限时返回零值，不调用 time.Now
+func (w *SlidingWindow) beginSlowProbeUnlocked() slowProbe {
+	if w.slow.deadline <= 0 {
+		return slowProbe{}
+	}
+	return slowProbe{
+		start:      time.Now(),
+		evicted:    w.counters.evictions.Load(),
+		recomputes: w.counters.recomputes.Load(),
+	}
+}
+
+func (w *SlidingWindow) endSlowProbeUnlocked(p slowProbe, points int) {
+	if p.start.IsZero() {
+		return
+	}
+	d := time.Since(p.start)
+	if d <= w.slow.deadline {
+		return
+	}
+	w.counters.slowAdds.Add(1)
+	w.slow.record(SlowEvent{
+		Ts:         p.start,
+		Duration:   d,
+		Points:     points,
+		Evicted:    int(w.counters.evictions.Load() - p.evicted),
+		Recomputed: w.counters.recomputes.Load() > p.recomputes,
+		Size:       w.size,
+		Tags:       w.tags,
+	})
+}
@@ -0,0 +1,28 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddDeadline_SlowEvents(t *testing.T) {
+	w := NewSlidingWindow(time.Second, 64, 0.1)
+	// 1ns 时限：每次 add 都会超时
+	w.SetAddDeadline(time.Nanosecond, 4)
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, base.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	if got := w.Counters().SlowAdds; got != 10 {
+		t.Fatalf("slow adds = %d, want 10", got)
+	}
+	evs := w.SlowEvents()
+	if len(evs) != 4 {
+		t.Fatalf("ring holds %d events, want 4", len(evs))
+	}
+	if evs[3].Size != 10 || evs[0].Size != 7 {
+		t.Fatalf("events not in chronological order: %+v", evs)
+	}
+}
@@ -1,74 +1,77 @@
 package sliding_window
 
-import (
-	"time"
-)
-
 type Snapshot struct {
-	HighestPrice               float64 `json:"highest_price"`
-	LowestPrice                float64 `json:"lowest_price"`
-	VolumeWeightedAveragePrice float64 `json:"volume_weighted_average_price"`
-	LatestPrice                float64 `json:"latest_price"`
-	TotalVolume                float64 `json:"total_volume"`
-	BuyVolume                  float64 `json:"buy_volume"`
-	SellVolume                 float64 `json:"sell_volume"`
-	DeltaVolume                float64 `json:"delta_volume"`
-	Momentum                   float64 `json:"momentum"`
-	Strength                   float64 `json:"strength"`
-	StrengthNorm               float64 `json:"strength_norm"`
-	EquPrice                   float64 `json:"equ_price"`
-	UpperBand                  float64 `json:"upper_band"`
-	LowerBand                  float64 `json:"lower_band"`
-	BandWidth                  float64 `json:"band_width"`
-	Price                      float64 `json:"price"`
-	Distance                   float64 `json:"distance"`
-	NormDist                   float64 `json:"norm_dist"`
-	NTrades                    int64   `json:"n_trades"`
-	WindowMs                   int64   `json:"window_ms"`
-	Ts                         int64   `json:"ts"`
-	DurationMs                 int64   `json:"duration_ms"`
-	Volatility                 float64 `json:"volatility"`
-	Imbalance                  float64 `json:"imbalance"`
+	HighestPrice               float64           `json:"highest_price"`
+	LowestPrice                float64           `json:"lowest_price"`
+	VolumeWeightedAveragePrice float64           `json:"volume_weighted_average_price"`
+	MedianPrice                float64           `json:"median_price"`
+	LatestPrice                float64           `json:"latest_price"`
+	TotalVolume                float64           `json:"total_volume"`
+	BuyVolume                  float64           `json:"buy_volume"`
+	SellVolume                 float64           `json:"sell_volume"`
+	DeltaVolume                float64           `json:"delta_volume"`
+	Momentum                   float64           `json:"momentum"`
+	Strength                   float64           `json:"strength"`
+	StrengthNorm               float64           `json:"strength_norm"`
+	EquPrice                   float64           `json:"equ_price"`
+	UpperBand                  float64           `json:"upper_band"`
+	LowerBand                  float64           `json:"lower_band"`
+	BandWidth                  float64           `json:"band_width"`
+	Price                      float64           `json:"price"`
+	Distance                   float64           `json:"distance"`
+	NormDist                   float64           `json:"norm_dist"`
+	NTrades                    int64             `json:"n_trades"`
+	WindowMs                   int64             `json:"window_ms"`
+	Ts                         int64             `json:"ts"`              // 计算该版本派生指标的时间
+	Version                    uint64            `json:"version"`         // 快照对应的窗口版本号
+	Stale                      bool              `json:"stale,omitempty"` // 写者持锁时复用了旧版本，Version 落后于窗口
+	DurationMs                 int64             `json:"duration_ms"`
+	Volatility                 float64           `json:"volatility"`
+	Imbalance                  float64           `json:"imbalance"`
+	TimeImbalance              float64           `json:"time_imbalance"`           // 按成交覆盖时长加权的方向失衡
+	BatchTs                    int64             `json:"batch_ts,omitempty"`       // Manager.SnapshotMany 的批次时间戳
+	SuggestedSize              float64           `json:"suggested_size,omitempty"` // 设置 RiskOverlay 时的规模上限
+	BlendedPrice               float64           `json:"blended_price,omitempty"`  // 开启价格混合时的最新混合价，Price 同样取该值
+	Tags                       map[string]string `json:"tags,omitempty"`           // 窗口标签（只读，见 WithTags）
 }
 
 func (w *SlidingWindow) Snapshot() *Snapshot {
-	highestPrice := w.HighestPrice.Load()
-	lowestPrice := w.LowestPrice.Load()
-	latestPrice := w.LatestPrice.Load()
-	nTrades := w.nTrades.Load()
+	if !w.IsReady() {
+		return nil
+	}
 
-	stat, ok := w.collectStats()
+	// 核心字段与派生指标来自同一版本，按版本号缓存，没有新 Add 时直接复用
+	d, ok := w.derived()
 	if !ok {
 		return nil
 	}
+	core := d.core
+	highestPrice := core.high
+	lowestPrice := core.low
+	latestPrice := core.latest
+	nTrades := core.trades
+	vwap, momentum, bs, ez, rv := d.vwap, d.momentum, d.bs, d.ez, d.rv
 
-	vwap, _ := w.vwap(stat)
-	momentum, _ := w.Momentum()
-	bs, _ := w.breakoutStrength(stat)
-
-	ez, _ := w.EquilibriumZone(0.4, 0.5)
-
-	// ===== 新增三项 =====
-	deltaVol := w.DeltaVolume()
-	imb := w.Imbalance()
-
-	rv, okRv := w.RealizedVol()
-	if !okRv {
-		rv = 0
-	}
+	imb := imbalanceOf(core.buy, core.sell)
 
-	totalVolume := w.sumVolume.Float(w.volumeScale)
+	// 成交量按展示单位（合约乘数）换算
+	totalVolume, buyVolume, sellVolume := w.volumesOf(core)
+	deltaVol := buyVolume - sellVolume
 
 	return &Snapshot{
 		HighestPrice:               QtyLoz(highestPrice).Float(w.priceScale),
 		LowestPrice:                QtyLoz(lowestPrice).Float(w.priceScale),
 		VolumeWeightedAveragePrice: vwap,
+		MedianPrice:                d.median,
 		LatestPrice:                QtyLoz(latestPrice).Float(w.priceScale),
 		TotalVolume:                totalVolume,
-		BuyVolume:                  float64(w.buyVol.Load()) / float64(w.volumeScale),
-		SellVolume:                 float64(w.sellVol.Load()) / float64(w.volumeScale),
+		BuyVolume:                  buyVolume,
+		SellVolume:                 sellVolume,
 		DeltaVolume:                deltaVol,
 		Imbalance:                  imb,
+		TimeImbalance:              d.timeImb,
+		SuggestedSize:              d.size,
+		BlendedPrice:               d.blended,
 		Volatility:                 rv,
 		Momentum:                   momentum,
 		Strength:                   bs.Strength,
@@ -81,8 +84,11 @@ func (w *SlidingWindow) Snapshot() *Snapshot {
 		Distance:                   ez.Distance,
 		NormDist:                   ez.NormDist,
 		NTrades:                    nTrades,
-		Ts:                         time.Now().UnixMilli(),
+		Ts:                         d.ts.UnixMilli(),
+		Version:                    d.version,
+		Stale:                      d.version < w.version.Load(),
 		WindowMs:                   w.duration.Milliseconds(),
 		DurationMs:                 w.duration.Milliseconds(),
+		Tags:                       w.tags,
 	}
 }
@@ -0,0 +1,140 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// Snapshot / Decision 使用的均衡区参数
+const (
+	snapshotZoneAlpha = 0.4
+	snapshotZoneBeta  = 0.5
+)
+
+// snapshotMaxStale 写者持锁时最多复用多旧的缓存，超过后阻塞等待读锁重算
+const snapshotMaxStale = 50 * time.Millisecond
+
+// derivedMetrics Snapshot 的核心统计与派生指标，全部取自同一把读锁内的同一版本
+type derivedMetrics struct {
+	version  uint64
+	ts       time.Time // 计算时间
+	core     rawCore
+	vwap     float64
+	median   float64
+	momentum float64
+	rv       float64
+	rvOK     bool
+	timeImb  float64
+	bs       BreakoutStrength
+	ez       EquilibriumZone
+	size     float64 // RiskOverlay 的规模上限，未设置时为 0
+	blended  float64 // 最新混合价，未开启混合时为 0
+}
+
+// Version 窗口数据版本号，每次 Add 后递增
+func (w *SlidingWindow) Version() uint64 {
+	return w.version.Load()
+}
+
+// bumpVersionUnlocked 数据变更后递增版本号并发布核心统计（要求持有写锁）
+func (w *SlidingWindow) bumpVersionUnlocked() {
+	w.version.Add(1)
+	w.evalReadyUnlocked()
+	w.recordReplUnlocked()
+	w.publishUnlocked()
+}
+
+// derived 当前版本的派生指标，缓存按指针原子替换。版本未变时直接复用缓存；版本已变而写者正持有锁时
+// 不等待，返回上一个完整版本（版本号、计算时间、核心字段与派生字段同属该版本），下一次调用再重算。
+// 没有缓存或缓存已超过 snapshotMaxStale 时等待写者
+func (w *SlidingWindow) derived() (derivedMetrics, bool) {
+	v := w.version.Load()
+
+	cached := w.cache.Load()
+	if cached != nil && cached.version == v {
+		return *cached, true
+	}
+
+	block := cached == nil || w.now().Sub(cached.ts) >= snapshotMaxStale
+	d, ok, locked := w.computeDerived(block)
+	if !locked {
+		return *cached, true
+	}
+	if !ok {
+		return d, false
+	}
+
+	fresh := false
+	for {
+		old := w.cache.Load()
+		if old != nil && old.version >= d.version {
+			break
+		}
+		if w.cache.CompareAndSwap(old, &d) {
+			fresh = true
+			break
+		}
+	}
+
+	// 每个版本只记一次历史，并发 Snapshot 重复计算时不会重复记录
+	if fresh {
+		w.recordHistory(d)
+		if d.rvOK {
+			w.volRegime.observe(d.rv)
+		}
+	}
+	return d, true
+}
+
+// computeDerived 在同一把读锁内取出版本号、核心统计和各派生指标的输入，排序等计算放在锁外。
+// block 为 false 时只尝试加锁，拿不到锁返回 locked=false
+func (w *SlidingWindow) computeDerived(block bool) (d derivedMetrics, ok, locked bool) {
+	if block {
+		w.mu.RLock()
+	} else if !w.mu.TryRLock() {
+		return d, false, false
+	}
+
+	// 发布区在写锁内更新，持有读锁时与 version 一致
+	d.version = w.version.Load()
+	d.ts = w.now()
+	d.core = w.loadPublished()
+	in, ok := w.gatherZoneUnlocked()
+	if !ok {
+		w.mu.RUnlock()
+		return d, false, true
+	}
+	d.momentum, _ = w.momentumUnlocked()
+	d.rv, d.rvOK = w.realizedVolUnlocked()
+	d.timeImb, _ = w.timeImbalanceUnlocked()
+	histMed, histOK := w.histQuantileUnlocked(0.5)
+	d.blended, _ = w.blendedUnlocked()
+	risk := w.risk
+	vps := QtyLoz(w.volPerSecond.Load()).Float(w.volumeScale) * w.volumeRateConvUnlocked()
+	w.mu.RUnlock()
+
+	defer w.putPricesBuf(in.pb)
+	if in.sumV > 0 {
+		d.vwap = in.sumPV / in.sumV
+	}
+	// breakoutStrength 依赖价格的时间顺序，必须在排序之前
+	d.bs, _ = w.breakoutStrength(WindowStats{Prices: in.prices})
+	sort.Float64s(in.prices)
+	median := quantileSorted(in.prices, 0.5)
+	d.median = median
+	if histOK {
+		d.median = histMed
+	}
+	if in.sumV > 0 {
+		d.ez, _ = zoneFrom(in, d.vwap, median, snapshotZoneAlpha, snapshotZoneBeta)
+	}
+	if risk != nil && d.rvOK && in.sumV > 0 {
+		alpha, beta := risk.zoneParams()
+		if zone, ok := zoneFrom(in, d.vwap, median, alpha, beta); ok {
+			if s, ok := risk.suggest(d.rv, vps, zone); ok {
+				d.size = s.MaxSize
+			}
+		}
+	}
+	return d, true, true
+}
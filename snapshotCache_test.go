@@ -0,0 +1,41 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotCache(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 5; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	v := w.Version()
+	s1 := w.Snapshot()
+	s2 := w.Snapshot()
+	if s1 == nil || s2 == nil || s1.VolumeWeightedAveragePrice != s2.VolumeWeightedAveragePrice || s1.MedianPrice != s2.MedianPrice {
+		t.Fatalf("snapshots differ without writes: %+v %+v", s1, s2)
+	}
+	if w.Version() != v {
+		t.Fatal("Snapshot must not bump the version")
+	}
+	// 同一版本只评估一次，重复 Snapshot 不追加历史
+	if st, ok := w.MetricStats("momentum"); !ok || st.N != 1 {
+		t.Fatalf("history after cached snapshots: %+v %v", st, ok)
+	}
+
+	// 新写入使缓存失效
+	w.AddWindowPoint(SideSell, 110, 5, t0.Add(5*time.Second))
+	if w.Version() == v {
+		t.Fatal("Add should bump the version")
+	}
+	s3 := w.Snapshot()
+	if s3.LatestPrice != 110 || s3.VolumeWeightedAveragePrice <= s1.VolumeWeightedAveragePrice {
+		t.Fatalf("stale snapshot after add: %+v", s3)
+	}
+	if st, _ := w.MetricStats("momentum"); st.N != 2 {
+		t.Fatalf("history n = %d, want 2", st.N)
+	}
+}
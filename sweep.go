@@ -0,0 +1,143 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// Burst 一次聚合下单（同方向、时间上紧挨着的一串成交）
+type Burst struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Side       Side      `json:"side"`
+	Fills      int       `json:"fills"` // 成交笔数，游程合并与压缩后的点按所含笔数计
+	FirstPrice float64   `json:"first_price"`
+	LastPrice  float64   `json:"last_price"`
+	VWAP       float64   `json:"vwap"`
+	Volume     float64   `json:"volume"`
+	// Slippage 首笔到末笔的价格滑移，按方向取符号：买单向上扫、卖单向下扫为正
+	Slippage float64 `json:"slippage"`
+	// Divergence 成交额均价相对首笔价格的偏离（同样按方向取符号），衡量按量加权后的吃单深度
+	Divergence float64 `json:"divergence"`
+}
+
+type SweepStats struct {
+	Bursts         int     `json:"bursts"`
+	MultiFill      int     `json:"multi_fill"` // 至少两笔成交的 burst 数
+	AvgFills       float64 `json:"avg_fills"`
+	AvgSlippage    float64 `json:"avg_slippage"` // 仅统计 multi-fill burst
+	MaxSlippage    float64 `json:"max_slippage"`
+	AvgDivergence  float64 `json:"avg_divergence"`
+	SweepVolumePct float64 `json:"sweep_volume_pct"` // multi-fill burst 成交量占比
+	Last           Burst   `json:"last"`             // 最近一次 multi-fill burst
+}
+
+// SetBurstGap 同方向成交间隔不超过 gap 视为同一笔聚合订单的分笔成交，默认 0（时间戳完全相同）
+func (w *SlidingWindow) SetBurstGap(gap time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.burstGap = gap
+}
+
+// SweepStats 识别窗口内的分笔成交 burst，统计首末成交价差（扫单滑移）和量价偏离（读锁）
+//
+//metric:name=sweep unit=return cost=O(n) min_points=2
+func (w *SlidingWindow) SweepStats() (SweepStats, bool) {
+	if !w.IsReady() {
+		return SweepStats{}, false
+	}
+
+	var st SweepStats
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 2 {
+		return st, false
+	}
+
+	var (
+		cur        Burst
+		curPV      float64
+		totalVol   float64
+		sweepVol   float64
+		totalFills int
+		sumSlip    float64
+		sumDiv     float64
+	)
+
+	flush := func() {
+		if cur.Fills == 0 {
+			return
+		}
+		st.Bursts++
+		totalFills += cur.Fills
+		if cur.Fills < 2 || cur.FirstPrice <= 0 || cur.Volume <= 0 {
+			return
+		}
+
+		sign := 1.0
+		if cur.Side == SideSell {
+			sign = -1
+		}
+		cur.VWAP = curPV / cur.Volume
+		cur.Slippage = sign * (cur.LastPrice - cur.FirstPrice) / cur.FirstPrice
+		cur.Divergence = sign * (cur.VWAP - cur.FirstPrice) / cur.FirstPrice
+
+		st.MultiFill++
+		sumSlip += cur.Slippage
+		sumDiv += cur.Divergence
+		sweepVol += cur.Volume
+		if cur.Slippage > st.MaxSlippage {
+			st.MaxSlippage = cur.Slippage
+		}
+		st.Last = cur
+	}
+
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		px := pt.Price.Float(w.priceScale)
+		v := math.Max(0, pt.Volume.Float(w.volumeScale))
+		totalVol += v
+
+		if pt.Side != SideBuy && pt.Side != SideSell {
+			flush()
+			cur = Burst{}
+			continue
+		}
+
+		if cur.Fills > 0 && pt.Side == cur.Side && pt.Ts.Sub(cur.End) <= w.burstGap {
+			cur.Fills += int(pt.Trades())
+			cur.End = pt.Ts
+			cur.LastPrice = px
+			cur.Volume += v
+			curPV += px * v
+			continue
+		}
+
+		flush()
+		cur = Burst{
+			Start:      pt.Ts,
+			End:        pt.Ts,
+			Side:       pt.Side,
+			Fills:      int(pt.Trades()),
+			FirstPrice: px,
+			LastPrice:  px,
+			Volume:     v,
+		}
+		curPV = px * v
+	}
+	flush()
+
+	if st.Bursts > 0 {
+		st.AvgFills = float64(totalFills) / float64(st.Bursts)
+	}
+	if st.MultiFill > 0 {
+		st.AvgSlippage = sumSlip / float64(st.MultiFill)
+		st.AvgDivergence = sumDiv / float64(st.MultiFill)
+	}
+	if totalVol > 0 {
+		st.SweepVolumePct = sweepVol / totalVol
+	}
+	return st, true
+}
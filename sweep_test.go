@@ -0,0 +1,61 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSweepStats(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+
+	// 买单向上扫三档；单笔卖出；卖单向下扫两档
+	w.AddWindowPoint(SideBuy, 100, 1, at(0))
+	w.AddWindowPoint(SideBuy, 101, 1, at(5))
+	w.AddWindowPoint(SideBuy, 102, 2, at(8))
+	w.AddWindowPoint(SideSell, 102, 1, at(1000))
+	w.AddWindowPoint(SideSell, 102, 1, at(2000))
+	w.AddWindowPoint(SideSell, 100, 3, at(2005))
+
+	if st, ok := w.SweepStats(); !ok || st.MultiFill != 0 || st.Bursts != 6 {
+		t.Fatalf("default gap merges only identical timestamps: %+v", st)
+	}
+
+	w.SetBurstGap(10 * time.Millisecond)
+	st, ok := w.SweepStats()
+	if !ok || st.Bursts != 3 || st.MultiFill != 2 || st.AvgFills != 2 {
+		t.Fatalf("bursts: %+v", st)
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-12 }
+	if !near(st.MaxSlippage, 0.02) || !near(st.SweepVolumePct, 8.0/9) {
+		t.Fatalf("stats: %+v", st)
+	}
+	// 卖单 burst：首笔 102 → 末笔 100，均价 100.5，滑移与偏离都按方向取正
+	last := st.Last
+	if last.Side != SideSell || last.Fills != 2 || !near(last.VWAP, 100.5) ||
+		!near(last.Slippage, 2.0/102) || !near(last.Divergence, 1.5/102) {
+		t.Fatalf("last burst: %+v", last)
+	}
+	if !near(st.AvgDivergence, (1.25/100+1.5/102)/2) {
+		t.Fatalf("avg divergence = %v", st.AvgDivergence)
+	}
+}
+
+func TestSweepStats_CountsMergedTrades(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetRunLength(time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+	// 三笔同价买单合并为一个点，再向上扫一档
+	for i := 0; i < 3; i++ {
+		w.AddWindowPoint(SideBuy, 100, 1, t0)
+	}
+	w.AddWindowPoint(SideBuy, 101, 1, t0)
+	w.AddWindowPoint(SideSell, 100, 1, t0.Add(time.Second))
+
+	st, ok := w.SweepStats()
+	if !ok || st.Bursts != 2 || st.Last.Fills != 4 || st.AvgFills != 2.5 {
+		t.Fatalf("stats: %+v", st)
+	}
+}
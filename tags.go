@@ -0,0 +1,27 @@
+package sliding_window
+
+import "maps"
+
+// WithTags 给窗口附加标签（如 symbol、venue、strategy），构造后不可变。
+// 标签随 Snapshot、归档 K 线、发布事件、expvar 以及慢操作 / 回调 panic 记录一起输出，
+// 下游可以直接按标签路由；这些输出中的 Tags 与窗口共享同一个 map，只读
+func WithTags(tags map[string]string) Option {
+	return func(o *windowOptions) {
+		if len(tags) == 0 {
+			o.tags = nil
+			return
+		}
+		o.tags = maps.Clone(tags)
+	}
+}
+
+// Tags 窗口标签（无锁，只读）
+func (w *SlidingWindow) Tags() map[string]string {
+	return w.tags
+}
+
+// Tag 单个标签的值
+func (w *SlidingWindow) Tag(key string) (string, bool) {
+	v, ok := w.tags[key]
+	return v, ok
+}
@@ -0,0 +1,43 @@
+package sliding_window
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTags(t *testing.T) {
+	src := map[string]string{"symbol": "BTCUSDT", "venue": "binance", "strategy": "mr"}
+	w := New(2*time.Second, 64, WithTags(src))
+	src["strategy"] = "changed" // 构造后修改原 map 不影响窗口
+	if v, _ := w.Tag("strategy"); v != "mr" {
+		t.Fatalf("tag = %q", v)
+	}
+
+	sink := &candleSink{}
+	w.SetArchiver(sink, time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+	for s := 0; s < 6; s++ {
+		w.AddWindowPoint(SideBuy, 100+float64(s), 1, t0.Add(time.Duration(s)*time.Second))
+	}
+
+	snap := w.Snapshot()
+	if snap == nil || snap.Tags["venue"] != "binance" {
+		t.Fatalf("snapshot tags: %+v", snap)
+	}
+	if len(sink.got) == 0 || sink.got[0].Tags["symbol"] != "BTCUSDT" {
+		t.Fatalf("candle tags: %+v", sink.got)
+	}
+	if w.debugVars()["tags"] == nil {
+		t.Fatal("expvar should carry tags")
+	}
+
+	var buf bytes.Buffer
+	if err := NewLineProtocolWriter(&buf, "").Write(SeriesTags{Symbol: "BTC-PERP"}, snap); err != nil {
+		t.Fatal(err)
+	}
+	if line := buf.String(); !strings.HasPrefix(line, "sliding_window,symbol=BTC-PERP,strategy=mr,venue=binance ") {
+		t.Fatalf("line protocol: %s", line)
+	}
+}
@@ -0,0 +1,51 @@
+package sliding_window
+
+// TimeWeightedImbalance 时间加权主动方向失衡（读锁），范围 [-1, 1]。
+// 每笔成交的方向按它“覆盖”的时间（距上一笔成交的开市时长）加权：
+// 一串密集小单合计只覆盖很短的时间，不会像按笔数或按量那样放大失衡。
+//
+//metric:name=time_imbalance unit=ratio cost=O(n) min_points=2
+func (w *SlidingWindow) TimeWeightedImbalance() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+	if w.metricTTLOn() {
+		return cachedMetric(w, "time_imbalance", "time_imbalance", w.computeTimeImbalance)
+	}
+	return w.computeTimeImbalance()
+}
+
+func (w *SlidingWindow) computeTimeImbalance() (float64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.timeImbalanceUnlocked()
+}
+
+func (w *SlidingWindow) timeImbalanceUnlocked() (float64, bool) {
+	if w.size < 2 {
+		return 0, false
+	}
+
+	var signed, total float64
+	prev := w.atUnlocked(0)
+	for i := 1; i < w.size; i++ {
+		cur := w.atUnlocked(i)
+		dt := w.openDurationUnlocked(prev.Ts, cur.Ts).Seconds()
+		prev = cur
+		if dt <= 0 {
+			continue
+		}
+		s := float64(cur.Sign())
+		if s == 0 {
+			continue
+		}
+		signed += s * dt
+		total += dt
+	}
+
+	if total <= 0 {
+		return 0, false
+	}
+	return signed / total, true
+}
@@ -0,0 +1,32 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWeightedImbalance_Burst(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 1024, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+
+	// 每秒一笔主动卖，持续 10 秒
+	for i := 0; i < 10; i++ {
+		w.AddWindowPoint(SideSell, 100, 1, base.Add(time.Duration(i)*time.Second))
+	}
+	// 随后 100 笔密集小买单，合计只覆盖 100ms
+	last := base.Add(9 * time.Second)
+	for i := 1; i <= 100; i++ {
+		w.AddWindowPoint(SideBuy, 100, 0.5, last.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	ti, ok := w.TimeWeightedImbalance()
+	if !ok || ti > -0.9 {
+		t.Fatalf("time imbalance = %v (ok=%v), want strongly negative", ti, ok)
+	}
+	if imb := w.Imbalance(); imb <= 0 {
+		t.Fatalf("volume imbalance = %v, want positive", imb)
+	}
+	if s := w.Snapshot(); s == nil || s.TimeImbalance != ti {
+		t.Fatalf("snapshot time imbalance mismatch: %+v", s)
+	}
+}
@@ -0,0 +1,121 @@
+package sliding_window
+
+import (
+	"sort"
+	"time"
+)
+
+// RevertStats 一个方向上偏离—回归事件的统计
+type RevertStats struct {
+	Episodes int           `json:"episodes"` // 偏离达到 level 的次数
+	Reverted int           `json:"reverted"` // 其中已回到均衡价的次数
+	Mean     time.Duration `json:"mean"`     // 已回归事件的平均回归时长（从首次达到 level 算起）
+	Median   time.Duration `json:"median"`
+	Max      time.Duration `json:"max"`
+}
+
+// TimeToRevertStats 双向的回归时长估计
+type TimeToRevertStats struct {
+	Equilibrium float64       `json:"equilibrium"` // 均衡价（窗口 VWAP）
+	Level       float64       `json:"level"`       // 偏离阈值（相对均衡价的比例）
+	Up          RevertStats   `json:"up"`          // 向上偏离后回落
+	Down        RevertStats   `json:"down"`        // 向下偏离后回升
+	All         RevertStats   `json:"all"`
+	OpenDir     int           `json:"open_dir"` // 尚未回归的当前偏离：+1 向上，-1 向下，0 无
+	OpenFor     time.Duration `json:"open_for"` // 当前偏离已持续的时长
+}
+
+// TimeToRevert 从窗口历史估计价格偏离均衡价 level（比例，比如 0.002 即 0.2%）之后，
+// 通常需要多久回到均衡价（读锁），可用于确定均值回归的持仓时长。
+// 均衡价取整个窗口的 VWAP；未回归的事件只计入 Episodes，不参与时长统计。
+//
+//metric:name=time_to_revert unit=duration cost=O(n) min_points=2
+func (w *SlidingWindow) TimeToRevert(level float64) (TimeToRevertStats, bool) {
+	if !w.IsReady() {
+		return TimeToRevertStats{}, false
+	}
+
+	var out TimeToRevertStats
+	if level <= 0 {
+		return out, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sumV := w.SumV.Load()
+	if w.size < 2 || sumV <= 0 {
+		return out, false
+	}
+
+	// 在 ticks 上比较，避免逐点换算
+	equ := float64(w.SumPV.Load()) / float64(sumV)
+	upper, lower := equ*(1+level), equ*(1-level)
+	out.Equilibrium = equ / float64(w.priceScale)
+	out.Level = level
+
+	var up, down []time.Duration
+	var dir int
+	var start time.Time
+	for i := 0; i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		px := float64(pt.Price.Int64())
+
+		switch dir {
+		case 1:
+			if px <= equ {
+				up = append(up, pt.Ts.Sub(start))
+				dir = 0
+			}
+		case -1:
+			if px >= equ {
+				down = append(down, pt.Ts.Sub(start))
+				dir = 0
+			}
+		}
+		if dir != 0 {
+			continue
+		}
+		switch {
+		case px >= upper:
+			dir, start = 1, pt.Ts
+			out.Up.Episodes++
+		case px <= lower:
+			dir, start = -1, pt.Ts
+			out.Down.Episodes++
+		}
+	}
+
+	if dir != 0 {
+		out.OpenDir = dir
+		out.OpenFor = w.lastUnlocked().Ts.Sub(start)
+	}
+
+	out.Up.fill(up)
+	out.Down.fill(down)
+	out.All.fill(append(up, down...))
+	out.All.Episodes = out.Up.Episodes + out.Down.Episodes
+	return out, true
+}
+
+// fill 由已回归事件的时长填充统计（会原地排序 ds）
+func (s *RevertStats) fill(ds []time.Duration) {
+	s.Reverted = len(ds)
+	if len(ds) == 0 {
+		return
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	n := len(ds)
+	s.Mean = sum / time.Duration(n)
+	s.Max = ds[n-1]
+	if n%2 == 1 {
+		s.Median = ds[n/2]
+	} else {
+		s.Median = (ds[n/2-1] + ds[n/2]) / 2
+	}
+}
@@ -0,0 +1,41 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeToRevert(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 256, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	// 均衡价 100：向上偏离 3s 回归、向下偏离 5s 回归、再向上偏离 1s 回归，最后向下偏离未回归
+	prices := []float64{100, 101, 101, 101, 100, 100, 99, 99, 99, 99, 99, 100, 101, 100, 100, 99}
+	for i, px := range prices {
+		vol := 1.0
+		if px > 100 {
+			vol = 1.5 // 让 VWAP 正好是 100
+		}
+		w.AddWindowPoint(SideBuy, px, vol, t0.Add(time.Duration(i)*time.Second))
+	}
+
+	r, ok := w.TimeToRevert(0.005)
+	if !ok {
+		t.Fatal("not ok")
+	}
+	if r.Up.Episodes != 2 || r.Up.Reverted != 2 || r.Up.Max != 3*time.Second || r.Up.Median != 2*time.Second || r.Equilibrium != 100 {
+		t.Fatalf("up: %+v (equ %v)", r.Up, r.Equilibrium)
+	}
+	if r.Down.Episodes != 2 || r.Down.Reverted != 1 || r.Down.Mean != 5*time.Second {
+		t.Fatalf("down: %+v", r.Down)
+	}
+	if r.All.Episodes != 4 || r.All.Reverted != 3 || r.All.Median != 3*time.Second {
+		t.Fatalf("all: %+v", r.All)
+	}
+	if r.OpenDir != -1 || r.OpenFor != 0 {
+		t.Fatalf("open: %+v", r)
+	}
+	if _, ok := w.TimeToRevert(0); ok {
+		t.Fatal("level 0 should not be ok")
+	}
+}
@@ -0,0 +1,70 @@
+package sliding_window
+
+import (
+	"strings"
+	"time"
+)
+
+// TrackedMetrics 需要在每次 Add 时增量维护的结构（位掩码）。
+// 低优先级 symbol 的窗口可以关掉用不到的部分，降低 Add 开销和内存。
+type TrackedMetrics uint32
+
+const (
+	TrackVolumeEMA    TrackedMetrics = 1 << iota // 成交量 EMA（VolumeFactor、EMA 衰减）
+	TrackArrival                                 // 到达率（ArrivalStats）
+	TrackClockSkew                               // 时钟偏差回归（ClockSkew、偏差修正）
+	TrackTWAP                                    // TWAP 增量累加器（TWAP、ExecutionPressure）
+	TrackNotional                                // 成交额累加器（VolumeInQuote、ExecutionPressure、Breadth.DeltaNotional）
+	TrackBlend                                   // 成交价 / 标记价混合序列
+	TrackImbalanceEMA                            // ImbalanceEMA
+	TrackSizeSketch                              // 单笔成交量直方图（SizeQuantile、大单百分位）
+	TrackExcursion                               // 最大回撤 / 上冲的滑动窗口聚合（MaxDrawdown、MaxRunUp），首次查询后才开始维护
+
+	TrackNone TrackedMetrics = 0
+	TrackAll  TrackedMetrics = TrackVolumeEMA | TrackArrival | TrackClockSkew | TrackTWAP |
+		TrackNotional | TrackBlend | TrackImbalanceEMA | TrackSizeSketch | TrackExcursion
+)
+
+var trackedNames = []struct {
+	bit  TrackedMetrics
+	name string
+}{
+	{TrackVolumeEMA, "volume_ema"},
+	{TrackArrival, "arrival"},
+	{TrackClockSkew, "clock_skew"},
+	{TrackTWAP, "twap"},
+	{TrackNotional, "notional"},
+	{TrackBlend, "blend"},
+	{TrackImbalanceEMA, "imbalance_ema"},
+	{TrackSizeSketch, "size_sketch"},
+	{TrackExcursion, "excursion"},
+}
+
+func (t TrackedMetrics) Has(bit TrackedMetrics) bool { return t&bit == bit }
+
+func (t TrackedMetrics) String() string {
+	if t == TrackNone {
+		return "none"
+	}
+	if t == TrackAll {
+		return "all"
+	}
+	var parts []string
+	for _, n := range trackedNames {
+		if t.Has(n.bit) {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// NewSlidingWindowTracked 与 NewSlidingWindow 相同，但只维护 tracked 中列出的增量结构；
+// 未维护的指标返回 false（或零值）。NewSlidingWindow 等价于 tracked = TrackAll。
+func NewSlidingWindowTracked(duration time.Duration, capacity int, emaAlpha float64, tracked TrackedMetrics) *SlidingWindow {
+	return New(duration, capacity, WithEMAAlpha(emaAlpha), WithTracked(tracked))
+}
+
+// Tracked 当前维护的增量结构
+func (w *SlidingWindow) Tracked() TrackedMetrics {
+	return w.tracked
+}
@@ -0,0 +1,38 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackedMetrics_Disabled(t *testing.T) {
+	w := NewSlidingWindowTracked(time.Minute, 64, 0.1, TrackArrival)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 20; i++ {
+		w.AddWindowPoint(SideBuy, 100+float64(i), 1, base.Add(time.Duration(i)*time.Second))
+	}
+
+	if _, ok := w.TWAP(); ok {
+		t.Fatal("TWAP should be unavailable without TrackTWAP")
+	}
+	if _, ok := w.ImbalanceEMA(0.2); ok {
+		t.Fatal("ImbalanceEMA should be unavailable without TrackImbalanceEMA")
+	}
+	if err := w.SetPriceBlend(0.7, 0.3); err == nil {
+		t.Fatal("SetPriceBlend should fail without TrackBlend")
+	}
+	if err := w.SetContractMultiplier(10, VolumeInQuote); err == nil {
+		t.Fatal("VolumeInQuote should require TrackNotional")
+	}
+	if got := w.Config().Tracked; got != "arrival" {
+		t.Fatalf("config tracked = %q", got)
+	}
+
+	// 核心统计不受影响
+	if cs := w.CoreStats(); cs.Size != 20 || cs.Volume != 20 {
+		t.Fatalf("unexpected core stats %+v", cs)
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+}
@@ -0,0 +1,76 @@
+package sliding_window
+
+import "time"
+
+// tradeIDSet 窗口内已见过的成交 ID 及其时间、价格、方向，按到达顺序遗忘，max > 0 时集合大小有上限。
+// 成交 ID 不存进环形数组，去重（SetTradeDedup）与成交更正（NegativeVolumeCorrect）各用一份
+type tradeIDSet struct {
+	max   int
+	seen  map[string]seenTrade
+	order []seenTrade // 按到达顺序，head 之前已移出
+	head  int
+}
+
+type seenTrade struct {
+	id    string
+	ts    time.Time
+	price QtyLoz
+	side  Side
+}
+
+func newTradeIDSet(max int) tradeIDSet {
+	return tradeIDSet{max: max, seen: make(map[string]seenTrade, max)}
+}
+
+// SetTradeDedup 开启按 TradeMeta.TradeID 去重（写锁），maxIDs <= 0 关闭。
+// 已出窗成交的 ID 随之遗忘，同时最多记住 maxIDs 个 ID（超出时遗忘最早的）；
+// 重连后交易所重放最近成交时，重复的点被忽略并计入 Counters().Duplicates。TradeID 为空的点不参与去重
+func (w *SlidingWindow) SetTradeDedup(maxIDs int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if maxIDs <= 0 {
+		w.dedup = tradeIDSet{}
+		return
+	}
+	w.dedup = newTradeIDSet(maxIDs)
+}
+
+// dedupUnlocked 已见过 tr.TradeID 时计数并返回 true，否则记住该 ID（要求持有写锁）
+func (w *SlidingWindow) dedupUnlocked(tr Trade) bool {
+	d := &w.dedup
+	if _, dup := d.seen[tr.TradeID]; dup {
+		w.counters.duplicates.Add(1)
+		return true
+	}
+	d.remember(tr)
+	return false
+}
+
+func (d *tradeIDSet) remember(tr Trade) {
+	t := seenTrade{id: tr.TradeID, ts: tr.Ts, price: tr.Price, side: tr.Side}
+	d.seen[t.id] = t
+	d.order = append(d.order, t)
+	for d.max > 0 && len(d.order)-d.head > d.max {
+		d.pop()
+	}
+}
+
+// forget 遗忘 Ts <= threshold 的 ID
+func (d *tradeIDSet) forget(threshold time.Time) {
+	for d.head < len(d.order) && !d.order[d.head].ts.After(threshold) {
+		d.pop()
+	}
+}
+
+func (d *tradeIDSet) pop() {
+	delete(d.seen, d.order[d.head].id)
+	d.order[d.head] = seenTrade{}
+	d.head++
+	// 已移出部分过半时压缩，避免底层数组无限增长
+	if d.head > len(d.order)/2 {
+		n := copy(d.order, d.order[d.head:])
+		d.order = d.order[:n]
+		d.head = 0
+	}
+}
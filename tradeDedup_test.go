@@ -0,0 +1,49 @@
+package sliding_window
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTradeDedup(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 256, 0.1)
+	w.SetTradeDedup(100)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	pt := func(i int) Trade {
+		return Trade{
+			WindowPoint: WindowPoint{
+				Ts:     t0.Add(time.Duration(i) * time.Second),
+				Price:  NewQtyLoz(100, w.priceScale),
+				Volume: NewQtyLoz(1, w.volumeScale),
+				Side:   SideBuy,
+			},
+			TradeMeta: TradeMeta{TradeID: strconv.Itoa(i)},
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		w.AddTrades(pt(i))
+	}
+	// 重连重放 2..4，并带上新成交 5、6
+	w.AddTrades(pt(2), pt(3), pt(4), pt(5), pt(6))
+	if got := w.SumVolume(); got != 7 {
+		t.Fatalf("sum volume = %v, want 7", got)
+	}
+	if c := w.Counters(); c.Duplicates != 3 {
+		t.Fatalf("duplicates = %d, want 3", c.Duplicates)
+	}
+
+	// 出窗的 ID 被遗忘：窗口推进到 20s 后，ID "5" 已不在集合里
+	for i := 7; i <= 20; i++ {
+		w.AddTrades(pt(i))
+	}
+	w.mu.RLock()
+	_, seen := w.dedup.seen["5"]
+	n := len(w.dedup.seen)
+	w.mu.RUnlock()
+	if seen || n > 11 {
+		t.Fatalf("expired ids retained: seen=%v n=%d", seen, n)
+	}
+}
@@ -0,0 +1,89 @@
+package sliding_window
+
+import "time"
+
+// TradeMeta 成交的可选元数据。它们不存进环形数组里的点，只在开启对应功能时由窗口的旁路结构保存：
+// RecvTs 用于时钟偏差估计（TrackClockSkew、SetSkewCorrection），Seq 用于 DuplicateSeq 排序，
+// TradeID 用于 SetTradeDedup 去重、NegativeVolumeCorrect 更正与 Backfill 重叠判断
+type TradeMeta struct {
+	RecvTs  time.Time `json:"recv_ts,omitzero"`   // 本地接收时间
+	Seq     uint64    `json:"seq,omitempty"`      // 交易所成交序号
+	TradeID string    `json:"trade_id,omitempty"` // 交易所成交 ID
+}
+
+// Trade 带元数据的成交，AddTrades 与 BackfillTrades 的输入
+type Trade struct {
+	WindowPoint
+	TradeMeta
+}
+
+// tradeIngest AddTrades 拆出点与序号的复用缓冲
+type tradeIngest struct {
+	pts  []WindowPoint
+	seqs []uint64
+}
+
+// AddTrades 添加带元数据的成交并自动清理超出时间窗口的旧点（写锁）。
+// 元数据交给已开启的功能后丢弃，窗口里只保存 WindowPoint；没有开启任何相关功能时等价于 Add
+func (w *SlidingWindow) AddTrades(trades ...Trade) {
+	w.lockWrite()
+	defer w.unlockWrite()
+
+	w.addTradesUnlocked(trades)
+}
+
+// addTradesUnlocked 逐笔处理负成交量、去重、时钟偏差样本，再把点与序号交给写入流程（要求持有写锁）
+func (w *SlidingWindow) addTradesUnlocked(trades []Trade) {
+	if w.size > 0 {
+		threshold := w.thresholdUnlocked(w.lastUnlocked().Ts)
+		w.dedup.forget(threshold)
+		w.negative.trades.forget(threshold)
+	}
+
+	in := &w.tradeIn
+	pts, seqs := in.pts[:0], in.seqs[:0]
+	withSeq := w.dupTs == DuplicateSeq
+	correct := w.negative.policy == NegativeVolumeCorrect
+	corrected := false
+	w.skew.lastRecv = time.Time{}
+	for _, tr := range trades {
+		if tr.Volume < 0 && w.negative.policy != NegativeVolumeClamp {
+			if correct && w.correctTradeUnlocked(tr) {
+				w.counters.corrections.Add(1)
+				corrected = true
+			} else {
+				w.counters.negativeRejects.Add(1)
+			}
+			continue
+		}
+		if tr.TradeID != "" {
+			if w.dedup.max > 0 && w.dedupUnlocked(tr) {
+				continue
+			}
+			if correct {
+				w.negative.trades.remember(tr)
+			}
+		}
+		if w.tracked.Has(TrackClockSkew) {
+			w.skew.observe(tr.Ts, tr.RecvTs)
+		}
+		if tr.RecvTs.After(w.skew.lastRecv) {
+			w.skew.lastRecv = tr.RecvTs
+		}
+
+		pts = append(pts, tr.WindowPoint)
+		if withSeq {
+			seqs = append(seqs, tr.Seq)
+		}
+	}
+	in.pts, in.seqs = pts, seqs
+
+	if corrected {
+		w.finishAddUnlocked(w.thresholdUnlocked(w.lastUnlocked().Ts))
+	}
+	if !withSeq {
+		seqs = nil
+	}
+	w.addPoints(pts, seqs)
+	w.skew.lastRecv = time.Time{}
+}
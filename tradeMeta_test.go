@@ -0,0 +1,62 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddTrades_ClockSkewFollowsWindow(t *testing.T) {
+	w := NewSlidingWindow(10*time.Second, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	for s := 0; s < 30; s++ {
+		ts := t0.Add(time.Duration(s) * time.Second)
+		w.AddTrades(Trade{
+			WindowPoint: WindowPoint{Ts: ts, Price: NewQtyLoz(100, w.priceScale), Volume: NewQtyLoz(1, w.volumeScale), Side: SideBuy},
+			TradeMeta:   TradeMeta{RecvTs: ts.Add(50 * time.Millisecond)},
+		})
+	}
+	// 不带接收时间的点照常写入，只是不计入样本
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(30*time.Second))
+
+	skew, ok := w.ClockSkew()
+	if !ok {
+		t.Fatal("clock skew not ready")
+	}
+	w.mu.RLock()
+	size := w.size
+	w.mu.RUnlock()
+	if skew.Samples != int64(size-1) {
+		t.Fatalf("samples = %d, want %d (points in window with RecvTs)", skew.Samples, size-1)
+	}
+	if d := skew.Offset - 50*time.Millisecond; d < -time.Microsecond || d > time.Microsecond {
+		t.Fatalf("offset = %v, want 50ms", skew.Offset)
+	}
+}
+
+func TestAddTrades_SeqThroughReorder(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	w.SetDuplicateTsPolicy(DuplicateSeq)
+	w.SetReorderBuffer(time.Second)
+	ts := time.Unix(1_700_000_000, 0)
+
+	trade := func(seq uint64) Trade {
+		return Trade{
+			WindowPoint: WindowPoint{Ts: ts, Price: QtyLoz(1000000 + seq*100), Volume: 1e8, Side: SideBuy},
+			TradeMeta:   TradeMeta{Seq: seq},
+		}
+	}
+	w.AddTrades(trade(3))
+	w.AddTrades(trade(1), trade(2))
+	w.Add(WindowPoint{Ts: ts, Price: 1000000, Volume: 1e8, Side: SideBuy}) // 无序号，保持到达顺序
+	if w.ReorderPending() != 4 {
+		t.Fatalf("pending = %d, want 4", w.ReorderPending())
+	}
+	w.FlushReorder()
+
+	for i, want := range []QtyLoz{1000100, 1000200, 1000300, 1000000} {
+		if got := w.at(i).Price; got != want {
+			t.Fatalf("point %d price = %d, want %d", i, got, want)
+		}
+	}
+}
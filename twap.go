@@ -0,0 +1,119 @@
+package sliding_window
+
+// twapAccum TWAP 增量累加器：每个价格持续到下一笔成交，相邻点对在进窗/出窗时加减
+type twapAccum struct {
+	sumPT compensated // Σ price·dt
+	sumT  compensated // Σ dt（秒，只计开市时长）
+}
+
+// TWAP 时间加权均价（读锁）：每个价格持续到下一笔成交，只计开市时长
+//
+//metric:name=twap unit=price cost=O(1) min_points=2
+func (w *SlidingWindow) TWAP() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.twapUnlocked()
+}
+
+func (w *SlidingWindow) twapUnlocked() (float64, bool) {
+	sumPT, sumT := w.twap.sumPT.value(), w.twap.sumT.value()
+	if w.size > 0 && w.runTailTs.After(w.lastUnlocked().Ts) {
+		// 尾部游程内部的时长还没有下一个点来结算
+		p, dt := w.twapLegUnlocked(w.lastUnlocked(), WindowPoint{Ts: w.runTailTs})
+		sumPT += p
+		sumT += dt
+	}
+	if sumT <= 0 {
+		return 0, false
+	}
+	return sumPT / sumT, true
+}
+
+// twapLegUnlocked 点对 (prev, next) 对 TWAP 的贡献：prev 的价格持续 dt 秒
+func (w *SlidingWindow) twapLegUnlocked(prev, next WindowPoint) (pt, dt float64) {
+	dt = w.openDurationUnlocked(prev.Ts, next.Ts).Seconds()
+	if dt <= 0 {
+		return 0, 0
+	}
+	return prev.Price.Float(w.priceScale) * dt, dt
+}
+
+// twapAppendUnlocked 新点进窗前调用（要求持有写锁）
+func (w *SlidingWindow) twapAppendUnlocked(pt WindowPoint) {
+	if w.size == 0 {
+		w.twap = twapAccum{}
+		return
+	}
+	p, dt := w.twapLegUnlocked(w.lastUnlocked(), pt)
+	w.twap.sumPT.add(p)
+	w.twap.sumT.add(dt)
+}
+
+// twapEvictUnlocked 头部点出窗前调用（要求持有写锁）
+func (w *SlidingWindow) twapEvictUnlocked() {
+	if w.size <= 2 {
+		// 出窗后不足一个点对，直接清零，顺便消除浮点累计误差
+		w.twap = twapAccum{}
+		return
+	}
+	p, dt := w.twapLegUnlocked(w.atUnlocked(0), w.atUnlocked(1))
+	w.twap.sumPT.add(-p)
+	w.twap.sumT.add(-dt)
+}
+
+// twapPopTailUnlocked 尾部点移除前调用（要求持有写锁）
+func (w *SlidingWindow) twapPopTailUnlocked() {
+	if w.size <= 2 {
+		w.twap = twapAccum{}
+		return
+	}
+	p, dt := w.twapLegUnlocked(w.atUnlocked(w.size-2), w.lastUnlocked())
+	w.twap.sumPT.add(-p)
+	w.twap.sumT.add(-dt)
+}
+
+// rebuildTWAPUnlocked 全量重算累加器（日历变更后调用，要求持有写锁）
+func (w *SlidingWindow) rebuildTWAPUnlocked() {
+	w.twap = twapAccum{}
+	if !w.tracked.Has(TrackTWAP) {
+		return
+	}
+	for i := 1; i < w.size; i++ {
+		p, dt := w.twapLegUnlocked(w.atUnlocked(i-1), w.atUnlocked(i))
+		w.twap.sumPT.add(p)
+		w.twap.sumT.add(dt)
+	}
+}
+
+// ExecutionPressure (VWAP − TWAP) / TWAP（读锁），两者都来自增量累加器，O(1)。
+// 为正表示成交量集中在较高价位（买方愿意在高位成交），为负则相反。
+//
+//metric:name=execution_pressure unit=ratio cost=O(1) min_points=2
+func (w *SlidingWindow) ExecutionPressure() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.executionPressureUnlocked()
+}
+
+func (w *SlidingWindow) executionPressureUnlocked() (float64, bool) {
+	sumV := w.SumV.Load()
+	if sumV <= 0 || !w.tracked.Has(TrackNotional) {
+		return 0, false
+	}
+	twap, ok := w.twapUnlocked()
+	if !ok || twap == 0 {
+		return 0, false
+	}
+	vwap := w.sumNotional.value() / (float64(sumV) / float64(w.volumeScale))
+	return (vwap - twap) / twap, true
+}
@@ -0,0 +1,36 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTWAP_IncrementalMatchesScan(t *testing.T) {
+	w := NewSlidingWindow(2*time.Second, 128, 0.1)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 1000; i++ {
+		side := SideBuy
+		if i%2 == 0 {
+			side = SideSell
+		}
+		w.AddWindowPoint(side, 100+float64(i%13)*0.25, 1+float64(i%5), base.Add(time.Duration(i*7)*time.Millisecond))
+	}
+
+	got, ok := w.TWAP()
+	if !ok {
+		t.Fatal("TWAP not ok")
+	}
+
+	w.mu.Lock()
+	w.rebuildTWAPUnlocked()
+	want, _ := w.twapUnlocked()
+	w.mu.Unlock()
+
+	if math.Abs(got-want) > 1e-9*want {
+		t.Fatalf("incremental TWAP %v != scan %v", got, want)
+	}
+	if _, ok := w.ExecutionPressure(); !ok {
+		t.Fatal("ExecutionPressure not ok")
+	}
+}
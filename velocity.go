@@ -0,0 +1,69 @@
+package sliding_window
+
+// Velocity 价格变化速度：窗口收益率 / 窗口跨度秒数（读锁）
+//
+//metric:name=velocity unit=return/s cost=O(1) min_points=2
+func (w *SlidingWindow) Velocity() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.velocityUnlocked()
+}
+
+func (w *SlidingWindow) velocityUnlocked() (float64, bool) {
+	if w.size < 2 {
+		return 0, false
+	}
+	oldest, newest := w.atUnlocked(0), w.lastUnlocked()
+	sec := newest.Ts.Sub(oldest.Ts).Seconds()
+	ret, ok := w.structuralReturn()
+	if !ok || sec <= 0 {
+		return 0, false
+	}
+	return ret / sec, true
+}
+
+// Acceleration 价格加速度：后半窗口速度 - 前半窗口速度，再除以半窗口秒数（每秒²收益率）。
+// 中点锚点用二分查找定位，O(log n)（读锁）
+//
+//metric:name=acceleration unit=return/s2 cost=O(logn) min_points=3
+func (w *SlidingWindow) Acceleration() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.size < 3 {
+		return 0, false
+	}
+
+	oldest, newest := w.atUnlocked(0), w.lastUnlocked()
+	half := newest.Ts.Sub(oldest.Ts) / 2
+	halfSec := half.Seconds()
+	if halfSec <= 0 {
+		return 0, false
+	}
+
+	// 中点价格：最后一个 Ts <= mid 的点
+	i := w.searchTsUnlocked(oldest.Ts.Add(half).Add(1)) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	p0 := oldest.Price.Float(w.priceScale)
+	pm := w.atUnlocked(i).Price.Float(w.priceScale)
+	p1 := newest.Price.Float(w.priceScale)
+	if p0 <= 0 || pm <= 0 {
+		return 0, false
+	}
+
+	v1 := (pm - p0) / p0 / halfSec
+	v2 := (p1 - pm) / pm / halfSec
+	return (v2 - v1) / halfSec, true
+}
@@ -0,0 +1,46 @@
+package sliding_window
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestVelocityAcceleration(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+
+	w.AddWindowPoint(SideBuy, 100, 1, t0)
+	if _, ok := w.Velocity(); ok {
+		t.Fatal("velocity needs two points")
+	}
+	// 同一时间戳：跨度为 0
+	w.AddWindowPoint(SideBuy, 101, 1, t0)
+	if _, ok := w.Velocity(); ok {
+		t.Fatal("zero span should not report velocity")
+	}
+
+	// 前半窗口持平，后半窗口上涨 10%
+	w = NewSlidingWindow(time.Minute, 64, 0.1)
+	w.AddWindowPoint(SideBuy, 100, 1, t0)
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(5*time.Second))
+	w.AddWindowPoint(SideBuy, 110, 1, t0.Add(10*time.Second))
+
+	v, ok := w.Velocity()
+	if !ok || math.Abs(v-0.01) > 1e-12 {
+		t.Fatalf("velocity = %v, %v", v, ok)
+	}
+	a, ok := w.Acceleration()
+	if !ok || math.Abs(a-0.004) > 1e-12 {
+		t.Fatalf("acceleration = %v, %v", a, ok)
+	}
+
+	// 先涨后平：速度为正，加速度为负
+	w = NewSlidingWindow(time.Minute, 64, 0.1)
+	w.AddWindowPoint(SideBuy, 100, 1, t0)
+	w.AddWindowPoint(SideBuy, 110, 1, t0.Add(5*time.Second))
+	w.AddWindowPoint(SideBuy, 110, 1, t0.Add(10*time.Second))
+	if a, ok := w.Acceleration(); !ok || a >= 0 {
+		t.Fatalf("decelerating acceleration = %v, %v", a, ok)
+	}
+}
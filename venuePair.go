@@ -0,0 +1,131 @@
+package sliding_window
+
+import (
+	"math"
+	"time"
+)
+
+// VenueCompareConfig CompareVenues 的参数，零值字段取默认值
+type VenueCompareConfig struct {
+	MaxLag        time.Duration // 领先滞后的搜索范围，默认 5s；网格步长 = MaxLag / 10
+	LeadThreshold time.Duration // 领先超过该时长给出 LeadSignal，默认 500ms
+	MinCorr       float64       // 领先判断要求的最小相关性（绝对值），默认 0.3
+	ZAlert        float64       // 价差或失衡差 z-score 超过该值给出 Alert，默认 3
+}
+
+func (c VenueCompareConfig) withDefaults() VenueCompareConfig {
+	if c.MaxLag <= 0 {
+		c.MaxLag = 5 * time.Second
+	}
+	if c.LeadThreshold <= 0 {
+		c.LeadThreshold = 500 * time.Millisecond
+	}
+	if c.MinCorr <= 0 {
+		c.MinCorr = 0.3
+	}
+	if c.ZAlert <= 0 {
+		c.ZAlert = 3
+	}
+	return c
+}
+
+// VenuePair 同一 symbol 在两个交易所的窗口对比（a 相对 b）
+type VenuePair struct {
+	PriceDiffBps  float64       `json:"price_diff_bps"` // 最新网格点 log(a/b)，单位 bp
+	PriceZ        float64       `json:"price_z"`        // 最新价差相对重叠时段价差序列的 z-score
+	ImbalanceDiff float64       `json:"imbalance_diff"` // 整个窗口的失衡差 a - b
+	ImbalanceZ    float64       `json:"imbalance_z"`    // 最新网格区间失衡差相对各区间的 z-score
+	Leader        int           `json:"leader"`         // 1: a 领先；-1: b 领先；0: 同步或无法判断
+	Lead          time.Duration `json:"lead"`           // 领先时长（绝对值）
+	LeadCorr      float64       `json:"lead_corr"`
+	LeadSignal    bool          `json:"lead_signal"` // 领先超过 LeadThreshold 且相关性足够
+	Alert         bool          `json:"alert"`       // 价差或失衡差 z-score 超过 ZAlert
+}
+
+// CompareVenues 对比同一 symbol 在两个交易所的窗口：价格与失衡的偏离（含 z-score），
+// 以及一方领先超过阈值时的信号，用于套利 / 延迟监控。
+// 单个交易所相对合成视图的偏离见 ConsolidatedWindow.Divergences
+func CompareVenues(a, b *SlidingWindow, cfg VenueCompareConfig) (VenuePair, bool) {
+	var res VenuePair
+	cfg = cfg.withDefaults()
+
+	step := cfg.MaxLag / leadLagSteps
+	start, n, ok := commonGrid(a, b, step)
+	if !ok || n < 2 {
+		return res, false
+	}
+
+	pa, pb := a.resampleOn(start, step, n), b.resampleOn(start, step, n)
+	spread := make([]float64, 0, n)
+	for g := range pa {
+		if pa[g] > 0 && pb[g] > 0 {
+			spread = append(spread, logRatio(pa[g], pb[g])*1e4)
+		}
+	}
+	if len(spread) == 0 {
+		return res, false
+	}
+	res.PriceDiffBps = spread[len(spread)-1]
+	res.PriceZ = lastZ(spread)
+
+	ia, ib := a.resampleImbalanceOn(start, step, n), b.resampleImbalanceOn(start, step, n)
+	diff := make([]float64, n)
+	for g := range diff {
+		diff[g] = ia[g] - ib[g]
+	}
+	res.ImbalanceDiff = a.Imbalance() - b.Imbalance()
+	res.ImbalanceZ = lastZ(diff)
+
+	if ll, ok := LeadLag(a, b, cfg.MaxLag); ok {
+		res.Leader = ll.Leader
+		res.Lead = ll.BestLag.Abs()
+		res.LeadCorr = ll.BestCorr
+		res.LeadSignal = res.Lead >= cfg.LeadThreshold && math.Abs(ll.BestCorr) >= cfg.MinCorr
+	}
+	res.Alert = math.Abs(res.PriceZ) >= cfg.ZAlert || math.Abs(res.ImbalanceZ) >= cfg.ZAlert
+	return res, true
+}
+
+// resampleImbalanceOn 网格各区间 [start+g·step, start+(g+1)·step) 内的买卖失衡（读锁），无成交的区间为 0
+func (w *SlidingWindow) resampleImbalanceOn(start time.Time, step time.Duration, n int) []float64 {
+	buy := make([]float64, n)
+	sell := make([]float64, n)
+
+	w.mu.RLock()
+	for i := w.searchTsUnlocked(start); i < w.size; i++ {
+		pt := w.atUnlocked(i)
+		g := int(pt.Ts.Sub(start) / step)
+		if g >= n {
+			break
+		}
+		v := max(pt.Volume.Float(w.volumeScale), 0)
+		switch pt.Side {
+		case SideBuy:
+			buy[g] += v
+		case SideSell:
+			sell[g] += v
+		}
+	}
+	w.mu.RUnlock()
+
+	for g := range buy {
+		buy[g] = imbalanceOfFloat(buy[g], sell[g])
+	}
+	return buy
+}
+
+// lastZ 最后一个值相对整个序列的 z-score，序列无波动时为 0
+func lastZ(xs []float64) float64 {
+	var sum, sumSq float64
+	for _, x := range xs {
+		sum += x
+		sumSq += x * x
+	}
+	n := float64(len(xs))
+	mean := sum / n
+	sd := math.Sqrt(max(sumSq/n-mean*mean, 0))
+	if sd <= 1e-12 {
+		return 0
+	}
+	return (xs[len(xs)-1] - mean) / sd
+}
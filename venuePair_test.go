@@ -0,0 +1,51 @@
+package sliding_window
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCompareVenues(t *testing.T) {
+	a := NewSlidingWindow(time.Minute, 8192, 0.1)
+	b := NewSlidingWindow(time.Minute, 8192, 0.1)
+
+	r := rand.New(rand.NewSource(11))
+	base := time.Unix(1_700_000_000, 0)
+	const lag = 80 // b 比 a 晚 800ms
+
+	prices := make([]float64, 3000)
+	px := 100.0
+	for i := range prices {
+		px += r.NormFloat64() * 0.05
+		prices[i] = px
+	}
+	for i := range prices {
+		ts := base.Add(time.Duration(i) * 10 * time.Millisecond)
+		side := SideBuy
+		if r.Intn(2) == 0 {
+			side = SideSell
+		}
+		a.AddWindowPoint(side, prices[i], 1, ts)
+		b.AddWindowPoint(side, prices[max(i-lag, 0)], 1, ts)
+	}
+
+	// 最后 200ms：a 突然拉升，b 还没跟上
+	end := base.Add(30 * time.Second)
+	for i := 0; i < 20; i++ {
+		ts := end.Add(time.Duration(i) * 10 * time.Millisecond)
+		a.AddWindowPoint(SideBuy, px+2, 5, ts)
+		b.AddWindowPoint(SideSell, prices[len(prices)-lag+i], 1, ts)
+	}
+
+	res, ok := CompareVenues(a, b, VenueCompareConfig{MaxLag: 2 * time.Second})
+	if !ok {
+		t.Fatal("comparison should be computable")
+	}
+	if res.Leader != 1 || res.Lead != 800*time.Millisecond || !res.LeadSignal {
+		t.Fatalf("expected a to lead by 800ms: %+v", res)
+	}
+	if res.PriceDiffBps <= 0 || res.PriceZ < 3 || res.ImbalanceDiff <= 0 || !res.Alert {
+		t.Fatalf("expected divergence alert: %+v", res)
+	}
+}
@@ -0,0 +1,71 @@
+package sliding_window
+
+import (
+	"math"
+	"sync"
+)
+
+// defaultVolRegimeAlpha realized vol 基准 EMA 的默认平滑系数
+const defaultVolRegimeAlpha = 0.05
+
+// volRegime 跨快照的 realized vol 基准：每个新版本数据的首次 Snapshot 更新一次
+type volRegime struct {
+	mu  sync.Mutex
+	ema EMA
+}
+
+func (r *volRegime) observe(rv float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ema.Alpha == 0 {
+		r.ema.Alpha = defaultVolRegimeAlpha
+	}
+	r.ema.Update(rv)
+}
+
+func (r *volRegime) baseline() (float64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ema.Get()
+}
+
+// SetVolRegimeAlpha 设置 realized vol 基准 EMA 的平滑系数并清空基准（0 < alpha <= 1，默认 0.05）
+func (w *SlidingWindow) SetVolRegimeAlpha(alpha float64) {
+	r := &w.volRegime
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ema = *NewEMA(alpha)
+}
+
+// VolRegimeBaseline 跨快照的 realized vol 基准（EMA），还没有快照时返回 false
+func (w *SlidingWindow) VolRegimeBaseline() (float64, bool) {
+	return w.volRegime.baseline()
+}
+
+// VolRegimeFactor 当前 realized vol / 跨快照基准，类似 VolumeFactor 之于成交量：
+// 明显大于 1 表示进入高波动状态，可用于波动突破类触发。基准随 Snapshot 更新，
+// 还没有快照或基准为 0 时返回 false
+//
+//metric:name=vol_regime_factor unit=ratio cost=O(n) min_points=2
+func (w *SlidingWindow) VolRegimeFactor() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
+	base, ok := w.volRegime.baseline()
+	if !ok || base <= 0 {
+		return 0, false
+	}
+	rv, ok := w.RealizedVol()
+	if !ok {
+		return 0, false
+	}
+	f := rv / base
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
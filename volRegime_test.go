@@ -0,0 +1,45 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVolRegimeFactor(t *testing.T) {
+	w := NewSlidingWindow(2*time.Second, 256, 0.1)
+	w.SetVolRegimeAlpha(0.2)
+	t0 := time.Unix(1700000000, 0)
+
+	if _, ok := w.VolRegimeFactor(); ok {
+		t.Fatal("no baseline before any snapshot")
+	}
+
+	// 平静期：价格小幅摆动
+	ts := t0
+	for i := 0; i < 200; i++ {
+		px := 100.0
+		if i%2 == 1 {
+			px = 100.01
+		}
+		ts = ts.Add(100 * time.Millisecond)
+		w.AddWindowPoint(SideBuy, px, 1, ts)
+		w.Snapshot()
+	}
+	calm, ok := w.VolRegimeFactor()
+	if !ok || calm < 0.9 || calm > 1.1 {
+		t.Fatalf("calm factor = %v (ok=%v)", calm, ok)
+	}
+
+	// 波动放大 20 倍
+	for i := 0; i < 20; i++ {
+		px := 100.0
+		if i%2 == 1 {
+			px = 100.2
+		}
+		ts = ts.Add(100 * time.Millisecond)
+		w.AddWindowPoint(SideBuy, px, 1, ts)
+	}
+	if f, _ := w.VolRegimeFactor(); f < 3 {
+		t.Fatalf("volatile factor = %v, want well above 1", f)
+	}
+}
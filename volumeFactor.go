@@ -5,14 +5,14 @@ import "math"
 // 无锁版计算交易量基准（要求调用方已持有 RLock 或 Lock）
 func (w *SlidingWindow) volumeFactor() (float64, bool) {
 	// 1) baseline：EMA 基准（单位：真实 volume float）
-	baselineVol, ok := w.ema.Get()
+	baselineVol, ok := w.volumeBaselineUnlocked()
 	if !ok || baselineVol <= 0 {
 		return 0, false
 	}
 
-	// 2) 当前窗口检查
-	sz := w.size
-	if sz <= 0 {
+	// 2) 当前窗口检查：按成交笔数平均（聚合点包含多笔），与按笔更新的 EMA 口径一致
+	trades := w.nTrades.Load()
+	if w.size <= 0 || trades <= 0 {
 		return 0, false
 	}
 
@@ -22,8 +22,8 @@ func (w *SlidingWindow) volumeFactor() (float64, bool) {
 		return 0, false
 	}
 
-	avgUnitsPerPoint := float64(sumUnits) / float64(sz)  // 仍是 units
-	currAvg := avgUnitsPerPoint / float64(w.volumeScale) // 转成真实 volume（只做一次除法）
+	avgUnitsPerPoint := float64(sumUnits) / float64(trades) // 仍是 units
+	currAvg := avgUnitsPerPoint / float64(w.volumeScale)    // 转成真实 volume（只做一次除法）
 
 	if currAvg <= 0 {
 		return 0, false
@@ -37,7 +37,13 @@ func (w *SlidingWindow) volumeFactor() (float64, bool) {
 }
 
 // VolumeFactor 带锁计算交易量基准
+//
+//metric:name=volume_factor unit=ratio cost=O(1) min_points=1
 func (w *SlidingWindow) VolumeFactor() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
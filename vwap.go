@@ -1,12 +1,24 @@
 package sliding_window
 
 // VolumeWeightedAveragePrice 计算VWAP价格（复用窗口快照）
+//
+//metric:name=vwap unit=price cost=O(n) min_points=2
 func (w *SlidingWindow) VolumeWeightedAveragePrice() (float64, bool) {
+	if !w.IsReady() {
+		return 0, false
+	}
+	if w.metricTTLOn() {
+		return cachedMetric(w, "vwap", "vwap", w.computeVWAP)
+	}
+	return w.computeVWAP()
+}
 
+func (w *SlidingWindow) computeVWAP() (float64, bool) {
 	stats, ok := w.collectStats()
 	if !ok {
 		return 0, false
 	}
+	defer w.releaseStats(&stats)
 
 	return w.vwap(stats)
 }
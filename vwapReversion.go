@@ -0,0 +1,106 @@
+package sliding_window
+
+import "math"
+
+// reversionHorizonDiv 短周期收益率的时间尺度 = 窗口长度 / reversionHorizonDiv
+const reversionHorizonDiv = 12
+
+type VWAPReversionSignal struct {
+	Fired     bool    // 本次调用是否触发入场
+	Side      Side    // 入场方向：价格在 VWAP 上方做空（SideSell），下方做多（SideBuy）
+	Deviation float64 // (price - vwap) / sigma
+	VWAP      float64
+	Sigma     float64 // 成交量加权价格标准差
+	Price     float64
+	ShortRet  float64 // 短周期收益率
+	State     GateState
+}
+
+// VWAPReversion 价格偏离 VWAP 超过 k 个 VWAP-σ 后开始回落（短周期收益率反向）时给出一次入场信号。
+// 状态由窗口内的 SignalGate 维护：同一次偏离只触发一次，价格回到 VWAP 另一侧后复位。
+//
+//metric:name=vwap_reversion unit=signal cost=O(n) min_points=2
+func (w *SlidingWindow) VWAPReversion(k float64) (VWAPReversionSignal, bool) {
+	if !w.IsReady() {
+		return VWAPReversionSignal{}, false
+	}
+
+	var sig VWAPReversionSignal
+
+	w.mu.RLock()
+	if w.size < 2 {
+		w.mu.RUnlock()
+		return sig, false
+	}
+
+	n := w.size
+	newest := w.lastUnlocked()
+	horizonStart := newest.Ts.Add(-w.windowDurationUnlocked() / reversionHorizonDiv)
+
+	var sumPV, sumV float64
+	refPx := w.atUnlocked(0).Price.Float(w.priceScale)
+	for i := 0; i < n; i++ {
+		pt := w.atUnlocked(i)
+		px := pt.Price.Float(w.priceScale)
+		v := pt.Volume.Float(w.volumeScale)
+		sumPV += px * v
+		sumV += v
+		if !pt.Ts.After(horizonStart) {
+			refPx = px
+		}
+	}
+	if sumV <= 0 {
+		w.mu.RUnlock()
+		return sig, false
+	}
+	vwap := sumPV / sumV
+
+	// 第二遍：成交量加权方差
+	var sumVar float64
+	for i := 0; i < n; i++ {
+		pt := w.atUnlocked(i)
+		d := pt.Price.Float(w.priceScale) - vwap
+		sumVar += d * d * pt.Volume.Float(w.volumeScale)
+	}
+	price := newest.Price.Float(w.priceScale)
+	ts := newest.Ts
+	w.mu.RUnlock()
+
+	sigma := math.Sqrt(sumVar / sumV)
+	if sigma <= 1e-12 || refPx <= 0 {
+		return sig, false
+	}
+
+	dev := (price - vwap) / sigma
+	shortRet := (price - refPx) / refPx
+
+	dir := 0
+	if dev > 0 {
+		dir = 1
+	} else if dev < 0 {
+		dir = -1
+	}
+
+	arm := math.Abs(dev) > k
+	// 确认：短周期收益率与偏离方向相反，说明开始回归
+	confirm := shortRet*float64(dir) < 0
+
+	sig.Fired = w.reversionGate.Step(arm, dir, confirm, ts)
+	sig.Deviation = dev
+	sig.VWAP = vwap
+	sig.Sigma = sigma
+	sig.Price = price
+	sig.ShortRet = shortRet
+	sig.State = w.reversionGate.State()
+	if dir > 0 {
+		sig.Side = SideSell
+	} else if dir < 0 {
+		sig.Side = SideBuy
+	}
+	return sig, true
+}
+
+// ResetVWAPReversion 复位 VWAPReversion 的信号闸门
+func (w *SlidingWindow) ResetVWAPReversion() {
+	w.reversionGate.Reset()
+}
@@ -0,0 +1,51 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVWAPReversion(t *testing.T) {
+	// 窗口 120s，短周期 = 10s
+	w := NewSlidingWindow(120*time.Second, 512, 0.1)
+	t0 := time.Unix(1_700_000_000, 0)
+	add := func(s int, px float64) VWAPReversionSignal {
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(s)*time.Second))
+		sig, ok := w.VWAPReversion(2)
+		if !ok && s >= 100 { // 价格走平时 σ 为 0，没有信号
+			t.Fatalf("t=%d: no signal", s)
+		}
+		return sig
+	}
+
+	for s := 0; s < 100; s++ {
+		add(s, 100)
+	}
+	// 急拉到 VWAP 上方：偏离超过 2σ，但短周期仍在上涨，只 arm 不触发
+	var sig VWAPReversionSignal
+	for s := 100; s < 105; s++ {
+		sig = add(s, 110)
+	}
+	if sig.Fired || sig.State != GateArmed || sig.Deviation <= 2 {
+		t.Fatalf("spike: %+v", sig)
+	}
+
+	// 开始回落：触发一次做空
+	sig = add(112, 108)
+	if !sig.Fired || sig.Side != SideSell || sig.ShortRet >= 0 {
+		t.Fatalf("reversal: %+v", sig)
+	}
+	// 同一次偏离不重复触发
+	if sig = add(113, 107.5); sig.Fired || sig.State != GateFired {
+		t.Fatalf("repeat: %+v", sig)
+	}
+
+	// 价格穿到 VWAP 另一侧后闸门复位
+	if sig = add(114, 95); sig.Fired || sig.State == GateFired || sig.Side != SideBuy {
+		t.Fatalf("cross: %+v", sig)
+	}
+	w.ResetVWAPReversion()
+	if sig, _ = w.VWAPReversion(1e9); sig.State != GateIdle {
+		t.Fatalf("reset: %+v", sig)
+	}
+}
@@ -0,0 +1,182 @@
+package sliding_window
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// 只依赖标准库的最小 WebSocket（RFC 6455）服务端实现，供 Broadcaster 使用：
+// 服务端只发送不分片的文本帧；客户端帧必须带掩码、不分片、RSV 位为 0，支持 close / ping，
+// 不满足时以 1002（协议错误）关闭。
+
+const (
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	wsOpCont  = 0x0
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+
+	// wsMaxClientFrame 客户端消息（订阅指令）的最大长度
+	wsMaxClientFrame = 64 << 10
+
+	wsCloseProtocolError = 1002
+)
+
+var (
+	errWSClosed   = errors.New("websocket closed by peer")
+	errWSProtocol = errors.New("websocket protocol error")
+)
+
+// wsSameOrigin 默认 Origin 校验：非浏览器客户端（无 Origin）放行，否则 Origin 的 host 必须与请求 Host 一致
+func wsSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// wsCloseCode close 帧负载：2 字节状态码
+func wsCloseCode(code uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, code)
+}
+
+// wsUpgrade 完成握手并接管连接
+func wsUpgrade(rw http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+	if r.Method != http.MethodGet ||
+		!strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") {
+		http.Error(rw, "websocket upgrade required", http.StatusBadRequest)
+		return nil, nil, errors.New("not a websocket upgrade")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(rw, "unsupported websocket version", http.StatusBadRequest)
+		return nil, nil, errors.New("bad websocket handshake")
+	}
+
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		http.Error(rw, "hijacking not supported", http.StatusInternalServerError)
+		return nil, nil, errors.New("response writer cannot hijack")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := sha1.Sum([]byte(key + wsGUID))
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(h[:]) + "\r\n\r\n"
+	if _, err := brw.WriteString(resp); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, brw, nil
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsWriteFrame 写一个不分片、不带掩码的服务端帧
+func wsWriteFrame(w *bufio.Writer, op byte, payload []byte) error {
+	var hdr [10]byte
+	hdr[0] = 0x80 | op
+	n := len(payload)
+	hl := 2
+	switch {
+	case n < 126:
+		hdr[1] = byte(n)
+	case n <= 0xFFFF:
+		hdr[1] = 126
+		binary.BigEndian.PutUint16(hdr[2:], uint16(n))
+		hl = 4
+	default:
+		hdr[1] = 127
+		binary.BigEndian.PutUint64(hdr[2:], uint64(n))
+		hl = 10
+	}
+	if _, err := w.Write(hdr[:hl]); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// wsReadFrame 读一个客户端帧并去掉掩码
+func wsReadFrame(r *bufio.Reader) (op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	op = hdr[0] & 0x0F
+	if hdr[0]&0x70 != 0 {
+		return 0, nil, fmt.Errorf("%w: reserved bits set", errWSProtocol)
+	}
+	if hdr[0]&0x80 == 0 || op == wsOpCont {
+		return 0, nil, fmt.Errorf("%w: fragmented frames not supported", errWSProtocol)
+	}
+	masked := hdr[1]&0x80 != 0
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > wsMaxClientFrame {
+		return 0, nil, errors.New("websocket frame too large")
+	}
+	if !masked {
+		return 0, nil, errors.New("client frame must be masked")
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
@@ -0,0 +1,156 @@
+package wgtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	sw "github.com/simonks2016/sliding_window"
+)
+
+// Scenario 分阶段的行情剧本，可以用 JSON 描述并提交到仓库，作为可复现的测试样本：
+//
+//	{
+//	  "seed": 7,
+//	  "price": 100,
+//	  "phases": [
+//	    {"name": "calm",  "duration": "2m",  "volatility": 0.0003, "rate": 10},
+//	    {"name": "crash", "duration": "15s", "drift": -0.06, "volatility": 0.001, "imbalance": -0.8, "rate": 80, "volume": 4}
+//	  ]
+//	}
+//
+// 同一剧本（含 seed）永远生成同一序列。
+type Scenario struct {
+	Seed   uint64    `json:"seed"`
+	Start  time.Time `json:"start,omitzero"` // 默认 2024-01-01 00:00:00 UTC
+	Price  float64   `json:"price"`          // 初始价格，默认 100
+	Volume float64   `json:"volume"`         // 基准单笔成交量，默认 1
+	Phases []Phase   `json:"phases"`
+}
+
+// Phase 剧本中的一个阶段
+type Phase struct {
+	Name       string   `json:"name"`
+	Duration   Duration `json:"duration"`
+	Drift      float64  `json:"drift"`      // 整个阶段的期望涨跌幅（比例），如 -0.05
+	Volatility float64  `json:"volatility"` // 单笔对数收益率的标准差
+	Imbalance  float64  `json:"imbalance"`  // 主动买卖失衡 [-1, 1]，主动买概率为 (1+imbalance)/2
+	Rate       float64  `json:"rate"`       // 平均每秒成交笔数（泊松到达），默认 10
+	Volume     float64  `json:"volume"`     // 相对基准的成交量倍数，默认 1
+}
+
+// Duration JSON 中写作 "1m30s" 形式的字符串，也接受以秒为单位的数字
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var sec float64
+		if err := json.Unmarshal(b, &sec); err != nil {
+			return fmt.Errorf("wgtest: duration must be a string or seconds, got %s", b)
+		}
+		*d = Duration(sec * float64(time.Second))
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("wgtest: %w", err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ParseScenario 解析并校验 JSON 剧本
+func ParseScenario(data []byte) (Scenario, error) {
+	var sc Scenario
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return Scenario{}, fmt.Errorf("wgtest: parse scenario: %w", err)
+	}
+	if err := sc.Validate(); err != nil {
+		return Scenario{}, err
+	}
+	return sc, nil
+}
+
+// LoadScenario 从文件读取剧本
+func LoadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	return ParseScenario(data)
+}
+
+// Validate 检查剧本参数是否合法
+func (sc Scenario) Validate() error {
+	if len(sc.Phases) == 0 {
+		return errors.New("wgtest: scenario has no phases")
+	}
+	for i, ph := range sc.Phases {
+		switch {
+		case ph.Duration <= 0:
+			return fmt.Errorf("wgtest: phase %d (%s): duration must be positive", i, ph.Name)
+		case ph.Drift <= -1:
+			return fmt.Errorf("wgtest: phase %d (%s): drift must be > -1, got %v", i, ph.Name, ph.Drift)
+		case ph.Volatility < 0 || ph.Rate < 0 || ph.Volume < 0:
+			return fmt.Errorf("wgtest: phase %d (%s): volatility, rate and volume must not be negative", i, ph.Name)
+		case ph.Imbalance < -1 || ph.Imbalance > 1:
+			return fmt.Errorf("wgtest: phase %d (%s): imbalance must be in [-1, 1], got %v", i, ph.Name, ph.Imbalance)
+		}
+	}
+	return nil
+}
+
+// Points 按剧本生成成交序列；价格精度与 NewSlidingWindow 默认值一致
+func (sc Scenario) Points() ([]sw.WindowPoint, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	g := newGen(Config{Seed: sc.Seed, Start: sc.Start, Price: sc.Price, Volume: sc.Volume})
+
+	ts := g.cfg.Start
+	px := g.cfg.Price
+	for _, ph := range sc.Phases {
+		rate := ph.Rate
+		if rate <= 0 {
+			rate = 10
+		}
+		mult := ph.Volume
+		if mult <= 0 {
+			mult = 1
+		}
+		span := time.Duration(ph.Duration)
+		// 把整段漂移平摊到期望成交笔数上
+		drift := math.Log1p(ph.Drift) / math.Max(rate*span.Seconds(), 1)
+		pBuy := (1 + ph.Imbalance) / 2
+
+		end := ts.Add(span)
+		for {
+			ts = ts.Add(time.Duration(g.rng.ExpFloat64() / rate * float64(time.Second)))
+			if !ts.Before(end) {
+				break
+			}
+			px *= math.Exp(drift + ph.Volatility*g.rng.NormFloat64())
+			g.emitAt(ts, px, g.jitter(g.cfg.Volume*mult), g.side(pBuy))
+		}
+		ts = end
+	}
+	return g.out, nil
+}
+
+// Replay 生成剧本序列并逐笔写入窗口
+func Replay(w *sw.SlidingWindow, sc Scenario) error {
+	pts, err := sc.Points()
+	if err != nil {
+		return err
+	}
+	Feed(w, pts)
+	return nil
+}
@@ -0,0 +1,52 @@
+package wgtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sw "github.com/simonks2016/sliding_window"
+)
+
+const crashScenario = `{
+  "seed": 7,
+  "phases": [
+    {"name": "calm",  "duration": "60s", "volatility": 0.0002, "rate": 20},
+    {"name": "crash", "duration": 10,    "drift": -0.05, "volatility": 0.0005, "imbalance": -0.9, "rate": 50, "volume": 3}
+  ]
+}`
+
+func TestScenarioReplay(t *testing.T) {
+	sc, err := ParseScenario([]byte(crashScenario))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := sc.Points()
+	b, _ := sc.Points()
+	if len(a) == 0 || !reflect.DeepEqual(a, b) {
+		t.Fatal("scenario should be deterministic")
+	}
+	if end := sc.Phases[1].Duration; time.Duration(end) != 10*time.Second {
+		t.Fatalf("numeric duration = %v", time.Duration(end))
+	}
+
+	w := sw.NewSlidingWindow(10*time.Second, 4096, 0.1)
+	if err := Replay(w, sc); err != nil {
+		t.Fatal(err)
+	}
+	if w.Imbalance() > -0.5 {
+		t.Fatalf("crash phase should be sell dominated, imbalance=%v", w.Imbalance())
+	}
+	if last := a[len(a)-1].Price.Float(sw.NewQtyScaleFromDecimals(4)); last > 99 {
+		t.Fatalf("crash should end lower, last=%v", last)
+	}
+}
+
+func TestScenarioValidate(t *testing.T) {
+	if _, err := ParseScenario([]byte(`{"phases":[{"duration":"1s","imbalance":2}]}`)); err == nil {
+		t.Fatal("imbalance out of range should fail")
+	}
+	if _, err := ParseScenario([]byte(`{"phases":[]}`)); err == nil {
+		t.Fatal("empty scenario should fail")
+	}
+}
@@ -0,0 +1,177 @@
+// Package wgtest 提供确定性的成交序列生成器，便于对窗口指标阈值做可复现的单元测试。
+//
+// 所有生成器只依赖 Config.Seed，同一配置永远产生同一序列。
+package wgtest
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	sw "github.com/simonks2016/sliding_window"
+)
+
+// Config 生成器公共参数，零值字段使用默认值
+type Config struct {
+	Seed        uint64
+	Start       time.Time     // 默认 2024-01-01 00:00:00 UTC
+	Step        time.Duration // 相邻成交间隔，默认 100ms
+	N           int           // 成交笔数，默认 600
+	Price       float64       // 初始价格，默认 100
+	Volume      float64       // 基准单笔成交量，默认 1
+	PriceScale  sw.QtyScale   // 默认 4 位小数（与 NewSlidingWindow 一致）
+	VolumeScale sw.QtyScale   // 默认 8 位小数
+}
+
+func (c Config) withDefaults() Config {
+	if c.Start.IsZero() {
+		c.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if c.Step <= 0 {
+		c.Step = 100 * time.Millisecond
+	}
+	if c.N <= 0 {
+		c.N = 600
+	}
+	if c.Price <= 0 {
+		c.Price = 100
+	}
+	if c.Volume <= 0 {
+		c.Volume = 1
+	}
+	if c.PriceScale == 0 {
+		c.PriceScale = sw.NewQtyScaleFromDecimals(4)
+	}
+	if c.VolumeScale == 0 {
+		c.VolumeScale = sw.NewQtyScaleFromDecimals(8)
+	}
+	return c
+}
+
+// gen 生成过程的公共状态
+type gen struct {
+	cfg Config
+	rng *rand.Rand
+	out []sw.WindowPoint
+}
+
+func newGen(cfg Config) *gen {
+	cfg = cfg.withDefaults()
+	return &gen{
+		cfg: cfg,
+		rng: rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15)),
+		out: make([]sw.WindowPoint, 0, cfg.N),
+	}
+}
+
+func (g *gen) emit(i int, price, vol float64, side sw.Side) {
+	g.emitAt(g.cfg.Start.Add(time.Duration(i)*g.cfg.Step), price, vol, side)
+}
+
+func (g *gen) emitAt(ts time.Time, price, vol float64, side sw.Side) {
+	g.out = append(g.out, sw.WindowPoint{
+		Ts:     ts,
+		Price:  sw.NewQtyLoz(price, g.cfg.PriceScale),
+		Volume: sw.NewQtyLoz(vol, g.cfg.VolumeScale),
+		Side:   side,
+	})
+}
+
+// side 以 pBuy 的概率返回买
+func (g *gen) side(pBuy float64) sw.Side {
+	if g.rng.Float64() < pBuy {
+		return sw.SideBuy
+	}
+	return sw.SideSell
+}
+
+// jitter 成交量在 [0.5, 1.5) 倍之间随机
+func (g *gen) jitter(v float64) float64 {
+	return v * (0.5 + g.rng.Float64())
+}
+
+// chopPrice 围绕 anchor 的均值回复随机游走
+func (g *gen) chopPrice(px, anchor float64) float64 {
+	px += (anchor-px)*0.2 + anchor*0.0005*g.rng.NormFloat64()
+	return math.Max(px, anchor*0.5)
+}
+
+// TrendUp 价格每笔平均上涨 drift（比例，<=0 时取 0.0002），成交量从 1 倍线性放大到 3 倍，约 70% 为主动买
+func TrendUp(cfg Config, drift float64) []sw.WindowPoint {
+	if drift <= 0 {
+		drift = 0.0002
+	}
+	g := newGen(cfg)
+	px := g.cfg.Price
+	for i := 0; i < g.cfg.N; i++ {
+		px *= 1 + drift + drift*0.5*g.rng.NormFloat64()
+		ramp := 1 + 2*float64(i)/float64(g.cfg.N)
+		g.emit(i, px, g.jitter(g.cfg.Volume*ramp), g.side(0.7))
+	}
+	return g.out
+}
+
+// Chop 围绕初始价格的窄幅震荡，买卖各半
+func Chop(cfg Config) []sw.WindowPoint {
+	g := newGen(cfg)
+	px := g.cfg.Price
+	for i := 0; i < g.cfg.N; i++ {
+		px = g.chopPrice(px, g.cfg.Price)
+		g.emit(i, px, g.jitter(g.cfg.Volume), g.side(0.5))
+	}
+	return g.out
+}
+
+// WhalePrint 震荡行情中在第 at 笔插入一笔 mult 倍基准量的大单（side 方向）
+func WhalePrint(cfg Config, at int, mult float64, side sw.Side) []sw.WindowPoint {
+	pts := Chop(cfg)
+	if at < 0 || at >= len(pts) {
+		return pts
+	}
+	c := cfg.withDefaults()
+	pts[at].Volume = sw.NewQtyLoz(c.Volume*mult, c.VolumeScale)
+	pts[at].Side = side
+	return pts
+}
+
+// FlashCrash 震荡行情中从第 at 笔开始，在 10 笔内以密集卖单下跌 depth（比例），
+// 随后 20 笔内回补一半跌幅，其余时间恢复震荡
+func FlashCrash(cfg Config, at int, depth float64) []sw.WindowPoint {
+	const (
+		crashLen   = 10
+		recoverLen = 20
+	)
+	if depth <= 0 || depth >= 1 {
+		depth = 0.05
+	}
+	g := newGen(cfg)
+	anchor := g.cfg.Price
+	px := anchor
+	bottom := anchor * (1 - depth)
+	for i := 0; i < g.cfg.N; i++ {
+		switch {
+		case i < at:
+			px = g.chopPrice(px, anchor)
+			g.emit(i, px, g.jitter(g.cfg.Volume), g.side(0.5))
+		case i < at+crashLen:
+			k := float64(i-at+1) / crashLen
+			px = anchor - (anchor-bottom)*k
+			g.emit(i, px, g.jitter(g.cfg.Volume*5), g.side(0.05))
+		case i < at+crashLen+recoverLen:
+			k := float64(i-at-crashLen+1) / recoverLen
+			px = bottom + (anchor-bottom)*0.5*k
+			g.emit(i, px, g.jitter(g.cfg.Volume*2), g.side(0.75))
+		default:
+			px = g.chopPrice(px, bottom+(anchor-bottom)*0.5)
+			g.emit(i, px, g.jitter(g.cfg.Volume), g.side(0.5))
+		}
+	}
+	return g.out
+}
+
+// Feed 按顺序逐笔写入窗口
+func Feed(w *sw.SlidingWindow, pts []sw.WindowPoint) {
+	for _, pt := range pts {
+		w.Add(pt)
+	}
+}
@@ -0,0 +1,38 @@
+package wgtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sw "github.com/simonks2016/sliding_window"
+)
+
+func TestDeterministic(t *testing.T) {
+	cfg := Config{Seed: 42}
+	if !reflect.DeepEqual(TrendUp(cfg, 0), TrendUp(cfg, 0)) {
+		t.Fatal("same seed should produce the same series")
+	}
+	if reflect.DeepEqual(Chop(cfg), Chop(Config{Seed: 43})) {
+		t.Fatal("different seeds should differ")
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	w := sw.NewSlidingWindow(time.Minute, 4096, 0.1)
+	Feed(w, TrendUp(Config{Seed: 1}, 0))
+	if d := w.DeltaVolume(); d <= 0 {
+		t.Fatalf("trend up should be buy dominated, delta=%v", d)
+	}
+
+	pts := FlashCrash(Config{Seed: 1}, 300, 0.1)
+	lo := pts[0].Price
+	for _, p := range pts {
+		if p.Price < lo {
+			lo = p.Price
+		}
+	}
+	if got := lo.Float(sw.NewQtyScaleFromDecimals(4)); got > 90.01 {
+		t.Fatalf("flash crash bottom = %v, want <= 90", got)
+	}
+}
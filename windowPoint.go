@@ -2,11 +2,55 @@ package sliding_window
 
 import "time"
 
+// WindowPoint 窗口内的一个点，按值存放在环形数组中，大小为 48 字节（64 位平台）。
+// Count 落在 Side 之后的对齐填充里，不占额外空间；接收时间、成交序号、成交 ID 等可选元数据
+// 不进环形数组，通过 AddTrades 传入，只在开启对应功能时保存在旁路结构里（见 TradeMeta）。
+// 百万点级别的窗口请用 WithSegments 按需分配，或用 WithEvictionPolicy 限制点数
 type WindowPoint struct {
 	Ts     time.Time `json:"ts"`
 	Price  QtyLoz    `json:"price"`
 	Volume QtyLoz    `json:"volume"`
 	Side   Side      `json:"side"`
+	Count  uint32    `json:"count,omitempty"` // 游程编码合并的成交笔数，0 表示单笔
+}
+
+// --- 值接收者访问器（可内联，不触发逃逸） ---
+
+// PriceFloat 按价格精度还原真实价格
+func (p WindowPoint) PriceFloat(scale QtyScale) float64 { return p.Price.Float(scale) }
+
+// VolumeFloat 按数量精度还原真实成交量
+func (p WindowPoint) VolumeFloat(scale QtyScale) float64 { return p.Volume.Float(scale) }
+
+// Notional 成交额 price * volume（真实值）
+func (p WindowPoint) Notional(priceScale, volumeScale QtyScale) float64 {
+	return p.Price.Float(priceScale) * p.Volume.Float(volumeScale)
+}
+
+// UnixMilli 时间戳（毫秒）
+func (p WindowPoint) UnixMilli() int64 { return p.Ts.UnixMilli() }
+
+// Trades 该点代表的成交笔数（游程编码时可能大于 1）
+func (p WindowPoint) Trades() int64 {
+	if p.Count == 0 {
+		return 1
+	}
+	return int64(p.Count)
+}
+
+func (p WindowPoint) IsBuy() bool  { return p.Side == SideBuy }
+func (p WindowPoint) IsSell() bool { return p.Side == SideSell }
+
+// Sign 买 +1，卖 -1，未知 0
+func (p WindowPoint) Sign() int {
+	switch p.Side {
+	case SideBuy:
+		return 1
+	case SideSell:
+		return -1
+	default:
+		return 0
+	}
 }
 
 type Side uint8
@@ -0,0 +1,47 @@
+package sliding_window
+
+// ZeroVolumePolicy 零成交量点的处理方式
+type ZeroVolumePolicy uint8
+
+const (
+	// ZeroVolumeTrade 零量点按普通成交计数（默认，兼容旧行为）
+	ZeroVolumeTrade ZeroVolumePolicy = iota
+	// ZeroVolumeQuote 零量点视为报价/标记价更新：参与最新价、高低点等价格统计，
+	// 不计入成交笔数、成交量相关统计（每点均量、成交量 EMA、到达率、成交量分布）
+	ZeroVolumeQuote
+)
+
+func (p ZeroVolumePolicy) String() string {
+	switch p {
+	case ZeroVolumeQuote:
+		return "quote"
+	default:
+		return "trade"
+	}
+}
+
+// SetZeroVolumePolicy 设置零量点的处理方式（写锁），窗口内已有的点按新策略重新计入统计
+func (w *SlidingWindow) SetZeroVolumePolicy(p ZeroVolumePolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if p == w.zeroVol {
+		return
+	}
+	for i := 0; i < w.size; i++ {
+		w.applyRemovePointUnlocked(w.atUnlocked(i))
+	}
+	w.zeroVol = p
+	for i := 0; i < w.size; i++ {
+		w.applyAddPointUnlocked(w.atUnlocked(i))
+	}
+	w.hiLoDirty = true
+	w.recomputeHighLowIfDirtyUnlocked()
+	w.refreshVolumeCachesUnlocked()
+	w.bumpVersionUnlocked()
+}
+
+// isQuoteUnlocked 按当前策略该点是否只是报价更新（要求持有锁）
+func (w *SlidingWindow) isQuoteUnlocked(pt WindowPoint) bool {
+	return w.zeroVol == ZeroVolumeQuote && pt.Volume == 0
+}
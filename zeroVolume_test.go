@@ -0,0 +1,43 @@
+package sliding_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZeroVolumeQuote(t *testing.T) {
+	w := NewSlidingWindow(time.Minute, 64, 0.1)
+	t0 := time.Unix(1700000000, 0)
+	feed := func() {
+		w.AddWindowPoint(SideBuy, 100, 2, t0)
+		w.AddWindowPoint(SideUnknown, 105, 0, t0.Add(time.Second))
+		w.AddWindowPoint(SideSell, 101, 4, t0.Add(2*time.Second))
+	}
+	feed()
+	if n := w.nTrades.Load(); n != 3 {
+		t.Fatalf("default policy counts zero-volume points, nTrades=%d", n)
+	}
+
+	w.SetZeroVolumePolicy(ZeroVolumeQuote)
+	if n := w.nTrades.Load(); n != 2 {
+		t.Fatalf("quote policy nTrades=%d, want 2", n)
+	}
+	if avg := w.AvgVolumePerPoint(); avg != 3 {
+		t.Fatalf("avg per point = %v, want 3", avg)
+	}
+	if hi := QtyLoz(w.HighestPrice.Load()).Float(w.priceScale); hi != 105 {
+		t.Fatalf("quote should count for high, got %v", hi)
+	}
+	if issues := w.Check(); len(issues) != 0 {
+		t.Fatalf("check: %v", issues)
+	}
+
+	// 报价出窗后统计保持一致
+	w.AddWindowPoint(SideBuy, 100, 1, t0.Add(90*time.Second))
+	if n := w.nTrades.Load(); n != 1 || !w.Healthy() {
+		t.Fatalf("after eviction nTrades=%d issues=%v", n, w.Check())
+	}
+	if w.Config().ZeroVolume != "quote" {
+		t.Fatal("config should record the policy")
+	}
+}
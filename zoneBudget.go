@@ -0,0 +1,131 @@
+package sliding_window
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// Precision 带时间预算的计算实际采用的精度
+type Precision uint8
+
+const (
+	PrecisionExact   Precision = iota // 全量精确计算
+	PrecisionSampled                  // 中位数由抽样估计，其余输入精确
+	PrecisionO1                       // 只用增量统计，中位数以 TWAP（无则 VWAP）代替
+)
+
+func (p Precision) String() string {
+	switch p {
+	case PrecisionSampled:
+		return "sampled"
+	case PrecisionO1:
+		return "o1"
+	default:
+		return "exact"
+	}
+}
+
+// Reduced 是否低于精确计算
+func (p Precision) Reduced() bool { return p != PrecisionExact }
+
+// 粗略的单点耗时估计，用于判断预算够不够
+const (
+	budgetScanNs = 10 // 遍历取值（含锁内访问）
+	budgetSortNs = 4  // 排序每次比较
+)
+
+// estimateSortCost 遍历并排序 n 个点的预计耗时
+func estimateSortCost(n int) time.Duration {
+	if n < 2 {
+		return 0
+	}
+	f := float64(n)
+	return time.Duration(f*budgetScanNs + f*math.Log2(f)*budgetSortNs)
+}
+
+// EquilibriumZoneWithBudget 带时间预算的 EquilibriumZone：按 ctx 的截止时间选择精度。
+// 预算足够（或 ctx 没有截止时间）时与 EquilibriumZone 完全一致；不够时中位数改为抽样估计
+// （样本量取 SetSampling 的 SampleSize），再不够时只用 O(1) 的增量统计。
+// 降级时 VWAP、首尾价仍然精确，高低点取增量维护的 HighestPrice / LowestPrice，返回的 Precision 表明是否降级
+func (w *SlidingWindow) EquilibriumZoneWithBudget(ctx context.Context, alpha, beta float64) (EquilibriumZone, Precision, bool) {
+	if !w.IsReady() {
+		return EquilibriumZone{}, PrecisionExact, false
+	}
+
+	budget := time.Duration(math.MaxInt64)
+	if dl, ok := ctx.Deadline(); ok {
+		budget = time.Until(dl)
+	}
+	if ctx.Err() != nil {
+		budget = 0
+	}
+
+	w.mu.RLock()
+	n, k := w.size, w.sampling.SampleSize
+	w.mu.RUnlock()
+
+	switch {
+	case estimateSortCost(n) <= budget:
+		z, ok := w.EquilibriumZone(alpha, beta)
+		return z, PrecisionExact, ok
+	case k < n && estimateSortCost(k) <= budget:
+		z, ok := w.sampledZone(alpha, beta, k)
+		return z, PrecisionSampled, ok
+	default:
+		z, ok := w.incrementalZone(alpha, beta)
+		return z, PrecisionO1, ok
+	}
+}
+
+// incrementalInputsUnlocked 由增量统计得到均衡区的精确输入（不含价格序列，要求持有读锁）
+func (w *SlidingWindow) incrementalInputsUnlocked() (zoneInputs, float64, bool) {
+	var in zoneInputs
+	sumV := w.SumV.Load()
+	if w.size < 2 || sumV <= 0 {
+		return in, 0, false
+	}
+	ps := float64(w.priceScale)
+	in.high = float64(w.HighestPrice.Load()) / ps
+	in.low = float64(w.LowestPrice.Load()) / ps
+	in.oldest = w.atUnlocked(0).Price.Float(w.priceScale)
+	in.newest = w.zonePriceUnlocked()
+	vwap := float64(w.SumPV.Load()) / float64(sumV) / ps
+	return in, vwap, true
+}
+
+// sampledZone 中位数取 k 个随机点的中位数（读锁）
+func (w *SlidingWindow) sampledZone(alpha, beta float64, k int) (EquilibriumZone, bool) {
+	w.mu.RLock()
+	in, vwap, ok := w.incrementalInputsUnlocked()
+	if !ok {
+		w.mu.RUnlock()
+		return EquilibriumZone{}, false
+	}
+	prices, pb := w.getPricesBuf(k)
+	defer w.putPricesBuf(pb)
+	r := w.sampleRandUnlocked()
+	for i := range prices {
+		prices[i] = w.atUnlocked(r.IntN(w.size)).Price.Float(w.priceScale)
+	}
+	w.mu.RUnlock()
+
+	sort.Float64s(prices)
+	return zoneFrom(in, vwap, quantileSorted(prices, 0.5), alpha, beta)
+}
+
+// incrementalZone 只用 O(1) 统计：中位数以 TWAP 代替，没有 TWAP 时退化为 VWAP（读锁）
+func (w *SlidingWindow) incrementalZone(alpha, beta float64) (EquilibriumZone, bool) {
+	w.mu.RLock()
+	in, vwap, ok := w.incrementalInputsUnlocked()
+	mid, okT := w.twapUnlocked()
+	w.mu.RUnlock()
+	if !ok {
+		return EquilibriumZone{}, false
+	}
+	if !okT {
+		mid = vwap
+	}
+	return zoneFrom(in, vwap, mid, alpha, beta)
+}
@@ -0,0 +1,41 @@
+package sliding_window
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEquilibriumZoneWithBudget(t *testing.T) {
+	w := NewSlidingWindow(time.Hour, 8192, 0.1)
+	w.SetSampling(SamplingConfig{SampleSize: 512})
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 5000; i++ {
+		px := 100 + math.Sin(float64(i)/50)
+		w.AddWindowPoint(SideBuy, px, 1, t0.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	exact, _ := w.EquilibriumZone(0.4, 0.5)
+
+	z, p, ok := w.EquilibriumZoneWithBudget(context.Background(), 0.4, 0.5)
+	if !ok || p != PrecisionExact || z != exact {
+		t.Fatalf("no deadline: %v %+v", p, z)
+	}
+
+	// 预算只够排序样本
+	ctx, cancel := context.WithTimeout(context.Background(), estimateSortCost(2048))
+	defer cancel()
+	z, p, ok = w.EquilibriumZoneWithBudget(ctx, 0.4, 0.5)
+	if !ok || p != PrecisionSampled || math.Abs(z.EquPrice-exact.EquPrice) > 0.1 {
+		t.Fatalf("sampled: %v %+v vs %+v", p, z, exact)
+	}
+
+	// 已超时：只用增量统计
+	done, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	z, p, ok = w.EquilibriumZoneWithBudget(done, 0.4, 0.5)
+	if !ok || !p.Reduced() || p != PrecisionO1 || z.BandWidth != exact.BandWidth {
+		t.Fatalf("o1: %v %+v vs %+v", p, z, exact)
+	}
+}